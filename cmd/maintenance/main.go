@@ -104,6 +104,11 @@ func cleanupState(ageDays int, force bool, verbose bool, logger *log.Logger) err
 			return nil
 		}
 
+		// Never remove a live SQLite state database
+		if strings.HasSuffix(path, ".db") || strings.HasSuffix(path, ".db-wal") || strings.HasSuffix(path, ".db-shm") {
+			return nil
+		}
+
 		if info.ModTime().Before(cutoff) {
 			if verbose {
 				logger.Printf("Removing old state file: %s (modified: %s)", path, info.ModTime().Format("2006-01-02"))
@@ -6,34 +6,67 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/yourusername/sam-gov-monitor/internal/config"
 	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
 )
 
 const (
-	DefaultConfigPath = "config/queries.yaml"
-	DefaultStateFile  = "state/monitor.json"
-	DefaultLookback   = 3 // days
-	Version           = "1.0.0"
-	BuildDate         = "2025-01-29"
+	DefaultConfigPath   = "config/queries.yaml"
+	DefaultStateFile    = "state/monitor.json"
+	DefaultLookback     = 3 // days
+	DefaultCacheDir     = "state/cache"
+	DefaultCalendarFile = "state/deadlines.ics"
+	DefaultMetricsFile  = "state/metrics.json"
+	Version             = "1.0.0"
+	BuildDate           = "2025-01-29"
 )
 
 func main() {
+	// The track subcommand has its own flags
+	if len(os.Args) > 1 && os.Args[1] == "track" {
+		if err := runTrack(os.Args[2:]); err != nil {
+			log.Fatalf("track: %v", err)
+		}
+		return
+	}
+
 	var (
-		configPath  = flag.String("config", DefaultConfigPath, "Path to config file")
-		stateFile   = flag.String("state", DefaultStateFile, "Path to state file")
-		dryRun      = flag.Bool("dry-run", false, "Run without sending notifications")
-		verbose     = flag.Bool("v", false, "Verbose output")
-		validateEnv = flag.Bool("validate-env", false, "Validate environment and exit")
-		showHelp    = flag.Bool("help", false, "Show help")
-		lookback    = flag.Int("lookback", DefaultLookback, "Days to look back for opportunities")
-		showVersion = flag.Bool("version", false, "Show version information")
-		reportMode  = flag.Bool("report", false, "Generate status report from state file")
-		debugEmail  = flag.Bool("debug-email", false, "Send test email every run")
+		configPath      = flag.String("config", DefaultConfigPath, "Path to config file")
+		stateFile       = flag.String("state", DefaultStateFile, "Path to state file")
+		stateBackend    = flag.String("state-backend", monitor.StateBackendJSON, "State storage backend: json or sqlite")
+		dryRun          = flag.Bool("dry-run", false, "Run without sending notifications")
+		verbose         = flag.Bool("v", false, "Verbose output")
+		validateEnv     = flag.Bool("validate-env", false, "Validate environment and exit")
+		showHelp        = flag.Bool("help", false, "Show help")
+		lookback        = flag.Int("lookback", DefaultLookback, "Days to look back for opportunities")
+		showVersion     = flag.Bool("version", false, "Show version information")
+		reportMode      = flag.Bool("report", false, "Generate status report from state file")
+		debugEmail      = flag.Bool("debug-email", false, "Send test email every run")
+		noCache         = flag.Bool("no-cache", false, "Disable the search response cache")
+		cacheDir        = flag.String("cache-dir", DefaultCacheDir, "Directory for cached search responses")
+		cacheTTL        = flag.Duration("cache-ttl", monitor.DefaultCacheTTL, "How long cached search responses are reused")
+		daemon          = flag.Bool("daemon", false, "Keep running and execute queries on their schedules")
+		flushDigest     = flag.Bool("flush-digest", false, "Send queued digest notifications now and exit")
+		calendarFile    = flag.String("calendar", DefaultCalendarFile, "Deadline calendar file rewritten after each run (empty to disable)")
+		calendarAddr    = flag.String("calendar-addr", "", "Serve the deadline calendar over HTTP at this address in daemon mode, e.g. 127.0.0.1:8080")
+		showOutbox      = flag.Bool("outbox", false, "List failed and pending notification deliveries and exit")
+		replay          = flag.String("replay", "", "Resend failed deliveries and exit: 'all' or comma-separated delivery IDs")
+		serveAddr       = flag.String("serve", "", "Serve the read-only query API and dashboard at this address, e.g. 127.0.0.1:8080")
+		servePublic     = flag.Bool("serve-public", false, "Allow -serve and -calendar-addr on addresses other than loopback; neither has authentication")
+		showAwards      = flag.Bool("awards", false, "Print who won the recorded awards per agency and NAICS code and exit")
+		metricsFile     = flag.String("metrics-file", DefaultMetricsFile, "File the run metrics are kept in across runs (empty to keep them in memory)")
+		metricsTextfile = flag.String("metrics-textfile", "", "Prometheus text file rewritten after each run, for the node_exporter textfile collector")
 	)
 	flag.Parse()
 
@@ -48,12 +81,38 @@ func main() {
 	}
 
 	if *reportMode {
-		if err := generateReport(*stateFile); err != nil {
+		if err := generateReport(*stateFile, *stateBackend); err != nil {
 			log.Fatalf("Failed to generate report: %v", err)
 		}
 		return
 	}
 
+	if *showOutbox {
+		if err := listDeliveries(*stateFile, *stateBackend); err != nil {
+			log.Fatalf("Failed to list deliveries: %v", err)
+		}
+		return
+	}
+
+	if *showAwards {
+		if err := generateAwardReport(*stateFile, *stateBackend); err != nil {
+			log.Fatalf("Failed to generate award report: %v", err)
+		}
+		return
+	}
+
+	if err := checkListenAddrs(*serveAddr, *calendarAddr, *servePublic); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Without -daemon, -serve only reads the state and needs no API key
+	if *serveAddr != "" && !*daemon {
+		if err := serveState(*stateFile, *stateBackend, *metricsFile, *serveAddr, *verbose); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
+		return
+	}
+
 	// Setup logging
 	if *verbose {
 		log.SetFlags(log.LstdFlags | log.Lshortfile)
@@ -83,6 +142,10 @@ func main() {
 		return
 	}
 
+	if *calendarAddr != "" && !*daemon {
+		log.Fatalf("-calendar-addr requires -daemon")
+	}
+
 	// Load configuration
 	cfg, err := config.Load(*configPath)
 	if err != nil {
@@ -96,20 +159,59 @@ func main() {
 
 	// Create monitor
 	m, err := monitor.New(monitor.Options{
-		APIKey:       apiKey,
-		Config:       cfg,
-		StateFile:    *stateFile,
-		Verbose:      *verbose,
-		DryRun:       *dryRun,
-		LookbackDays: *lookback,
-		DebugEmail:   *debugEmail,
+		APIKey:          apiKey,
+		Config:          cfg,
+		StateFile:       *stateFile,
+		StateBackend:    *stateBackend,
+		Verbose:         *verbose,
+		DryRun:          *dryRun,
+		LookbackDays:    *lookback,
+		DebugEmail:      *debugEmail,
+		CacheDir:        *cacheDir,
+		CacheTTL:        *cacheTTL,
+		NoCache:         *noCache,
+		CalendarFile:    *calendarFile,
+		MetricsFile:     *metricsFile,
+		MetricsTextfile: *metricsTextfile,
 	})
 	if err != nil {
 		log.Fatalf("Failed to create monitor: %v", err)
 	}
+	defer m.Close()
+
+	if *flushDigest {
+		ctx, cancel := context.WithTimeout(context.Background(), monitor.DefaultRunTimeout)
+		defer cancel()
+		if err := m.FlushDigest(ctx); err != nil {
+			log.Fatalf("Failed to send digest: %v", err)
+		}
+		return
+	}
+
+	if *replay != "" {
+		var ids []string
+		if *replay != "all" {
+			for _, id := range strings.Split(*replay, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					ids = append(ids, id)
+				}
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), monitor.DefaultRunTimeout)
+		defer cancel()
+		if err := m.ReplayDeliveries(ctx, ids); err != nil {
+			log.Fatalf("Failed to replay deliveries: %v", err)
+		}
+		return
+	}
+
+	if *daemon {
+		runDaemon(m, *verbose, *calendarAddr, *serveAddr)
+		return
+	}
 
 	// Run monitoring
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), monitor.DefaultRunTimeout)
 	defer cancel()
 
 	if *verbose {
@@ -123,6 +225,171 @@ func main() {
 	log.Printf("Monitor completed successfully")
 }
 
+// runDaemon runs scheduled queries until SIGINT or SIGTERM, serving the
+// deadline calendar over HTTP when calendarAddr is set and the query API and
+// dashboard when serveAddr is set
+func runDaemon(m *monitor.Monitor, verbose bool, calendarAddr, serveAddr string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	scheduler, err := monitor.NewScheduler(m, monitor.DefaultRunTimeout, verbose)
+	if err != nil {
+		log.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	if calendarAddr != "" {
+		server, err := serveCalendar(m, calendarAddr)
+		if err != nil {
+			log.Fatalf("Calendar server failed: %v", err)
+		}
+		defer server.Close()
+	}
+
+	if serveAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/deadlines.ics", m.CalendarHandler())
+		mux.Handle("/metrics", m.MetricsHandler())
+		mux.Handle("/", m.APIHandler())
+		server, err := startServer(serveAddr, mux, "API server")
+		if err != nil {
+			log.Fatalf("API server failed: %v", err)
+		}
+		defer server.Close()
+		log.Printf("Serving dashboard at http://%s/, deadline calendar at http://%s/deadlines.ics and metrics at http://%s/metrics", serveAddr, serveAddr, serveAddr)
+	}
+
+	// Restore default signal handling once shutdown starts so a second
+	// signal stops the process without waiting for the current run
+	go func() {
+		<-ctx.Done()
+		stop()
+		log.Printf("Shutdown requested, waiting for the current run to finish")
+	}()
+
+	if err := scheduler.Run(ctx); err != nil {
+		log.Fatalf("Daemon failed: %v", err)
+	}
+
+	log.Printf("Daemon stopped")
+}
+
+// serveCalendar starts an HTTP server for the deadline calendar feed
+func serveCalendar(m *monitor.Monitor, addr string) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/deadlines.ics", m.CalendarHandler())
+
+	server, err := startServer(addr, mux, "Calendar server")
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Serving deadline calendar at http://%s/deadlines.ics", addr)
+	return server, nil
+}
+
+// startServer listens on addr and serves handler in the background. Listen
+// errors, such as an address already in use, are returned.
+func startServer(addr string, handler http.Handler, name string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("%s failed: %v", name, err)
+		}
+	}()
+	return server, nil
+}
+
+// checkListenAddrs rejects -serve and -calendar-addr addresses reachable
+// from other machines unless public is set, because neither server has
+// authentication, and -serve and -calendar-addr addresses that would share
+// a port
+func checkListenAddrs(serveAddr, calendarAddr string, public bool) error {
+	if serveAddr != "" && !public && !isLoopbackAddr(serveAddr) {
+		return fmt.Errorf("-serve %s is reachable from other machines and the API has no authentication; listen on 127.0.0.1 or add -serve-public", serveAddr)
+	}
+	if calendarAddr != "" && !public && !isLoopbackAddr(calendarAddr) {
+		return fmt.Errorf("-calendar-addr %s is reachable from other machines and the calendar has no authentication; listen on 127.0.0.1 or add -serve-public", calendarAddr)
+	}
+	if serveAddr != "" && calendarAddr != "" && sharePort(serveAddr, calendarAddr) {
+		return fmt.Errorf("-serve %s and -calendar-addr %s use the same port; -serve already serves /deadlines.ics", serveAddr, calendarAddr)
+	}
+	return nil
+}
+
+// isLoopbackAddr reports whether addr only listens on this machine
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sharePort reports whether listening on both addresses would clash: the
+// ports match and the hosts match or either covers every interface
+func sharePort(a, b string) bool {
+	hostA, portA, errA := net.SplitHostPort(a)
+	hostB, portB, errB := net.SplitHostPort(b)
+	if errA != nil || errB != nil || portA != portB {
+		return false
+	}
+	anyHost := func(host string) bool {
+		ip := net.ParseIP(host)
+		return host == "" || (ip != nil && ip.IsUnspecified())
+	}
+	return hostA == hostB || anyHost(hostA) || anyHost(hostB)
+}
+
+// serveState serves the query API and dashboard over the state file, and the
+// metrics saved in metricsFile, until SIGINT or SIGTERM. It never writes
+// either, so it can run alongside a monitor process updating them.
+func serveState(stateFile, backend, metricsFile, addr string, verbose bool) error {
+	store, err := monitor.OpenReadOnlyStore(backend, stateFile, verbose)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", monitor.NewAPIServer(store, verbose).Handler())
+	if metricsFile != "" {
+		mux.Handle("/metrics", monitor.MetricsFileHandler(metricsFile))
+	}
+
+	server, err := startServer(addr, mux, "API server")
+	if err != nil {
+		return err
+	}
+	log.Printf("Serving dashboard at http://%s/ and API at http://%s/api/opportunities", addr, addr)
+	if metricsFile != "" {
+		log.Printf("Serving metrics from %s at http://%s/metrics", metricsFile, addr)
+	}
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down server: %w", err)
+	}
+	log.Printf("Server stopped")
+	return nil
+}
+
 func validateEnvironment() error {
 	required := []string{
 		"SAM_API_KEY",
@@ -130,12 +397,14 @@ func validateEnvironment() error {
 
 	optional := []string{
 		"SMTP_HOST",
-		"SMTP_PORT", 
+		"SMTP_PORT",
 		"SMTP_USERNAME",
 		"SMTP_PASSWORD",
 		"EMAIL_FROM",
 		"EMAIL_TO",
 		"SLACK_WEBHOOK",
+		"TEAMS_WEBHOOK",
+		"WEBHOOK_URL",
 	}
 
 	missing := []string{}
@@ -177,12 +446,17 @@ func showUsage() {
 	fmt.Printf(`SAM.gov Opportunity Monitor v%s
 
 Usage: %s [options]
+       %s track <command> [arguments] [options]
 
 Options:
   -config string
         Path to config file (default "%s")
   -state string  
         Path to state file (default "%s")
+  -state-backend string
+        State storage backend: json or sqlite (default "json"). The SQLite
+        database is stored beside the state file with a .db extension and
+        imports the JSON state on first use
   -dry-run
         Run without sending notifications
   -v    Verbose output
@@ -196,8 +470,50 @@ Options:
         Generate status report from state file
   -debug-email
         Send test email every run
+  -no-cache
+        Disable the search response cache
+  -cache-dir string
+        Directory for cached search responses (default "%s")
+  -cache-ttl duration
+        How long cached search responses are reused (default %v)
+  -daemon
+        Keep running and execute queries on their schedules
+  -flush-digest
+        Send queued digest notifications now and exit
+  -calendar string
+        Deadline calendar file rewritten after each run, empty to
+        disable (default "%s")
+  -calendar-addr string
+        Serve the deadline calendar over HTTP at this address in daemon
+        mode, e.g. :8080
+  -outbox
+        List failed and pending notification deliveries and exit
+  -replay string
+        Resend failed deliveries and exit: 'all' or comma-separated
+        delivery IDs
+  -serve string
+        Serve the read-only query API, dashboard and Prometheus metrics
+        at this address, e.g. 127.0.0.1:8080. Without -daemon only the
+        state and metrics files are read and no API key is needed
+  -serve-public
+        Allow -serve on addresses other than loopback, such as :8080.
+        The API has no authentication
+  -awards
+        Print who won the recorded awards per agency and NAICS code
+        and exit
+  -metrics-file string
+        File the run metrics are kept in across runs, served in
+        Prometheus format at /metrics by -serve; empty keeps them in
+        memory (default "%s")
+  -metrics-textfile string
+        Prometheus text file rewritten after each run, for the
+        node_exporter textfile collector or a Pushgateway push
   -help Show this help
 
+Commands:
+  track            Set the capture pipeline stage, owner and notes of
+                   tracked opportunities; run "track -help" for details
+
 Environment Variables:
   SAM_API_KEY      Required - SAM.gov API key
   SMTP_HOST        Optional - SMTP server host
@@ -207,18 +523,34 @@ Environment Variables:
   EMAIL_FROM       Optional - Sender email address
   EMAIL_TO         Optional - Recipient email addresses
   SLACK_WEBHOOK    Optional - Slack webhook URL
+  TEAMS_WEBHOOK    Optional - Microsoft Teams webhook URL
+  WEBHOOK_URL      Optional - URL to POST notification JSON to
+  WEBHOOK_SECRET   Optional - Key for the webhook HMAC-SHA256 signature
 
 Examples:
   %s -config myconfig.yaml -dry-run -v
   %s -validate-env
   %s -lookback 7 -v
-
-`, Version, os.Args[0], DefaultConfigPath, DefaultStateFile, DefaultLookback, 
-   os.Args[0], os.Args[0], os.Args[0])
+  %s -daemon
+  %s -flush-digest
+  %s -daemon -calendar-addr :8080
+  %s -outbox
+  %s -replay all
+  %s -serve 127.0.0.1:8080
+  %s -awards
+  %s -metrics-textfile /var/lib/node_exporter/textfile/samgov.prom
+  %s track set-stage <noticeId> pursuing --owner alice
+
+`, Version, os.Args[0], os.Args[0], DefaultConfigPath, DefaultStateFile, DefaultLookback,
+		DefaultCacheDir, monitor.DefaultCacheTTL, DefaultCalendarFile, DefaultMetricsFile, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 }
 
 // generateReport creates a status report from the state file
-func generateReport(stateFile string) error {
+func generateReport(stateFile, backend string) error {
+	if backend == monitor.StateBackendSQLite {
+		return generateSQLiteReport(stateFile)
+	}
+
 	data, err := os.ReadFile(stateFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -263,4 +595,126 @@ func generateReport(stateFile string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// listDeliveries prints the failed and pending deliveries in the outbox
+func listDeliveries(stateFile, backend string) error {
+	store, err := monitor.OpenStateStore(backend, stateFile, false)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	defer store.Close()
+
+	deliveries := store.GetDeliveries()
+	sent := 0
+	unsent := make([]notify.Delivery, 0)
+	for _, delivery := range deliveries {
+		if delivery.Status == notify.DeliverySent {
+			sent++
+			continue
+		}
+		unsent = append(unsent, delivery)
+	}
+
+	fmt.Printf("# Notification Outbox\n\n")
+	fmt.Printf("%d unsent, %d sent\n\n", len(unsent), sent)
+	if len(unsent) == 0 {
+		fmt.Printf("Nothing to replay.\n")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tCHANNEL\tATTEMPTS\tCREATED\tSUBJECT")
+	for _, delivery := range unsent {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", delivery.ID, delivery.Status, delivery.Channel,
+			delivery.Attempts, delivery.CreatedAt.Format("2006-01-02 15:04"), delivery.Subject)
+		if delivery.LastError != "" {
+			fmt.Fprintf(w, "\t\t\t\t\tlast error: %s\n", delivery.LastError)
+		}
+	}
+	w.Flush()
+
+	fmt.Printf("\nResend with -replay all or -replay <id>[,<id>...]\n")
+	return nil
+}
+
+// generateAwardReport prints who won the recorded awards, per agency and per
+// NAICS code
+func generateAwardReport(stateFile, backend string) error {
+	store, err := monitor.OpenStateStore(backend, stateFile, false)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	defer store.Close()
+
+	report := monitor.BuildAwardReport(store.ListAwards())
+
+	fmt.Printf("# SAM.gov Award Report\n\n")
+	fmt.Printf("Generated: %s\n\n", time.Now().Format(time.RFC3339))
+	fmt.Printf("## Summary\n")
+	fmt.Printf("- Awards Recorded: %d\n", report.Awards)
+	fmt.Printf("- Awards of Tracked Opportunities: %d\n", report.Linked)
+	fmt.Printf("- Total Awarded: %s\n", samgov.FormatDollars(report.TotalAmount))
+
+	if report.Awards == 0 {
+		fmt.Printf("\nNo awards recorded yet. Set trackAwards on a query that searches award notices (ptype a).\n")
+		return nil
+	}
+
+	printAwardGroups("By Agency", report.ByAgency)
+	printAwardGroups("By NAICS Code", report.ByNAICS)
+	return nil
+}
+
+// printAwardGroups prints the winners of each award group as a table
+func printAwardGroups(heading string, groups []monitor.AwardGroup) {
+	fmt.Printf("\n## %s\n", heading)
+	for _, group := range groups {
+		fmt.Printf("\n### %s\n", group.Key)
+		fmt.Printf("%d awards, %s\n\n", group.Awards, samgov.FormatDollars(group.TotalAmount))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "AWARDEE\tUEI\tAWARDS\tTOTAL")
+		for _, winner := range group.Winners {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", winner.Awardee, winner.UEI, winner.Awards, samgov.FormatDollars(winner.TotalAmount))
+		}
+		w.Flush()
+	}
+}
+
+// generateSQLiteReport creates a status report from the SQLite state database
+func generateSQLiteReport(stateFile string) error {
+	dbPath := monitor.SQLitePath(stateFile)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		fmt.Printf("State database not found: %s\n", dbPath)
+		fmt.Printf("No monitoring runs have been completed yet.\n")
+		return nil
+	}
+
+	store, err := monitor.OpenSQLiteStore(dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	stats := store.GetStats()
+
+	fmt.Printf("# SAM.gov Monitor Status Report\n\n")
+	fmt.Printf("Generated: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Printf("State Database: %s\n\n", dbPath)
+
+	fmt.Printf("## Summary\n")
+	fmt.Printf("- Total Opportunities Tracked: %d\n", stats.TotalOpportunities)
+	fmt.Printf("- First Seen This Week: %d\n", stats.OpportunitiesLastWeek)
+	fmt.Printf("- Queries Tracked: %d (%.0f%% successful executions)\n", stats.TotalQueries, stats.QuerySuccessRate*100)
+
+	if !stats.LastRun.IsZero() {
+		fmt.Printf("- Last Run: %s (%s ago)\n",
+			stats.LastRun.Format(time.RFC3339),
+			time.Since(stats.LastRun).Round(time.Minute))
+	} else {
+		fmt.Printf("- Last Run: Never\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// trackUsage describes the track subcommand
+const trackUsage = `Usage: %s track <command> [arguments] [options]
+
+Manage the capture pipeline of tracked opportunities.
+
+Commands:
+  set-stage <noticeId> <stage>   Move a notice to another pipeline stage
+  set-owner <noticeId> <owner>   Assign a notice, "" to unassign
+  set-notes <noticeId> <notes>   Replace a notice's notes
+  show <noticeId>                Print a notice's pipeline details
+  list                           List the notices in the pipeline
+
+Stages:
+  new → reviewing → pursuing → submitted → won or lost
+  new, reviewing and pursuing can also move to no-bid, and no-bid back to
+  reviewing. Won, lost and no-bid notices get no amendment alerts, and
+  submitted ones no deadline reminders.
+
+Options:
+  -state string
+        Path to state file (default "%s")
+  -state-backend string
+        State storage backend: json or sqlite (default "json")
+  -owner string
+        set-stage: also assign the notice
+  -notes string
+        set-stage: also replace the notes
+  -force
+        set-stage: allow a move the pipeline does not, e.g. reopening a
+        lost bid
+  -stage string
+        list: only notices in this stage
+  -all
+        list: include notices still in stage new
+
+Examples:
+  %s track set-stage 3f2a9c reviewing
+  %s track set-stage 3f2a9c pursuing --owner alice --notes "Teaming with Acme"
+  %s track list -stage pursuing
+`
+
+// trackCommands maps each track command to its number of arguments
+var trackCommands = map[string]int{
+	"set-stage": 2,
+	"set-owner": 2,
+	"set-notes": 2,
+	"show":      1,
+	"list":      0,
+}
+
+// runTrack runs "track <command>", which reads and updates the pipeline
+// stage, owner and notes of tracked opportunities
+func runTrack(args []string) error {
+	fs := flag.NewFlagSet("track", flag.ContinueOnError)
+	stateFile := fs.String("state", DefaultStateFile, "Path to state file")
+	stateBackend := fs.String("state-backend", monitor.StateBackendJSON, "State storage backend: json or sqlite")
+	owner := fs.String("owner", "", "Assign the notice")
+	notes := fs.String("notes", "", "Replace the notice's notes")
+	force := fs.Bool("force", false, "Allow a move the pipeline does not")
+	stageFilter := fs.String("stage", "", "Only list notices in this stage")
+	all := fs.Bool("all", false, "Also list notices in stage new")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), trackUsage, os.Args[0], DefaultStateFile, os.Args[0], os.Args[0], os.Args[0])
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		fs.Usage()
+		return errors.New("missing command")
+	}
+
+	command, positional := positional[0], positional[1:]
+	want, ok := trackCommands[command]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+	if len(positional) != want {
+		return fmt.Errorf("%s takes %d arguments, got %d", command, want, len(positional))
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	store, err := monitor.OpenStateStore(*stateBackend, *stateFile, false)
+	if err != nil {
+		return fmt.Errorf("loading state: %w", err)
+	}
+	defer store.Close()
+
+	switch command {
+	case "list":
+		return listPipeline(store, *stageFilter, *all)
+	case "show":
+		tracked, ok := store.GetOpportunity(positional[0])
+		if !ok {
+			return fmt.Errorf("opportunity %s is not tracked", positional[0])
+		}
+		printPipeline(tracked)
+		return nil
+	}
+
+	change := monitor.PipelineChange{Force: *force}
+	switch command {
+	case "set-stage":
+		stage, err := samgov.ParseStage(positional[1])
+		if err != nil {
+			return err
+		}
+		change.Stage = stage
+		if set["owner"] {
+			change.Owner = owner
+		}
+		if set["notes"] {
+			change.Notes = notes
+		}
+	case "set-owner":
+		change.Owner = &positional[1]
+	case "set-notes":
+		change.Notes = &positional[1]
+	}
+
+	updated, err := store.UpdatePipeline(positional[0], change)
+	if err != nil {
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	printPipeline(updated)
+	return nil
+}
+
+// parseInterspersed parses flags given before, between or after the
+// positional arguments, which the flag package stops at, and returns the
+// positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// printPipeline prints a notice's pipeline details
+func printPipeline(tracked samgov.OpportunityState) {
+	stage := tracked.PipelineStage()
+	next := make([]string, 0)
+	for _, s := range stage.NextStages() {
+		next = append(next, string(s))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Notice:\t%s\n", tracked.NoticeID)
+	fmt.Fprintf(w, "Title:\t%s\n", tracked.Title)
+	if len(next) > 0 {
+		fmt.Fprintf(w, "Stage:\t%s (next: %s)\n", stage, strings.Join(next, ", "))
+	} else {
+		fmt.Fprintf(w, "Stage:\t%s (final)\n", stage)
+	}
+	if tracked.Owner != "" {
+		fmt.Fprintf(w, "Owner:\t%s\n", tracked.Owner)
+	}
+	if tracked.Deadline != nil {
+		fmt.Fprintf(w, "Deadline:\t%s\n", *tracked.Deadline)
+	}
+	if !tracked.StageUpdated.IsZero() {
+		fmt.Fprintf(w, "Updated:\t%s\n", tracked.StageUpdated.Local().Format(time.RFC3339))
+	}
+	if tracked.Notes != "" {
+		fmt.Fprintf(w, "Notes:\t%s\n", tracked.Notes)
+	}
+	fmt.Fprintf(w, "Link:\thttps://sam.gov/opp/%s/view\n", tracked.NoticeID)
+	w.Flush()
+}
+
+// listPipeline prints the tracked notices by stage, then deadline. Notices
+// still in stage new are left out unless all is set or stage asks for them.
+func listPipeline(store monitor.StateStore, stage string, all bool) error {
+	var only samgov.Stage
+	if stage != "" {
+		parsed, err := samgov.ParseStage(stage)
+		if err != nil {
+			return err
+		}
+		only = parsed
+	}
+
+	order := make(map[samgov.Stage]int, len(samgov.Stages))
+	for i, s := range samgov.Stages {
+		order[s] = i
+	}
+
+	listed := make([]samgov.OpportunityState, 0)
+	for _, tracked := range store.ListOpportunities() {
+		current := tracked.PipelineStage()
+		if only != "" && current != only {
+			continue
+		}
+		if only == "" && !all && current == samgov.StageNew {
+			continue
+		}
+		listed = append(listed, tracked)
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		a, b := listed[i], listed[j]
+		if order[a.PipelineStage()] != order[b.PipelineStage()] {
+			return order[a.PipelineStage()] < order[b.PipelineStage()]
+		}
+		// Missing deadlines sort last
+		if da, db := deadlineOf(a), deadlineOf(b); da != db {
+			return db == "" || (da != "" && da < db)
+		}
+		return a.NoticeID < b.NoticeID
+	})
+
+	if len(listed) == 0 {
+		fmt.Printf("No opportunities in the pipeline. Move one with: %s track set-stage <noticeId> reviewing\n", os.Args[0])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NOTICE ID\tSTAGE\tOWNER\tDEADLINE\tTITLE")
+	for _, tracked := range listed {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", tracked.NoticeID, tracked.PipelineStage(), tracked.Owner,
+			deadlineOf(tracked), truncate(tracked.Title, 60))
+	}
+	return w.Flush()
+}
+
+// deadlineOf returns a notice's deadline date, "" when it has none
+func deadlineOf(tracked samgov.OpportunityState) string {
+	if tracked.Deadline == nil {
+		return ""
+	}
+	if deadline, ok := samgov.ParseDate(*tracked.Deadline); ok {
+		return deadline.Format("2006-01-02")
+	}
+	return *tracked.Deadline
+}
+
+// truncate shortens text to at most n runes
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "…"
+}
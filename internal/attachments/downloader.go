@@ -0,0 +1,165 @@
+package attachments
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// DefaultMaxDownloads caps downloads per query run when the query does not set one
+const DefaultMaxDownloads = 10
+
+// MaxEmailAttachmentBytes bounds the total size of documents attached to one email
+const MaxEmailAttachmentBytes = 10 << 20
+
+// Downloader saves opportunity resource files into a directory per notice,
+// skipping files whose content is already stored
+type Downloader struct {
+	client  *samgov.Client
+	baseDir string
+	verbose bool
+}
+
+// NewDownloader creates a downloader that stores files under baseDir
+func NewDownloader(client *samgov.Client, baseDir string, verbose bool) (*Downloader, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating attachments directory: %w", err)
+	}
+
+	return &Downloader{
+		client:  client,
+		baseDir: baseDir,
+		verbose: verbose,
+	}, nil
+}
+
+// Download fetches the resource links of opp that are not among known, up to
+// max downloads. Files with the same content as an existing document are not
+// written again. It stops early, without error, when the budget runs out.
+func (d *Downloader) Download(ctx context.Context, opp samgov.Opportunity, known []samgov.Document, max int) ([]samgov.Document, error) {
+	knownURLs := make(map[string]bool, len(known))
+	byHash := make(map[string]samgov.Document, len(known))
+	for _, doc := range known {
+		knownURLs[doc.URL] = true
+		byHash[doc.SHA256] = doc
+	}
+
+	dir := filepath.Join(d.baseDir, sanitizeName(opp.NoticeID))
+	downloaded := make([]samgov.Document, 0)
+
+	for _, link := range opp.ResourceLinks {
+		if knownURLs[link] {
+			continue
+		}
+		if len(downloaded) >= max {
+			if d.verbose {
+				log.Printf("Attachment limit reached for %s", opp.NoticeID)
+			}
+			break
+		}
+
+		resource, err := d.client.DownloadResource(ctx, link)
+		if err != nil {
+			if errors.Is(err, samgov.ErrBudgetExhausted) || ctx.Err() != nil {
+				log.Printf("Stopped downloading attachments for %s: %v", opp.NoticeID, err)
+				break
+			}
+			log.Printf("Failed to download attachment for %s: %v", opp.NoticeID, err)
+			continue
+		}
+
+		sum := fmt.Sprintf("%x", sha256.Sum256(resource.Content))
+		doc := samgov.Document{
+			URL:          link,
+			Name:         resource.Name,
+			ContentType:  resource.ContentType,
+			Size:         int64(len(resource.Content)),
+			SHA256:       sum,
+			DownloadedAt: time.Now(),
+		}
+
+		// Same bytes under a new link: record the link, reuse the file
+		if existing, ok := byHash[sum]; ok {
+			doc.Path = existing.Path
+			if d.verbose {
+				log.Printf("Attachment %s for %s duplicates %s", resource.Name, opp.NoticeID, existing.Name)
+			}
+		} else {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return downloaded, fmt.Errorf("creating notice directory: %w", err)
+			}
+			doc.Path = filepath.Join(dir, sum[:12]+"-"+sanitizeName(resource.Name))
+			if err := os.WriteFile(doc.Path, resource.Content, 0644); err != nil {
+				return downloaded, fmt.Errorf("writing attachment: %w", err)
+			}
+			if d.verbose {
+				log.Printf("Downloaded %s for %s (%d bytes)", resource.Name, opp.NoticeID, doc.Size)
+			}
+		}
+
+		byHash[sum] = doc
+		knownURLs[link] = true
+		downloaded = append(downloaded, doc)
+	}
+
+	return downloaded, nil
+}
+
+// EmailAttachments loads documents from disk as notification attachments,
+// skipping duplicates and stopping at MaxEmailAttachmentBytes
+func EmailAttachments(docs []samgov.Document) []notify.Attachment {
+	attachments := make([]notify.Attachment, 0, len(docs))
+	seen := make(map[string]bool)
+	var total int64
+
+	for _, doc := range docs {
+		if seen[doc.SHA256] {
+			continue
+		}
+		if total+doc.Size > MaxEmailAttachmentBytes {
+			log.Printf("Skipping attachment %s: email size limit reached", doc.Name)
+			continue
+		}
+
+		content, err := os.ReadFile(doc.Path)
+		if err != nil {
+			log.Printf("Skipping attachment %s: %v", doc.Name, err)
+			continue
+		}
+
+		seen[doc.SHA256] = true
+		total += doc.Size
+		attachments = append(attachments, notify.Attachment{
+			Name:        doc.Name,
+			Content:     content,
+			ContentType: doc.ContentType,
+		})
+	}
+
+	return attachments
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeName makes a notice ID or file name safe to use as a path element
+func sanitizeName(name string) string {
+	name = unsafeNameChars.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		return "file"
+	}
+	if len(name) > 120 {
+		name = name[len(name)-120:]
+	}
+	return name
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// CacheStats counts how searches were served
+type CacheStats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// CachedSearcher serves repeated identical searches from the file cache and
+// only forwards misses to the wrapped searcher
+type CachedSearcher struct {
+	searcher samgov.Searcher
+	cache    *Cache
+	verbose  bool
+
+	mu    sync.Mutex
+	stats CacheStats
+}
+
+// NewCachedSearcher wraps a searcher with a cache
+func NewCachedSearcher(searcher samgov.Searcher, cache *Cache, verbose bool) *CachedSearcher {
+	return &CachedSearcher{
+		searcher: searcher,
+		cache:    cache,
+		verbose:  verbose,
+	}
+}
+
+// Search returns a cached response when one is available, otherwise executes
+// the search and caches the response
+func (cs *CachedSearcher) Search(ctx context.Context, params map[string]string) (*samgov.SearchResponse, error) {
+	if response, ok := cs.cache.Get(params); ok {
+		cs.mu.Lock()
+		cs.stats.Hits++
+		cs.mu.Unlock()
+		return response, nil
+	}
+
+	cs.mu.Lock()
+	cs.stats.Misses++
+	cs.mu.Unlock()
+
+	response, err := cs.searcher.Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed cache write should never fail the search itself
+	if err := cs.cache.Set(params, response); err != nil && cs.verbose {
+		log.Printf("Warning: could not cache response: %v", err)
+	}
+
+	return response, nil
+}
+
+// Stats returns the hit/miss counts since the searcher was created
+func (cs *CachedSearcher) Stats() CacheStats {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.stats
+}
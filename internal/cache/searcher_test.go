@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// countingSearcher returns one notice per search and counts the calls
+type countingSearcher struct {
+	calls int
+	err   error
+}
+
+func (s *countingSearcher) Search(ctx context.Context, params map[string]string) (*samgov.SearchResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &samgov.SearchResponse{
+		TotalRecords:      1,
+		OpportunitiesData: []samgov.Opportunity{{NoticeID: params["title"]}},
+	}, nil
+}
+
+func TestCachedSearcher(t *testing.T) {
+	first := map[string]string{"title": "cloud", "limit": "10"}
+	reordered := map[string]string{"limit": "10", "title": "cloud"}
+	other := map[string]string{"title": "cyber", "limit": "10"}
+
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		searches   []map[string]string
+		wantCalls  int
+		wantHits   int
+		wantMisses int
+	}{
+		{"repeated search is a hit", time.Hour, []map[string]string{first, first}, 1, 1, 1},
+		{"parameter order does not matter", time.Hour, []map[string]string{first, reordered}, 1, 1, 1},
+		{"different parameters miss", time.Hour, []map[string]string{first, other}, 2, 0, 2},
+		{"expired entry misses", time.Nanosecond, []map[string]string{first, first}, 2, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, err := NewCache(t.TempDir(), tt.ttl, false)
+			if err != nil {
+				t.Fatalf("NewCache: %v", err)
+			}
+			upstream := &countingSearcher{}
+			searcher := NewCachedSearcher(upstream, cache, false)
+
+			for _, params := range tt.searches {
+				resp, err := searcher.Search(context.Background(), params)
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if len(resp.OpportunitiesData) != 1 || resp.OpportunitiesData[0].NoticeID != params["title"] {
+					t.Errorf("Search(%v) = %+v", params, resp.OpportunitiesData)
+				}
+			}
+
+			if upstream.calls != tt.wantCalls {
+				t.Errorf("upstream calls = %d, want %d", upstream.calls, tt.wantCalls)
+			}
+			stats := searcher.Stats()
+			if stats.Hits != tt.wantHits || stats.Misses != tt.wantMisses {
+				t.Errorf("stats = %+v, want %d hits and %d misses", stats, tt.wantHits, tt.wantMisses)
+			}
+		})
+	}
+}
+
+func TestCachedSearcherSkipsFailures(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), time.Hour, false)
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	upstream := &countingSearcher{err: errors.New("service unavailable")}
+	searcher := NewCachedSearcher(upstream, cache, false)
+	params := map[string]string{"title": "cloud"}
+
+	if _, err := searcher.Search(context.Background(), params); err == nil {
+		t.Fatalf("Search succeeded, want the upstream error")
+	}
+
+	// The failure was not cached, so the next search goes upstream again
+	upstream.err = nil
+	if _, err := searcher.Search(context.Background(), params); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if upstream.calls != 2 {
+		t.Errorf("upstream calls = %d, want 2", upstream.calls)
+	}
+}
+
+func TestCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	params := map[string]string{"title": "cloud"}
+
+	cache, err := NewCache(dir, time.Hour, false)
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	if _, err := NewCachedSearcher(&countingSearcher{}, cache, false).Search(context.Background(), params); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	// A new process loads the cached response from disk
+	reopened, err := NewCache(dir, time.Hour, false)
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	upstream := &countingSearcher{}
+	if _, err := NewCachedSearcher(upstream, reopened, false).Search(context.Background(), params); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if upstream.calls != 0 {
+		t.Errorf("upstream calls = %d, want 0", upstream.calls)
+	}
+}
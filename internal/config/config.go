@@ -4,14 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/yourusername/sam-gov-monitor/internal/filter"
 )
 
 // Config represents the complete configuration for the monitor
 type Config struct {
-	Queries []Query `yaml:"queries"`
+	Notifications NotificationsConfig `yaml:"notifications,omitempty"`
+	Queries       []Query             `yaml:"queries"`
 }
 
 // Query represents a single search query configuration
@@ -21,44 +25,57 @@ type Query struct {
 	Parameters   map[string]interface{} `yaml:"parameters"`
 	Notification NotificationConfig     `yaml:"notification"`
 	Advanced     AdvancedQuery          `yaml:"advanced,omitempty"`
+	Schedule     string                 `yaml:"schedule,omitempty"` // daemon mode: interval, @daily or cron
+	Scoring      ScoringConfig          `yaml:"scoring,omitempty"`
 }
 
 // NotificationConfig defines how notifications should be sent
 type NotificationConfig struct {
-	Priority    string   `yaml:"priority"`    // high, medium, low
-	Recipients  []string `yaml:"recipients,omitempty"`
-	Channels    []string `yaml:"channels,omitempty"` // email, slack, github
-	Template    string   `yaml:"template,omitempty"`
-	Digest      bool     `yaml:"digest,omitempty"`   // group notifications
+	Priority   string   `yaml:"priority"` // high, medium, low
+	Recipients []string `yaml:"recipients,omitempty"`
+	Channels   []string `yaml:"channels,omitempty"`  // email, slack, teams, github, webhook or a named channel
+	Template   string   `yaml:"template,omitempty"`  // user-supplied templates in notifications.templateDir
+	Digest     bool     `yaml:"digest,omitempty"`    // group notifications
+	Reminders  []string `yaml:"reminders,omitempty"` // deadline reminders, e.g. ["7d", "3d", "24h"]
 }
 
 // AdvancedQuery provides additional filtering options
 type AdvancedQuery struct {
-	Include       []string  `yaml:"include,omitempty"`        // Keywords that must be present
-	Exclude       []string  `yaml:"exclude,omitempty"`        // Keywords that must not be present
-	MinValue      float64   `yaml:"minValue,omitempty"`       // Minimum contract value
-	MaxValue      float64   `yaml:"maxValue,omitempty"`       // Maximum contract value
-	MaxDaysOld    int       `yaml:"maxDaysOld,omitempty"`     // Maximum age in days
-	SetAsideTypes []string  `yaml:"setAsideTypes,omitempty"`  // Required set-aside types
-	NAICSCodes    []string  `yaml:"naicsCodes,omitempty"`     // Required NAICS codes
+	Include             []string `yaml:"include,omitempty"`             // Keywords that must be present
+	Exclude             []string `yaml:"exclude,omitempty"`             // Keywords that must not be present
+	MinValue            float64  `yaml:"minValue,omitempty"`            // Minimum contract value
+	MaxValue            float64  `yaml:"maxValue,omitempty"`            // Maximum contract value
+	MaxDaysOld          int      `yaml:"maxDaysOld,omitempty"`          // Maximum age in days
+	SetAsideTypes       []string `yaml:"setAsideTypes,omitempty"`       // Required set-aside types
+	NAICSCodes          []string `yaml:"naicsCodes,omitempty"`          // Required NAICS codes
+	FetchDescriptions   bool     `yaml:"fetchDescriptions,omitempty"`   // Download full descriptions (one request each)
+	MaxDescriptions     int      `yaml:"maxDescriptions,omitempty"`     // Cap on description downloads per run
+	DownloadAttachments bool     `yaml:"downloadAttachments,omitempty"` // Save resource link files under state/attachments
+	MaxAttachments      int      `yaml:"maxAttachments,omitempty"`      // Cap on attachment downloads per run
+	AttachDocuments     bool     `yaml:"attachDocuments,omitempty"`     // Attach downloaded files to email notifications
+	Filter              string   `yaml:"filter,omitempty"`              // Boolean filter expression, see internal/filter
+	TrackAwards         bool     `yaml:"trackAwards,omitempty"`         // Record award notices and link them to tracked solicitations
 }
 
 // Load reads and parses the configuration file
-func Load(filepath string) (*Config, error) {
-	if filepath == "" {
+func Load(path string) (*Config, error) {
+	if path == "" {
 		return nil, errors.New("config file path is required")
 	}
 
-	data, err := os.ReadFile(filepath)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading config file %s: %w", filepath, err)
+		return nil, fmt.Errorf("reading config file %s: %w", path, err)
 	}
 
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("parsing config file %s: %w", filepath, err)
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
 	}
 
+	config.interpolateChannels()
+	config.Notifications.resolveTemplateDir(filepath.Dir(path))
+
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
@@ -72,20 +89,56 @@ func (c *Config) Validate() error {
 		return errors.New("no queries configured")
 	}
 
+	for name := range c.Notifications.Channels {
+		if IsBuiltinChannel(name) {
+			return fmt.Errorf("notification channel name '%s' is reserved", name)
+		}
+	}
+
+	if _, err := c.Notifications.Digest.ParseSchedule(); err != nil {
+		return err
+	}
+
+	if _, _, err := c.Notifications.Outbox.ParsePolicy(); err != nil {
+		return err
+	}
+
 	enabledCount := 0
+	referenced := make(map[string]bool)
 	for i, query := range c.Queries {
 		if err := query.Validate(); err != nil {
 			return fmt.Errorf("query %d (%s): %w", i, query.Name, err)
 		}
+		for _, channel := range query.Notification.Channels {
+			if !c.HasChannel(channel) {
+				return fmt.Errorf("query %d (%s): unknown notification channel '%s'", i, query.Name, channel)
+			}
+			if query.Enabled {
+				referenced[channel] = true
+			}
+		}
 		if query.Enabled {
 			enabledCount++
 		}
 	}
 
+	// Only channels used by enabled queries need their secrets present
+	for name := range referenced {
+		if channel, ok := c.Notifications.Channels[name]; ok {
+			if err := channel.Validate(); err != nil {
+				return fmt.Errorf("notification channel '%s': %w", name, err)
+			}
+		}
+	}
+
 	if enabledCount == 0 {
 		return errors.New("no enabled queries found")
 	}
 
+	if err := c.validateTemplates(referenced); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -105,12 +158,13 @@ func (q *Query) Validate() error {
 		return fmt.Errorf("invalid notification priority '%s', must be high, medium, or low", q.Notification.Priority)
 	}
 
-	// Validate notification channels
-	validChannels := map[string]bool{"email": true, "slack": true, "github": true}
-	for _, channel := range q.Notification.Channels {
-		if !validChannels[channel] {
-			return fmt.Errorf("invalid notification channel '%s'", channel)
-		}
+	if _, err := ParseReminders(q.Notification.Reminders); err != nil {
+		return err
+	}
+
+	// Validate schedule
+	if _, err := ParseSchedule(q.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
 	}
 
 	// Validate advanced query parameters
@@ -121,6 +175,25 @@ func (q *Query) Validate() error {
 		return errors.New("maxDaysOld cannot exceed 365 days")
 	}
 
+	if q.Advanced.MaxDescriptions < 0 {
+		return errors.New("maxDescriptions cannot be negative")
+	}
+
+	if q.Advanced.MaxAttachments < 0 {
+		return errors.New("maxAttachments cannot be negative")
+	}
+
+	if q.Advanced.Filter != "" {
+		if _, err := filter.Compile(q.Advanced.Filter); err != nil {
+			// Show the expression with a caret under the error
+			var syntaxErr *filter.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return fmt.Errorf("invalid advanced filter: %w\n%s", err, syntaxErr.Context())
+			}
+			return fmt.Errorf("invalid advanced filter: %w", err)
+		}
+	}
+
 	if q.Advanced.MinValue < 0 {
 		return errors.New("minValue cannot be negative")
 	}
@@ -128,6 +201,10 @@ func (q *Query) Validate() error {
 		return errors.New("minValue cannot be greater than maxValue")
 	}
 
+	if err := q.Scoring.Validate(); err != nil {
+		return fmt.Errorf("invalid scoring: %w", err)
+	}
+
 	// Validate lookback days parameter
 	if lookbackDays, ok := q.Parameters["lookbackDays"].(int); ok {
 		if lookbackDays < 1 {
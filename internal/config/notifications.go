@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+)
+
+// Built-in channel types. A query may list these directly to use the
+// channel configured from environment variables.
+const (
+	ChannelTypeEmail   = "email"
+	ChannelTypeSlack   = "slack"
+	ChannelTypeTeams   = "teams"
+	ChannelTypeGitHub  = "github"
+	ChannelTypeWebhook = "webhook"
+)
+
+// NotificationsConfig holds the top-level notifications block
+type NotificationsConfig struct {
+	Channels map[string]ChannelConfig `yaml:"channels,omitempty"` // named channels referenced by queries
+	Digest   DigestConfig             `yaml:"digest,omitempty"`
+	Outbox   OutboxConfig             `yaml:"outbox,omitempty"`
+
+	// TemplateDir holds user-supplied notification templates; relative paths
+	// are resolved against the config file's directory
+	TemplateDir string `yaml:"templateDir,omitempty"`
+}
+
+// DefaultTemplateDir is used when the notifications block sets no templateDir
+const DefaultTemplateDir = "templates"
+
+// TemplatePath returns the template directory with the default applied
+func (n NotificationsConfig) TemplatePath() string {
+	if n.TemplateDir == "" {
+		return DefaultTemplateDir
+	}
+	return n.TemplateDir
+}
+
+// resolveTemplateDir makes a relative template directory relative to the
+// directory of the config file
+func (n *NotificationsConfig) resolveTemplateDir(configDir string) {
+	dir := n.TemplatePath()
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(configDir, dir)
+	}
+	n.TemplateDir = dir
+}
+
+// validateTemplates loads every user-supplied template named by an enabled
+// query or by a channel one uses, so that a broken template fails at startup
+// rather than on the first notification
+func (c *Config) validateTemplates(referenced map[string]bool) error {
+	dir := c.Notifications.TemplatePath()
+	checked := make(map[string]bool)
+	check := func(name string) error {
+		if name == "" || checked[name] {
+			return nil
+		}
+		checked[name] = true
+		_, err := notify.LoadTemplates(dir, name)
+		return err
+	}
+
+	for i, query := range c.Queries {
+		if !query.Enabled {
+			continue
+		}
+		if err := check(query.Notification.Template); err != nil {
+			return fmt.Errorf("query %d (%s): notification template: %w", i, query.Name, err)
+		}
+	}
+
+	names := make([]string, 0, len(referenced))
+	for name := range referenced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := check(c.Notifications.Channels[name].Template); err != nil {
+			return fmt.Errorf("notification channel '%s': template: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// DigestConfig sets when notifications queued by digest queries are sent
+type DigestConfig struct {
+	Schedule string `yaml:"schedule,omitempty"` // e.g. "daily 08:00" or "weekly monday 08:00"
+	Timezone string `yaml:"timezone,omitempty"` // IANA zone such as America/New_York; defaults to local time
+}
+
+// DefaultDigestSchedule is used when the digest block sets no schedule
+const DefaultDigestSchedule = "daily 08:00"
+
+// ParseSchedule returns the digest schedule in its time zone
+func (d DigestConfig) ParseSchedule() (Schedule, error) {
+	loc := time.Local
+	if d.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(d.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid digest timezone '%s': %w", d.Timezone, err)
+		}
+	}
+
+	spec := d.Schedule
+	if spec == "" {
+		spec = DefaultDigestSchedule
+	}
+	schedule, err := ParseScheduleIn(spec, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// OutboxConfig sets how long failed notification deliveries are retried
+type OutboxConfig struct {
+	MaxAttempts int    `yaml:"maxAttempts,omitempty"` // attempts before automatic retries stop
+	Retention   string `yaml:"retention,omitempty"`   // e.g. "7d"; older deliveries are dropped
+}
+
+// Outbox defaults, used when the outbox block leaves them unset
+const (
+	DefaultOutboxMaxAttempts = 5
+	DefaultOutboxRetention   = "7d"
+)
+
+// ParsePolicy returns the attempt limit and retention period with defaults
+// applied
+func (o OutboxConfig) ParsePolicy() (int, time.Duration, error) {
+	maxAttempts := o.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultOutboxMaxAttempts
+	}
+	if maxAttempts < 0 {
+		return 0, 0, fmt.Errorf("outbox maxAttempts cannot be negative")
+	}
+
+	spec := o.Retention
+	if spec == "" {
+		spec = DefaultOutboxRetention
+	}
+	retention, err := parseDaysOrDuration(spec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid outbox retention '%s': expected days such as '7d' or a duration such as '72h'", o.Retention)
+	}
+	if retention < time.Hour {
+		return 0, 0, fmt.Errorf("outbox retention '%s' must be at least one hour", o.Retention)
+	}
+	return maxAttempts, retention, nil
+}
+
+// ChannelConfig defines one named notification channel. Type selects which of
+// the fields apply. Any value may use ${ENV_VAR} to pull secrets from the
+// environment.
+type ChannelConfig struct {
+	Type string `yaml:"type"` // email, slack, teams, github, webhook
+
+	// Template names user-supplied templates for email, slack and github
+	// channels, used when the query names none
+	Template string `yaml:"template,omitempty"`
+
+	// Email
+	SMTPHost string   `yaml:"smtpHost,omitempty"`
+	SMTPPort int      `yaml:"smtpPort,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+	UseTLS   *bool    `yaml:"useTLS,omitempty"`
+
+	// Username is the SMTP login for email and the bot name for Slack
+	Username string `yaml:"username,omitempty"`
+
+	// Slack and Teams
+	WebhookURL string `yaml:"webhookURL,omitempty"`
+	Channel    string `yaml:"channel,omitempty"`
+	IconEmoji  string `yaml:"iconEmoji,omitempty"`
+
+	// GitHub
+	Token       string   `yaml:"token,omitempty"`
+	Owner       string   `yaml:"owner,omitempty"`
+	Repository  string   `yaml:"repository,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	AssignUsers []string `yaml:"assignUsers,omitempty"`
+	APIURL      string   `yaml:"apiURL,omitempty"` // defaults to https://api.github.com
+
+	// Webhook
+	URL        string            `yaml:"url,omitempty"`
+	Secret     string            `yaml:"secret,omitempty"`     // HMAC-SHA256 signing key
+	Headers    map[string]string `yaml:"headers,omitempty"`    // extra request headers
+	MaxRetries int               `yaml:"maxRetries,omitempty"` // negative disables retries
+
+	// MissingEnv lists ${VAR} references that were unset when the config was loaded
+	MissingEnv []string `yaml:"-"`
+}
+
+// IsBuiltinChannel reports whether name is one of the env-configured channel types
+func IsBuiltinChannel(name string) bool {
+	switch name {
+	case ChannelTypeEmail, ChannelTypeSlack, ChannelTypeTeams, ChannelTypeGitHub, ChannelTypeWebhook:
+		return true
+	}
+	return false
+}
+
+// HasChannel reports whether a query may reference name
+func (c *Config) HasChannel(name string) bool {
+	if IsBuiltinChannel(name) {
+		return true
+	}
+	_, ok := c.Notifications.Channels[name]
+	return ok
+}
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolateChannels replaces ${VAR} references in every channel with values
+// from the environment, recording any that are unset
+func (c *Config) interpolateChannels() {
+	for name, channel := range c.Notifications.Channels {
+		missing := make(map[string]bool)
+		expand := func(value string) string {
+			return envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+				key := envRefPattern.FindStringSubmatch(ref)[1]
+				val, ok := os.LookupEnv(key)
+				if !ok {
+					missing[key] = true
+				}
+				return val
+			})
+		}
+		expandAll := func(values []string) []string {
+			for i := range values {
+				values[i] = expand(values[i])
+			}
+			return values
+		}
+
+		channel.Type = expand(channel.Type)
+		channel.SMTPHost = expand(channel.SMTPHost)
+		channel.Username = expand(channel.Username)
+		channel.Password = expand(channel.Password)
+		channel.From = expand(channel.From)
+		channel.To = expandAll(channel.To)
+		channel.WebhookURL = expand(channel.WebhookURL)
+		channel.Channel = expand(channel.Channel)
+		channel.IconEmoji = expand(channel.IconEmoji)
+		channel.Token = expand(channel.Token)
+		channel.Owner = expand(channel.Owner)
+		channel.Repository = expand(channel.Repository)
+		channel.Labels = expandAll(channel.Labels)
+		channel.AssignUsers = expandAll(channel.AssignUsers)
+		channel.APIURL = expand(channel.APIURL)
+		channel.URL = expand(channel.URL)
+		channel.Secret = expand(channel.Secret)
+		for header, value := range channel.Headers {
+			channel.Headers[header] = expand(value)
+		}
+
+		channel.MissingEnv = nil
+		for key := range missing {
+			channel.MissingEnv = append(channel.MissingEnv, key)
+		}
+		sort.Strings(channel.MissingEnv)
+
+		c.Notifications.Channels[name] = channel
+	}
+}
+
+// Validate checks a named channel definition
+func (ch ChannelConfig) Validate() error {
+	if len(ch.MissingEnv) > 0 {
+		return fmt.Errorf("unset environment variables: %s", strings.Join(ch.MissingEnv, ", "))
+	}
+
+	if ch.Template != "" && ch.Type != ChannelTypeEmail && ch.Type != ChannelTypeSlack && ch.Type != ChannelTypeGitHub {
+		return fmt.Errorf("%s channels do not support templates", ch.Type)
+	}
+
+	switch ch.Type {
+	case ChannelTypeEmail:
+		if ch.SMTPHost == "" {
+			return fmt.Errorf("email channel requires smtpHost")
+		}
+		if ch.From == "" {
+			return fmt.Errorf("email channel requires from")
+		}
+	case ChannelTypeSlack:
+		if ch.WebhookURL == "" {
+			return fmt.Errorf("slack channel requires webhookURL")
+		}
+	case ChannelTypeTeams:
+		if ch.WebhookURL == "" {
+			return fmt.Errorf("teams channel requires webhookURL")
+		}
+	case ChannelTypeGitHub:
+		if ch.Token == "" || ch.Owner == "" || ch.Repository == "" {
+			return fmt.Errorf("github channel requires token, owner and repository")
+		}
+		if ch.APIURL != "" && !strings.HasPrefix(ch.APIURL, "https://") && !strings.HasPrefix(ch.APIURL, "http://") {
+			return fmt.Errorf("github apiURL must start with http:// or https://")
+		}
+	case ChannelTypeWebhook:
+		if ch.URL == "" {
+			return fmt.Errorf("webhook channel requires url")
+		}
+		if !strings.HasPrefix(ch.URL, "https://") && !strings.HasPrefix(ch.URL, "http://") {
+			return fmt.Errorf("webhook url must start with http:// or https://")
+		}
+	case "":
+		return fmt.Errorf("channel type is required")
+	default:
+		return fmt.Errorf("unknown channel type '%s'", ch.Type)
+	}
+
+	return nil
+}
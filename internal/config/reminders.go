@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseReminders parses deadline reminder offsets such as "7d", "3d" or
+// "24h" and returns them from longest to shortest. Days are written with a
+// "d" suffix; anything else is a Go duration.
+func ParseReminders(offsets []string) ([]time.Duration, error) {
+	parsed := make([]time.Duration, 0, len(offsets))
+	seen := make(map[time.Duration]bool)
+	for _, offset := range offsets {
+		d, err := parseReminderOffset(offset)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[d] {
+			seen[d] = true
+			parsed = append(parsed, d)
+		}
+	}
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i] > parsed[j] })
+	return parsed, nil
+}
+
+func parseReminderOffset(offset string) (time.Duration, error) {
+	d, err := parseDaysOrDuration(offset)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reminder '%s': expected days such as '7d' or a duration such as '24h'", offset)
+	}
+
+	if d < time.Hour {
+		return 0, fmt.Errorf("reminder '%s' must be at least one hour before the deadline", offset)
+	}
+	if d > 365*24*time.Hour {
+		return 0, fmt.Errorf("reminder '%s' cannot be more than 365 days before the deadline", offset)
+	}
+	return d, nil
+}
+
+// parseDaysOrDuration parses a whole number of days written with a "d"
+// suffix, or else a Go duration
+func parseDaysOrDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// FormatReminder writes an offset the way people say it: "7 days",
+// "24 hours", "90 minutes"
+func FormatReminder(d time.Duration) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	switch {
+	case d%(24*time.Hour) == 0 && d > 24*time.Hour:
+		return plural(int64(d/(24*time.Hour)), "day")
+	case d%time.Hour == 0:
+		return plural(int64(d/time.Hour), "hour")
+	default:
+		return plural(int64(d/time.Minute), "minute")
+	}
+}
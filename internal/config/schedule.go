@@ -0,0 +1,274 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSchedule is used for queries that do not set a schedule
+const DefaultSchedule = "@daily"
+
+// Schedule decides when a query should next run
+type Schedule interface {
+	// Next returns the first run time strictly after t
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a query schedule. Accepted forms are a Go duration
+// ("90m", "6h"), "@every <duration>", the descriptors @hourly, @daily and
+// @weekly, "daily HH:MM", "weekly <weekday> [HH:MM]", or a standard
+// five-field cron expression. Clock times are evaluated in UTC.
+func ParseSchedule(spec string) (Schedule, error) {
+	return ParseScheduleIn(spec, time.UTC)
+}
+
+// ParseScheduleIn parses a schedule like ParseSchedule, evaluating clock
+// times in loc
+func ParseScheduleIn(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		spec = DefaultSchedule
+	}
+
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		return parseInterval(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+	}
+	if strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("unknown schedule descriptor '%s'", spec)
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) > 0 && (strings.EqualFold(fields[0], "daily") || strings.EqualFold(fields[0], "weekly")) {
+		cron, err := calendarToCron(fields)
+		if err != nil {
+			return nil, err
+		}
+		fields = strings.Fields(cron)
+	}
+
+	if len(fields) == 5 {
+		return parseCron(fields, loc)
+	}
+
+	return parseInterval(spec)
+}
+
+var weekdayNames = map[string]int{
+	"sunday": 0, "sun": 0,
+	"monday": 1, "mon": 1,
+	"tuesday": 2, "tue": 2,
+	"wednesday": 3, "wed": 3,
+	"thursday": 4, "thu": 4,
+	"friday": 5, "fri": 5,
+	"saturday": 6, "sat": 6,
+}
+
+// calendarToCron converts "daily HH:MM" and "weekly <weekday> [HH:MM]" into
+// cron fields. The time defaults to midnight.
+func calendarToCron(fields []string) (string, error) {
+	spec := strings.Join(fields, " ")
+	weekday := "*"
+	rest := fields[1:]
+
+	if strings.EqualFold(fields[0], "weekly") {
+		if len(rest) == 0 {
+			return "", fmt.Errorf("schedule '%s' needs a weekday, e.g. 'weekly monday 08:00'", spec)
+		}
+		day, ok := weekdayNames[strings.ToLower(rest[0])]
+		if !ok {
+			return "", fmt.Errorf("unknown weekday '%s' in schedule '%s'", rest[0], spec)
+		}
+		weekday = strconv.Itoa(day)
+		rest = rest[1:]
+	}
+
+	hour, minute := 0, 0
+	switch len(rest) {
+	case 0:
+	case 1:
+		clock, err := time.Parse("15:04", rest[0])
+		if err != nil {
+			return "", fmt.Errorf("invalid time '%s' in schedule '%s': expected HH:MM", rest[0], spec)
+		}
+		hour, minute = clock.Hour(), clock.Minute()
+	default:
+		return "", fmt.Errorf("invalid schedule '%s'", spec)
+	}
+
+	return fmt.Sprintf("%d %d * * %s", minute, hour, weekday), nil
+}
+
+// IntervalSchedule runs a query at a fixed interval
+type IntervalSchedule struct {
+	Interval time.Duration
+}
+
+// Next implements Schedule
+func (s IntervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.Interval)
+}
+
+func parseInterval(spec string) (Schedule, error) {
+	d, err := time.ParseDuration(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': expected a duration or cron expression", spec)
+	}
+	if d < time.Minute {
+		return nil, fmt.Errorf("schedule interval %v is shorter than one minute", d)
+	}
+	return IntervalSchedule{Interval: d}, nil
+}
+
+// CronSchedule is a five-field cron expression (minute hour day-of-month
+// month day-of-week) evaluated in a fixed location, UTC unless set
+type CronSchedule struct {
+	loc *time.Location
+
+	minutes  [60]bool
+	hours    [24]bool
+	days     [32]bool
+	months   [13]bool
+	weekdays [7]bool
+
+	// Cron matches either day field when both are restricted
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+func parseCron(fields []string, loc *time.Location) (Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	s := &CronSchedule{loc: loc}
+	if err := parseCronField(fields[0], 0, 59, s.minutes[:]); err != nil {
+		return nil, fmt.Errorf("minute field: %w", err)
+	}
+	if err := parseCronField(fields[1], 0, 23, s.hours[:]); err != nil {
+		return nil, fmt.Errorf("hour field: %w", err)
+	}
+	if err := parseCronField(fields[2], 1, 31, s.days[:]); err != nil {
+		return nil, fmt.Errorf("day-of-month field: %w", err)
+	}
+	if err := parseCronField(fields[3], 1, 12, s.months[:]); err != nil {
+		return nil, fmt.Errorf("month field: %w", err)
+	}
+
+	// Accept 7 as Sunday like most cron implementations
+	weekdays := make([]bool, 8)
+	if err := parseCronField(fields[4], 0, 7, weekdays); err != nil {
+		return nil, fmt.Errorf("day-of-week field: %w", err)
+	}
+	copy(s.weekdays[:], weekdays[:7])
+	s.weekdays[0] = s.weekdays[0] || weekdays[7]
+
+	s.daysRestricted = fields[2] != "*"
+	s.weekdaysRestricted = fields[4] != "*"
+
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression '%s' never matches", strings.Join(fields, " "))
+	}
+	return s, nil
+}
+
+// parseCronField handles "*", lists, ranges and steps such as "*/15" or "1-5"
+func parseCronField(field string, min, max int, set []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step in '%s'", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("invalid range '%s'", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return fmt.Errorf("invalid value '%s'", part)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return fmt.Errorf("'%s' is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// Next implements Schedule. It returns the zero time if the expression never matches.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := s.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	next := t.In(loc).Truncate(time.Minute).Add(time.Minute)
+
+	// Every valid expression matches at least once within four years
+	limit := next.AddDate(4, 0, 0)
+	for next.Before(limit) {
+		if !s.months[next.Month()] {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hours[next.Hour()] {
+			// Daylight saving changes can make the next wall-clock hour
+			// ambiguous; always move forward
+			candidate := time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+			if !candidate.After(next) {
+				candidate = next.Truncate(time.Hour).Add(time.Hour)
+			}
+			next = candidate
+			continue
+		}
+		if !s.minutes[next.Minute()] {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom := s.days[t.Day()]
+	dow := s.weekdays[t.Weekday()]
+	if s.daysRestricted && s.weekdaysRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // The DST cases need zones that may be missing on the host
+)
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		zone string // Empty evaluates in UTC
+		from string
+		want string
+	}{
+		{"interval", "90m", "", "2024-03-15T10:15:30Z", "2024-03-15T11:45:30Z"},
+		{"every descriptor", "@every 2h", "", "2024-03-15T10:15:00Z", "2024-03-15T12:15:00Z"},
+		{"hourly", "@hourly", "", "2024-03-15T10:15:00Z", "2024-03-15T11:00:00Z"},
+		{"next is strictly after a matching time", "*/15 * * * *", "", "2024-03-15T10:15:00Z", "2024-03-15T10:30:00Z"},
+		{"seconds are dropped", "*/15 * * * *", "", "2024-03-15T10:14:59Z", "2024-03-15T10:15:00Z"},
+		{"daily clock time", "daily 08:30", "", "2024-03-15T08:30:00Z", "2024-03-16T08:30:00Z"},
+		{"default schedule", "", "", "2024-03-15T10:00:00Z", "2024-03-16T00:00:00Z"},
+
+		// Day, month and year rollovers
+		{"weekdays roll over the weekend", "0 9 * * 1-5", "", "2024-03-15T10:00:00Z", "2024-03-18T09:00:00Z"},
+		{"weekly named day", "weekly sunday 08:00", "", "2024-03-16T09:00:00Z", "2024-03-17T08:00:00Z"},
+		{"7 is Sunday", "0 0 * * 7", "", "2024-03-15T00:00:00Z", "2024-03-17T00:00:00Z"},
+		{"day 31 skips short months", "0 0 31 * *", "", "2024-04-01T00:00:00Z", "2024-05-31T00:00:00Z"},
+		{"February 29 waits for a leap year", "0 0 29 2 *", "", "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"year rollover", "0 0 1 1 *", "", "2024-12-31T23:59:00Z", "2025-01-01T00:00:00Z"},
+		{"month restriction rolls into next year", "0 0 * 2 *", "", "2024-02-29T23:00:00Z", "2025-02-01T00:00:00Z"},
+		{"restricted day fields match either", "0 0 1 * 1", "", "2024-03-15T00:00:00Z", "2024-03-18T00:00:00Z"},
+		{"weekday step", "0 12 * * */2", "", "2024-03-17T13:00:00Z", "2024-03-19T12:00:00Z"},
+
+		// Clock times in a zone with daylight saving
+		{"evaluated in the location", "daily 08:00", "America/New_York", "2024-03-15T11:00:00Z", "2024-03-15T08:00:00-04:00"},
+		{"spring forward skips the missing hour", "30 2 * * *", "America/New_York", "2024-03-10T00:00:00-05:00", "2024-03-11T02:30:00-04:00"},
+		{"hourly across spring forward", "0 * * * *", "America/New_York", "2024-03-10T01:30:00-05:00", "2024-03-10T03:00:00-04:00"},
+		{"fall back repeats the hour", "30 1 * * *", "America/New_York", "2024-11-03T01:30:00-04:00", "2024-11-03T01:30:00-05:00"},
+		{"hour after fall back", "0 2 * * *", "America/New_York", "2024-11-03T01:30:00-05:00", "2024-11-03T02:00:00-05:00"},
+		{"two hour fall back", "30 2 * * *", "Antarctica/Troll", "2024-10-27T01:30:00Z", "2024-10-27T02:30:00Z"},
+		{"half hour offset", "0 9 * * *", "Asia/Kolkata", "2024-03-15T04:00:00Z", "2024-03-16T09:00:00+05:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := time.UTC
+			if tt.zone != "" {
+				var err error
+				if loc, err = time.LoadLocation(tt.zone); err != nil {
+					t.Fatalf("loading %s: %v", tt.zone, err)
+				}
+			}
+			schedule, err := ParseScheduleIn(tt.spec, loc)
+			if err != nil {
+				t.Fatalf("ParseScheduleIn(%q): %v", tt.spec, err)
+			}
+			from := mustParseTime(t, tt.from)
+			want := mustParseTime(t, tt.want)
+
+			got := schedule.Next(from)
+			if !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format(time.RFC3339), tt.want)
+			}
+			if !got.After(from) {
+				t.Errorf("Next(%s) = %s is not after the start", tt.from, got.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{"@yearly", "unknown schedule descriptor '@yearly'"},
+		{"weekly", "needs a weekday"},
+		{"weekly funday 08:00", "unknown weekday 'funday'"},
+		{"daily 25:00", "invalid time '25:00'"},
+		{"daily 08:00 09:00", "invalid schedule 'daily 08:00 09:00'"},
+		{"30s", "shorter than one minute"},
+		{"@every 10s", "shorter than one minute"},
+		{"sometimes", "expected a duration or cron expression"},
+		{"60 * * * *", "minute field: '60' is outside 0-59"},
+		{"* 24 * * *", "hour field: '24' is outside 0-23"},
+		{"* * 0 * *", "day-of-month field: '0' is outside 1-31"},
+		{"* * * 13 *", "month field: '13' is outside 1-12"},
+		{"* * * * 8", "day-of-week field: '8' is outside 0-7"},
+		{"*/0 * * * *", "minute field: invalid step in '*/0'"},
+		{"5-1 * * * *", "minute field: '5-1' is outside 0-59"},
+		{"1-x * * * *", "minute field: invalid range '1-x'"},
+		{"a * * * *", "minute field: invalid value 'a'"},
+		{"0 0 30 2 *", "never matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseSchedule(tt.spec)
+			if err == nil {
+				t.Fatalf("ParseSchedule(%q) succeeded, want an error", tt.spec)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", value, err)
+	}
+	return parsed
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ScoringConfig ranks a query's matches by relevance. Every rule adds its
+// points when it applies; negative points push an opportunity down.
+type ScoringConfig struct {
+	Keywords     map[string]float64 `yaml:"keywords,omitempty"` // Points per keyword in the title; half when only in the description
+	NAICS        NAICSScoring       `yaml:"naics,omitempty"`
+	SetAsides    map[string]float64 `yaml:"setAsides,omitempty"`    // Points by set-aside code, e.g. SBA or 8A
+	Agencies     map[string]float64 `yaml:"agencies,omitempty"`     // Points when the agency path contains the name
+	Deadline     RangeScoring       `yaml:"deadline,omitempty"`     // Points when the days until the deadline fall in range
+	Value        RangeScoring       `yaml:"value,omitempty"`        // Points when the award amount falls in range
+	MinScore     float64            `yaml:"minScore,omitempty"`     // Matches scoring below this are dropped
+	HighPriority float64            `yaml:"highPriority,omitempty"` // Scores at or above this are high priority
+	LowPriority  float64            `yaml:"lowPriority,omitempty"`  // Scores below this are low priority
+}
+
+// NAICSScoring rewards opportunities in the listed NAICS codes. A code that
+// only shares the first PrefixLength digits earns the prefix points.
+type NAICSScoring struct {
+	Codes        []string `yaml:"codes,omitempty"`
+	Exact        float64  `yaml:"exact,omitempty"`
+	Prefix       float64  `yaml:"prefix,omitempty"`
+	PrefixLength int      `yaml:"prefixLength,omitempty"` // Defaults to 4
+}
+
+// RangeScoring awards points when a number falls within [Min, Max]. A zero
+// Max leaves the range open-ended.
+type RangeScoring struct {
+	Min    float64 `yaml:"min,omitempty"`
+	Max    float64 `yaml:"max,omitempty"`
+	Points float64 `yaml:"points,omitempty"`
+}
+
+// DefaultNAICSPrefixLength is how many digits count as a NAICS prefix match
+const DefaultNAICSPrefixLength = 4
+
+// Enabled reports whether any scoring rule or threshold is configured
+func (s ScoringConfig) Enabled() bool {
+	return len(s.Keywords) > 0 || len(s.NAICS.Codes) > 0 || len(s.SetAsides) > 0 ||
+		len(s.Agencies) > 0 || s.Deadline.Points != 0 || s.Value.Points != 0 ||
+		s.MinScore != 0 || s.HighPriority != 0 || s.LowPriority != 0
+}
+
+// Validate checks the scoring rules for mistakes
+func (s ScoringConfig) Validate() error {
+	for keyword := range s.Keywords {
+		if strings.TrimSpace(keyword) == "" {
+			return errors.New("keywords cannot contain an empty keyword")
+		}
+	}
+
+	for agency := range s.Agencies {
+		if strings.TrimSpace(agency) == "" {
+			return errors.New("agencies cannot contain an empty name")
+		}
+	}
+
+	if s.NAICS.PrefixLength < 0 || s.NAICS.PrefixLength > 6 {
+		return fmt.Errorf("naics prefixLength must be 0 (default 4) to 6, got %d", s.NAICS.PrefixLength)
+	}
+	if len(s.NAICS.Codes) == 0 && (s.NAICS.Exact != 0 || s.NAICS.Prefix != 0) {
+		return errors.New("naics points are set but no codes are listed")
+	}
+
+	if err := s.Deadline.validate("deadline"); err != nil {
+		return err
+	}
+	if err := s.Value.validate("value"); err != nil {
+		return err
+	}
+
+	if s.HighPriority != 0 && s.LowPriority != 0 && s.LowPriority > s.HighPriority {
+		return errors.New("lowPriority cannot be greater than highPriority")
+	}
+
+	return nil
+}
+
+func (r RangeScoring) validate(name string) error {
+	if r.Min < 0 {
+		return fmt.Errorf("%s min cannot be negative", name)
+	}
+	if r.Max > 0 && r.Min > r.Max {
+		return fmt.Errorf("%s min cannot be greater than max", name)
+	}
+	return nil
+}
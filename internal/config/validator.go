@@ -8,6 +8,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/filter"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
 )
 
 // ValidationError represents a configuration validation error
@@ -52,6 +55,16 @@ func (cv *ConfigValidator) Validate(config *Config) *ValidationResult {
 
 	// Validate queries
 	cv.validateQueries(config, result)
+	cv.validateChannels(config, result)
+	cv.validateTemplates(config, result)
+
+	if _, err := config.Notifications.Digest.ParseSchedule(); err != nil {
+		cv.addError(result, "notifications.digest", config.Notifications.Digest.Schedule, err.Error())
+	}
+
+	if _, _, err := config.Notifications.Outbox.ParsePolicy(); err != nil {
+		cv.addError(result, "notifications.outbox", config.Notifications.Outbox.Retention, err.Error())
+	}
 
 	// Set overall validity
 	result.Valid = len(result.Errors) == 0 && (!cv.strict || len(result.Warnings) == 0)
@@ -75,6 +88,14 @@ func (cv *ConfigValidator) validateQueries(config *Config, result *ValidationRes
 		// Validate individual query
 		cv.validateQuery(query, fieldPrefix, result)
 
+		// Channels may name a built-in type or a channel from the notifications block
+		for j, channel := range query.Notification.Channels {
+			if !config.HasChannel(channel) {
+				cv.addError(result, fmt.Sprintf("%s.notification.channels[%d]", fieldPrefix, j), channel,
+					"Unknown notification channel - use email, slack, teams, github, webhook or a name from notifications.channels")
+			}
+		}
+
 		// Check for enabled queries
 		if query.Enabled {
 			enabledCount++
@@ -118,6 +139,43 @@ func (cv *ConfigValidator) validateQuery(query Query, fieldPrefix string, result
 
 	// Validate notification configuration
 	cv.validateNotificationConfig(query.Notification, fieldPrefix+".notification", result)
+
+	// Validate schedule
+	if _, err := ParseSchedule(query.Schedule); err != nil {
+		cv.addError(result, fieldPrefix+".schedule", query.Schedule, err.Error())
+	}
+
+	// Validate filter expression
+	if query.Advanced.Filter != "" {
+		if _, err := filter.Compile(query.Advanced.Filter); err != nil {
+			cv.addError(result, fieldPrefix+".advanced.filter", query.Advanced.Filter, err.Error())
+		}
+	}
+
+	// Validate scoring rules
+	if err := query.Scoring.Validate(); err != nil {
+		cv.addError(result, fieldPrefix+".scoring", "", err.Error())
+	}
+
+	// Award tracking needs award notices in the results
+	if query.Advanced.TrackAwards {
+		if ptype, exists := query.Parameters["ptype"]; exists && !cv.includesAwardType(ptype) {
+			cv.addWarning(result, fieldPrefix+".advanced.trackAwards", fmt.Sprintf("%v", ptype),
+				"Posting types do not include 'a', so no award notices will be found")
+		}
+	}
+}
+
+// includesAwardType reports whether a ptype parameter asks for award notices
+func (cv *ConfigValidator) includesAwardType(value interface{}) bool {
+	for _, item := range cv.extractStringArray(value) {
+		for _, ptype := range strings.Split(item, ",") {
+			if strings.EqualFold(strings.TrimSpace(ptype), "a") {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // validateQueryParameters validates query parameters
@@ -447,17 +505,83 @@ func (cv *ConfigValidator) validateNotificationConfig(notification NotificationC
 		}
 	}
 
-	// Validate channels if present
-	if len(notification.Channels) > 0 {
-		validChannels := map[string]bool{"email": true, "slack": true, "github": true}
-		for i, channel := range notification.Channels {
-			channelField := fmt.Sprintf("%s.channels[%d]", fieldPrefix, i)
-			if !validChannels[strings.ToLower(channel)] {
-				cv.addWarning(result, channelField, channel, 
-					"Unknown notification channel - supported: email, slack, github")
+	if _, err := ParseReminders(notification.Reminders); err != nil {
+		cv.addError(result, fieldPrefix+".reminders", strings.Join(notification.Reminders, ", "), err.Error())
+	}
+}
+
+// validateChannels validates the named channels in the notifications block
+func (cv *ConfigValidator) validateChannels(config *Config, result *ValidationResult) {
+	// Problems in channels used only by disabled queries are warnings
+	used := make(map[string]bool)
+	enabled := make(map[string]bool)
+	for _, query := range config.Queries {
+		for _, channel := range query.Notification.Channels {
+			used[channel] = true
+			if query.Enabled {
+				enabled[channel] = true
 			}
 		}
 	}
+
+	for name, channel := range config.Notifications.Channels {
+		fieldPrefix := fmt.Sprintf("notifications.channels.%s", name)
+
+		if IsBuiltinChannel(name) {
+			cv.addError(result, fieldPrefix, name, "Channel name is reserved for the built-in channel type")
+			continue
+		}
+
+		if err := channel.Validate(); err != nil {
+			if enabled[name] {
+				cv.addError(result, fieldPrefix, channel.Type, err.Error())
+			} else {
+				cv.addWarning(result, fieldPrefix, channel.Type, err.Error())
+			}
+		}
+
+		if !used[name] {
+			cv.addWarning(result, fieldPrefix, name, "Channel is not used by any query")
+		}
+
+		for i, address := range channel.To {
+			if !cv.isValidEmail(address) {
+				cv.addError(result, fmt.Sprintf("%s.to[%d]", fieldPrefix, i), address, "Invalid email address format")
+			}
+		}
+	}
+}
+
+// validateTemplates loads every user-supplied template the queries and
+// channels name. Templates used only by disabled queries are warnings.
+func (cv *ConfigValidator) validateTemplates(config *Config, result *ValidationResult) {
+	dir := config.Notifications.TemplatePath()
+	report := func(field, name string, enabled bool) {
+		if name == "" {
+			return
+		}
+		if _, err := notify.LoadTemplates(dir, name); err != nil {
+			if enabled {
+				cv.addError(result, field, name, err.Error())
+			} else {
+				cv.addWarning(result, field, name, err.Error())
+			}
+		}
+	}
+
+	enabled := make(map[string]bool)
+	for i, query := range config.Queries {
+		report(fmt.Sprintf("queries[%d].notification.template", i), query.Notification.Template, query.Enabled)
+		for _, channel := range query.Notification.Channels {
+			if query.Enabled {
+				enabled[channel] = true
+			}
+		}
+	}
+
+	for name, channel := range config.Notifications.Channels {
+		report(fmt.Sprintf("notifications.channels.%s.template", name), channel.Template, enabled[name])
+	}
 }
 
 // Helper methods
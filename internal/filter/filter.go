@@ -0,0 +1,250 @@
+// Package filter implements the boolean expression language used by the
+// advanced.filter setting in queries.yaml, for example:
+//
+//	(title ~ "zero trust" OR title ~ "ZTA") AND naics IN [541512, 541519] AND NOT agency ~ "Navy"
+//
+// Expressions are parsed and type checked once by Compile and then
+// evaluated against each opportunity with Match.
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// Expr is a compiled filter expression
+type Expr struct {
+	source string
+	root   node
+}
+
+// Compile parses and type checks an expression. Syntax errors are returned
+// as *SyntaxError with the position of the offending token.
+func Compile(source string) (*Expr, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, &SyntaxError{Pos: 1, Message: "empty expression", Source: source}
+	}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{src: source, tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s, expected AND, OR or end of expression", t.describe())
+	}
+
+	return &Expr{source: source, root: root}, nil
+}
+
+// String returns the expression as written in the configuration
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Match reports whether the opportunity satisfies the expression
+func (e *Expr) Match(opp samgov.Opportunity) bool {
+	return e.MatchAt(opp, time.Now())
+}
+
+// MatchAt evaluates the expression with day-based fields measured from now
+func (e *Expr) MatchAt(opp samgov.Opportunity, now time.Time) bool {
+	return e.root.eval(&env{opp: opp, now: now})
+}
+
+type fieldKind int
+
+const (
+	kindText fieldKind = iota
+	kindNumber
+)
+
+// field is a named opportunity attribute usable in expressions. Number
+// fields report ok=false when the opportunity has no value, in which case
+// every comparison against them is false.
+type field struct {
+	name   string
+	kind   fieldKind
+	text   func(samgov.Opportunity) string
+	number func(*env) (float64, bool)
+}
+
+var fields = map[string]field{
+	"title":        {name: "title", kind: kindText, text: func(o samgov.Opportunity) string { return o.Title }},
+	"description":  {name: "description", kind: kindText, text: func(o samgov.Opportunity) string { return o.DescriptionBody() }},
+	"agency":       {name: "agency", kind: kindText, text: func(o samgov.Opportunity) string { return o.FullParentPath }},
+	"type":         {name: "type", kind: kindText, text: func(o samgov.Opportunity) string { return o.Type }},
+	"setaside":     {name: "setaside", kind: kindText, text: func(o samgov.Opportunity) string { return o.TypeOfSetAside }},
+	"naics":        {name: "naics", kind: kindText, text: func(o samgov.Opportunity) string { return o.NAICSCode }},
+	"solicitation": {name: "solicitation", kind: kindText, text: func(o samgov.Opportunity) string { return o.SolicitationNum }},
+	"notice_id":    {name: "notice_id", kind: kindText, text: func(o samgov.Opportunity) string { return o.NoticeID }},
+	"state": {name: "state", kind: kindText, text: func(o samgov.Opportunity) string {
+		if o.PlaceOfPerformance == nil {
+			return ""
+		}
+		return o.PlaceOfPerformance.GetState()
+	}},
+	"value": {name: "value", kind: kindNumber, number: func(e *env) (float64, bool) {
+		if e.opp.Award == nil || e.opp.Award.Amount == nil {
+			return 0, false
+		}
+		return e.opp.Award.GetAmount(), true
+	}},
+	"days_old": {name: "days_old", kind: kindNumber, number: func(e *env) (float64, bool) {
+		posted, ok := samgov.ParseDate(e.opp.PostedDate)
+		if !ok {
+			return 0, false
+		}
+		return float64(int(e.now.Sub(posted).Hours() / 24)), true
+	}},
+	"days_to_deadline": {name: "days_to_deadline", kind: kindNumber, number: func(e *env) (float64, bool) {
+		if e.opp.ResponseDeadline == nil {
+			return 0, false
+		}
+		deadline, ok := samgov.ParseDate(*e.opp.ResponseDeadline)
+		if !ok {
+			return 0, false
+		}
+		return float64(int(deadline.Sub(e.now).Hours() / 24)), true
+	}},
+}
+
+// fieldAliases maps alternative spellings onto canonical field names
+var fieldAliases = map[string]string{
+	"set_aside":  "setaside",
+	"naics_code": "naics",
+	"noticeid":   "notice_id",
+	"amount":     "value",
+}
+
+func lookupField(name string) (field, bool) {
+	key := strings.ToLower(name)
+	if alias, ok := fieldAliases[key]; ok {
+		key = alias
+	}
+	f, ok := fields[key]
+	return f, ok
+}
+
+func fieldNames() string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+type env struct {
+	opp samgov.Opportunity
+	now time.Time
+}
+
+type literal struct {
+	text   string
+	number float64
+}
+
+type node interface {
+	eval(e *env) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) eval(e *env) bool { return n.left.eval(e) && n.right.eval(e) }
+
+type orNode struct{ left, right node }
+
+func (n *orNode) eval(e *env) bool { return n.left.eval(e) || n.right.eval(e) }
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(e *env) bool { return !n.operand.eval(e) }
+
+type compareNode struct {
+	field field
+	op    string
+	value literal
+}
+
+func (n *compareNode) eval(e *env) bool {
+	if n.field.kind == kindNumber {
+		actual, ok := n.field.number(e)
+		if !ok {
+			return false
+		}
+		return compareNumbers(actual, n.op, n.value.number)
+	}
+
+	actual := n.field.text(e.opp)
+	switch n.op {
+	case "~":
+		return containsFold(actual, n.value.text)
+	case "!~":
+		return !containsFold(actual, n.value.text)
+	case "=":
+		return strings.EqualFold(strings.TrimSpace(actual), n.value.text)
+	case "!=":
+		return !strings.EqualFold(strings.TrimSpace(actual), n.value.text)
+	}
+	return false
+}
+
+type inNode struct {
+	field  field
+	values []literal
+}
+
+func (n *inNode) eval(e *env) bool {
+	if n.field.kind == kindNumber {
+		actual, ok := n.field.number(e)
+		if !ok {
+			return false
+		}
+		for _, v := range n.values {
+			if actual == v.number {
+				return true
+			}
+		}
+		return false
+	}
+
+	actual := strings.TrimSpace(n.field.text(e.opp))
+	for _, v := range n.values {
+		if strings.EqualFold(actual, v.text) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareNumbers(actual float64, op string, want float64) bool {
+	switch op {
+	case "=":
+		return actual == want
+	case "!=":
+		return actual != want
+	case "<":
+		return actual < want
+	case "<=":
+		return actual <= want
+	case ">":
+		return actual > want
+	case ">=":
+		return actual >= want
+	}
+	panic(fmt.Sprintf("filter: unchecked operator %q", op))
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,123 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	deadline := "2024-04-04T12:00:00Z"
+	opp := samgov.Opportunity{
+		NoticeID:         "abc123",
+		Title:            "Zero Trust Architecture",
+		FullParentPath:   "DEPT OF DEFENSE.DISA",
+		Type:             "Solicitation",
+		NAICSCode:        "541512",
+		PostedDate:       "2024-03-05",
+		ResponseDeadline: &deadline,
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		// AND binds tighter than OR, NOT tighter than AND
+		{"AND before OR", `title ~ "cloud" AND agency ~ "navy" OR naics = 541512`, true},
+		{"parentheses group OR", `title ~ "cloud" AND (agency ~ "navy" OR naics = 541512)`, false},
+		{"lowercase keywords keep precedence", `title ~ "zero" or title ~ "cloud" and naics = 1`, true},
+		{"NOT before AND", `NOT title ~ "zero" AND naics = 999`, false},
+		{"NOT of a group", `NOT (title ~ "zero" AND naics = 999)`, true},
+		{"symbolic operators", `title ~ "ZERO" && !(agency ~ "navy") || type = "award"`, true},
+
+		{"contains is case-insensitive", `title ~ "trust arch"`, true},
+		{"not contains", `agency !~ "navy"`, true},
+		{"equals is case-insensitive", `type = "solicitation"`, true},
+		{"double equals", `type == "Solicitation"`, true},
+		{"not equals", `type != "Solicitation"`, false},
+		{"IN with mixed literals", `naics IN [541511, "541512"]`, true},
+		{"NOT IN", `naics NOT IN [541511, 541519]`, true},
+		{"field alias", `naics_code = 541512 AND noticeid = "ABC123"`, true},
+		{"escaped quote", `title !~ "zero \"trust\""`, true},
+
+		{"days_old rounds down", `days_old >= 10 AND days_old < 11`, true},
+		{"days_to_deadline", `days_to_deadline = 20`, true},
+		{"missing value never compares", `value > 0 OR value <= 0 OR value IN [0]`, false},
+		{"NOT of a missing value", `NOT amount > 0`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.expr, err)
+			}
+			if got := expr.MatchAt(opp, now); got != tt.want {
+				t.Errorf("MatchAt(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantPos int
+		wantMsg string
+	}{
+		{"empty", "  ", 1, "empty expression"},
+		{"missing value", `title ~`, 8, "expected a quoted string or number, found end of expression"},
+		{"unknown field", `titel ~ "x"`, 1, `unknown field "titel"`},
+		{"unclosed parenthesis", `title ~ "x" AND (agency ~ "y"`, 30, "expected ')' to close '(' at position 17"},
+		{"text operator on a number", `value ~ "10"`, 7, `operator ~ needs a text field, but "value" is a number`},
+		{"number operator on text", `title > 5`, 7, `operator > needs a number field, but "title" is text`},
+		{"text value for a number", `days_old > "ten"`, 12, `field "days_old" is a number, but "ten" is text`},
+		{"unterminated string", `title ~ "x`, 9, "unterminated string"},
+		{"invalid number", `value > 1.2.3`, 9, `invalid number "1.2.3"`},
+		{"missing list separator", `naics IN [541512 541519]`, 18, "expected ',' or ']' in list"},
+		{"IN without a list", `naics IN 541512`, 10, "expected '[' after IN"},
+		{"missing operator", `title "x"`, 7, `expected an operator after "title"`},
+		{"trailing token", `title ~ "x" agency`, 13, "expected AND, OR or end of expression"},
+		{"dangling AND", `title ~ "x" AND`, 16, "expected a field name, NOT or '('"},
+		{"unexpected character", `title # "x"`, 7, `unexpected character '#'`},
+		{"positions count characters, not bytes", `title ~ "é" AND ?`, 17, `unexpected character '?'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Compile(%q) error = %v, want *SyntaxError", tt.expr, err)
+			}
+			if syntaxErr.Pos != tt.wantPos {
+				t.Errorf("Pos = %d, want %d (%s)", syntaxErr.Pos, tt.wantPos, syntaxErr.Message)
+			}
+			if !strings.Contains(syntaxErr.Message, tt.wantMsg) {
+				t.Errorf("Message = %q, want it to contain %q", syntaxErr.Message, tt.wantMsg)
+			}
+			if syntaxErr.Source != tt.expr {
+				t.Errorf("Source = %q, want %q", syntaxErr.Source, tt.expr)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorContext(t *testing.T) {
+	_, err := Compile(`title ~ "x" AND naics IN 541512`)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("error = %v, want *SyntaxError", err)
+	}
+
+	want := "title ~ \"x\" AND naics IN 541512\n" + strings.Repeat(" ", 25) + "^"
+	if got := syntaxErr.Context(); got != want {
+		t.Errorf("Context() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,359 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError reports a problem in a filter expression. Pos is the 1-based
+// character position of the offending token.
+type SyntaxError struct {
+	Pos     int
+	Message string
+	Source  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("filter position %d: %s", e.Pos, e.Message)
+}
+
+// Context renders the expression with a caret under the error position
+func (e *SyntaxError) Context() string {
+	return e.Source + "\n" + strings.Repeat(" ", max(e.Pos-1, 0)) + "^"
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokIn
+)
+
+type token struct {
+	kind tokenKind
+	text string // Operator, identifier or literal source text
+	val  string // Unquoted string literal
+	pos  int    // 1-based rune position
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.val)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits an expression into tokens. Keywords are case-insensitive, and
+// &&, || and ! are accepted for AND, OR and NOT.
+func lex(src string) ([]token, error) {
+	runes := []rune(src)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(' || r == ')' || r == '[' || r == ']' || r == ',':
+			kind := map[rune]tokenKind{'(': tokLParen, ')': tokRParen, '[': tokLBracket, ']': tokRBracket, ',': tokComma}[r]
+			tokens = append(tokens, token{kind: kind, text: string(r), pos: pos})
+			i++
+
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			closed := false
+			for j < len(runes) {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					b.WriteRune(runes[j+1])
+					j += 2
+					continue
+				}
+				if runes[j] == r {
+					closed = true
+					break
+				}
+				b.WriteRune(runes[j])
+				j++
+			}
+			if !closed {
+				return nil, &SyntaxError{Pos: pos, Message: "unterminated string", Source: src}
+			}
+			tokens = append(tokens, token{kind: tokString, text: string(runes[i : j+1]), val: b.String(), pos: pos})
+			i = j + 1
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			text := string(runes[i:j])
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, &SyntaxError{Pos: pos, Message: fmt.Sprintf("invalid number %q", text), Source: src}
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, pos: pos})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			text := string(runes[i:j])
+			kind := tokIdent
+			switch strings.ToUpper(text) {
+			case "AND":
+				kind = tokAnd
+			case "OR":
+				kind = tokOr
+			case "NOT":
+				kind = tokNot
+			case "IN":
+				kind = tokIn
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: pos})
+			i = j
+
+		default:
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch {
+			case two == "&&":
+				tokens = append(tokens, token{kind: tokAnd, text: two, pos: pos})
+				i += 2
+			case two == "||":
+				tokens = append(tokens, token{kind: tokOr, text: two, pos: pos})
+				i += 2
+			case two == "!~" || two == "!=" || two == "<=" || two == ">=" || two == "==":
+				tokens = append(tokens, token{kind: tokOp, text: two, pos: pos})
+				i += 2
+			case r == '!':
+				tokens = append(tokens, token{kind: tokNot, text: "!", pos: pos})
+				i++
+			case r == '~' || r == '=' || r == '<' || r == '>':
+				tokens = append(tokens, token{kind: tokOp, text: string(r), pos: pos})
+				i++
+			default:
+				return nil, &SyntaxError{Pos: pos, Message: fmt.Sprintf("unexpected character %q", r), Source: src}
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+// parser is a recursive-descent parser over the token stream:
+//
+//	expr       = and { OR and }
+//	and        = unary { AND unary }
+//	unary      = NOT unary | "(" expr ")" | comparison
+//	comparison = field op literal | field [NOT] IN "[" literal { "," literal } "]"
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Pos: t.pos, Message: fmt.Sprintf(format, args...), Source: p.src}
+}
+
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+
+	case tokLParen:
+		p.next()
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected ')' to close '(' at position %d, found %s", t.pos, closing.describe())
+		}
+		return inner, nil
+
+	case tokIdent:
+		return p.parseComparison()
+
+	default:
+		return nil, p.errorf(t, "expected a field name, NOT or '(', found %s", t.describe())
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	fieldTok := p.next()
+	f, ok := lookupField(fieldTok.text)
+	if !ok {
+		return nil, p.errorf(fieldTok, "unknown field %q (known fields: %s)", fieldTok.text, fieldNames())
+	}
+
+	opTok := p.next()
+	negate := false
+	if opTok.kind == tokNot && p.peek().kind == tokIn {
+		negate = true
+		opTok = p.next()
+	}
+
+	if opTok.kind == tokIn {
+		values, err := p.parseList(f)
+		if err != nil {
+			return nil, err
+		}
+		var n node = &inNode{field: f, values: values}
+		if negate {
+			n = &notNode{operand: n}
+		}
+		return n, nil
+	}
+
+	if opTok.kind != tokOp {
+		return nil, p.errorf(opTok, "expected an operator after %q (~, !~, =, !=, <, <=, >, >=, IN), found %s", fieldTok.text, opTok.describe())
+	}
+
+	op := opTok.text
+	if op == "==" {
+		op = "="
+	}
+	if err := checkOperator(f, op); err != nil {
+		return nil, p.errorf(opTok, "%s", err.Error())
+	}
+
+	lit, err := p.parseLiteral(f)
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{field: f, op: op, value: lit}, nil
+}
+
+func (p *parser) parseList(f field) ([]literal, error) {
+	open := p.next()
+	if open.kind != tokLBracket {
+		return nil, p.errorf(open, "expected '[' after IN, found %s", open.describe())
+	}
+
+	values := make([]literal, 0)
+	for {
+		lit, err := p.parseLiteral(f)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, lit)
+
+		sep := p.next()
+		if sep.kind == tokRBracket {
+			return values, nil
+		}
+		if sep.kind != tokComma {
+			return nil, p.errorf(sep, "expected ',' or ']' in list, found %s", sep.describe())
+		}
+	}
+}
+
+// parseLiteral reads a value and checks it against the field's type. Text
+// fields accept numbers as written, so naics IN [541512] works unquoted.
+func (p *parser) parseLiteral(f field) (literal, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		if f.kind == kindNumber {
+			return literal{}, p.errorf(t, "field %q is a number, but %s is text", f.name, t.describe())
+		}
+		return literal{text: t.val}, nil
+	case tokNumber:
+		n, _ := strconv.ParseFloat(t.text, 64)
+		return literal{text: t.text, number: n}, nil
+	default:
+		return literal{}, p.errorf(t, "expected a quoted string or number, found %s", t.describe())
+	}
+}
+
+// checkOperator reports whether op can be applied to the field's type
+func checkOperator(f field, op string) error {
+	switch op {
+	case "~", "!~":
+		if f.kind != kindText {
+			return fmt.Errorf("operator %s needs a text field, but %q is a number", op, f.name)
+		}
+	case "<", "<=", ">", ">=":
+		if f.kind != kindNumber {
+			return fmt.Errorf("operator %s needs a number field, but %q is text", op, f.name)
+		}
+	}
+	return nil
+}
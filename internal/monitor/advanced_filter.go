@@ -192,8 +192,8 @@ func (af *AdvancedFilter) matchesAdvancedCriteria(opp samgov.Opportunity, advanc
 // containsAnyKeyword checks if opportunity contains any of the specified keywords
 func (af *AdvancedFilter) containsAnyKeyword(opp samgov.Opportunity, keywords []string) bool {
 	// Combine searchable text fields
-	searchText := strings.ToLower(fmt.Sprintf("%s %s %s %s", 
-		opp.Title, opp.Description, opp.FullParentPath, opp.Type))
+	searchText := strings.ToLower(fmt.Sprintf("%s %s %s %s",
+		opp.Title, opp.DescriptionBody(), opp.FullParentPath, opp.Type))
 
 	for _, keyword := range keywords {
 		if strings.Contains(searchText, strings.ToLower(keyword)) {
@@ -0,0 +1,628 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// API paging defaults
+const (
+	DefaultAPIPageSize = 50
+	MaxAPIPageSize     = 500
+)
+
+// apiIndexTTL is how long the opportunity index is reused between requests.
+// Building it loads every opportunity with its versions.
+const apiIndexTTL = 15 * time.Second
+
+// OpportunityReader is the read-only view of the state served by the API
+type OpportunityReader interface {
+	GetOpportunity(noticeID string) (samgov.OpportunityState, bool)
+	ListOpportunities() []samgov.OpportunityState
+}
+
+// ReadOnlyStore is an OpportunityReader that holds open resources
+type ReadOnlyStore interface {
+	OpportunityReader
+	Close() error
+}
+
+// OpenReadOnlyStore opens the state for serving without running queries. The
+// JSON state file is reloaded whenever it changes on disk, so a separate
+// monitor process can keep updating it; SQLite reads the database directly.
+func OpenReadOnlyStore(backend, statePath string, verbose bool) (ReadOnlyStore, error) {
+	switch backend {
+	case "", StateBackendJSON:
+		if statePath == "" {
+			return nil, fmt.Errorf("json state backend requires a state file path")
+		}
+		reader := &stateFileReader{path: statePath, verbose: verbose}
+		if _, err := reader.current(); err != nil {
+			return nil, err
+		}
+		return reader, nil
+	default:
+		return OpenStateStore(backend, statePath, verbose)
+	}
+}
+
+// stateFileReader serves a JSON state file, reloading it when its
+// modification time changes
+type stateFileReader struct {
+	path    string
+	verbose bool
+
+	mu      sync.Mutex
+	state   *State
+	modTime time.Time
+}
+
+// current returns the latest state, keeping the previous one if the file
+// cannot be read mid-write
+func (r *stateFileReader) current() (*State, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modTime time.Time
+	if info, err := os.Stat(r.path); err == nil {
+		modTime = info.ModTime()
+	}
+	if r.state != nil && modTime.Equal(r.modTime) {
+		return r.state, nil
+	}
+
+	state, err := LoadState(r.path)
+	if err != nil {
+		if r.state != nil {
+			log.Printf("Warning: failed to reload state file, serving previous state: %v", err)
+			return r.state, nil
+		}
+		return nil, err
+	}
+	if r.verbose && r.state != nil {
+		log.Printf("Reloaded state file %s", r.path)
+	}
+	r.state = state
+	r.modTime = modTime
+	return state, nil
+}
+
+func (r *stateFileReader) GetOpportunity(noticeID string) (samgov.OpportunityState, bool) {
+	state, err := r.current()
+	if err != nil {
+		return samgov.OpportunityState{}, false
+	}
+	return state.GetOpportunity(noticeID)
+}
+
+func (r *stateFileReader) ListOpportunities() []samgov.OpportunityState {
+	state, err := r.current()
+	if err != nil {
+		return []samgov.OpportunityState{}
+	}
+	return state.ListOpportunities()
+}
+
+// Close is a no-op; the state file is not held open
+func (r *stateFileReader) Close() error {
+	return nil
+}
+
+// OpportunitySummary is one tracked opportunity as returned by the API, with
+// the fields of its latest version flattened
+type OpportunitySummary struct {
+	NoticeID           string    `json:"notice_id"`
+	Title              string    `json:"title"`
+	SolicitationNumber string    `json:"solicitation_number,omitempty"`
+	Type               string    `json:"type,omitempty"`
+	Agency             string    `json:"agency,omitempty"`
+	NAICSCode          string    `json:"naics_code,omitempty"`
+	SetAside           string    `json:"set_aside,omitempty"`
+	PostedDate         string    `json:"posted_date,omitempty"`
+	Deadline           *string   `json:"deadline,omitempty"`
+	Active             bool      `json:"active"`
+	Queries            []string  `json:"queries"`
+	Stage              string    `json:"stage"`
+	Owner              string    `json:"owner,omitempty"`
+	FirstSeen          time.Time `json:"first_seen"`
+	LastSeen           time.Time `json:"last_seen"`
+	LastModified       time.Time `json:"last_modified"`
+	Versions           int       `json:"versions"`
+	Documents          int       `json:"documents"`
+	UILink             string    `json:"ui_link"`
+
+	deadline time.Time // Parsed deadline; zero when missing or unparseable
+}
+
+// summarizeOpportunity flattens a tracked opportunity for the API
+func summarizeOpportunity(tracked samgov.OpportunityState) OpportunitySummary {
+	opp := trackedOpportunity(tracked)
+	summary := OpportunitySummary{
+		NoticeID:           tracked.NoticeID,
+		Title:              tracked.Title,
+		SolicitationNumber: opp.SolicitationNum,
+		Type:               opp.Type,
+		Agency:             opp.FullParentPath,
+		NAICSCode:          opp.NAICSCode,
+		SetAside:           opp.TypeOfSetAside,
+		PostedDate:         opp.PostedDate,
+		Deadline:           tracked.Deadline,
+		Active:             true,
+		Queries:            tracked.Queries,
+		Stage:              string(tracked.PipelineStage()),
+		Owner:              tracked.Owner,
+		FirstSeen:          tracked.FirstSeen,
+		LastSeen:           tracked.LastSeen,
+		LastModified:       tracked.LastModified,
+		Versions:           len(tracked.Versions),
+		Documents:          len(tracked.Documents),
+		UILink:             opp.UILink,
+	}
+	if summary.Queries == nil {
+		summary.Queries = []string{}
+	}
+	if latest, ok := tracked.LatestVersion(); ok && strings.EqualFold(latest.Snapshot.Active, "no") {
+		summary.Active = false
+	}
+	if tracked.Deadline != nil {
+		summary.deadline, _ = samgov.ParseDate(*tracked.Deadline)
+	}
+	return summary
+}
+
+// OpportunityFilter selects opportunities for an API listing. Empty fields
+// match everything.
+type OpportunityFilter struct {
+	Search         string    // Case-insensitive text in the title, notice ID, solicitation number or agency
+	Query          string    // Name of a query that matched the notice
+	Agency         string    // Case-insensitive text in the agency path
+	NAICS          string    // NAICS code prefix
+	SetAside       string    // Set-aside code
+	Stage          string    // Capture pipeline stage
+	Owner          string    // Capture owner
+	DeadlineAfter  time.Time // Deadline on or after
+	DeadlineBefore time.Time // Deadline before
+	SeenSince      time.Time // First seen on or after
+	ActiveOnly     bool
+}
+
+// Match reports whether summary passes the filter. Opportunities without a
+// deadline never match a deadline window.
+func (f OpportunityFilter) Match(summary OpportunitySummary) bool {
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		found := false
+		for _, field := range []string{summary.Title, summary.NoticeID, summary.SolicitationNumber, summary.Agency} {
+			if strings.Contains(strings.ToLower(field), search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Query != "" {
+		found := false
+		for _, query := range summary.Queries {
+			if strings.EqualFold(query, f.Query) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Agency != "" && !strings.Contains(strings.ToLower(summary.Agency), strings.ToLower(f.Agency)) {
+		return false
+	}
+	if f.NAICS != "" && !strings.HasPrefix(summary.NAICSCode, f.NAICS) {
+		return false
+	}
+	if f.SetAside != "" && !strings.EqualFold(summary.SetAside, f.SetAside) {
+		return false
+	}
+	if f.Stage != "" && summary.Stage != f.Stage {
+		return false
+	}
+	if f.Owner != "" && !strings.EqualFold(summary.Owner, f.Owner) {
+		return false
+	}
+	if !f.DeadlineAfter.IsZero() || !f.DeadlineBefore.IsZero() {
+		if summary.deadline.IsZero() {
+			return false
+		}
+		if !f.DeadlineAfter.IsZero() && summary.deadline.Before(f.DeadlineAfter) {
+			return false
+		}
+		if !f.DeadlineBefore.IsZero() && !summary.deadline.Before(f.DeadlineBefore) {
+			return false
+		}
+	}
+	if !f.SeenSince.IsZero() && summary.FirstSeen.Before(f.SeenSince) {
+		return false
+	}
+	if f.ActiveOnly && !summary.Active {
+		return false
+	}
+	return true
+}
+
+// API sort orders; a leading "-" sorts descending
+var apiSortFields = map[string]func(a, b OpportunitySummary) bool{
+	"deadline": func(a, b OpportunitySummary) bool {
+		// Missing deadlines sort last
+		if a.deadline.IsZero() != b.deadline.IsZero() {
+			return !a.deadline.IsZero()
+		}
+		return a.deadline.Before(b.deadline)
+	},
+	"first_seen":    func(a, b OpportunitySummary) bool { return a.FirstSeen.Before(b.FirstSeen) },
+	"last_modified": func(a, b OpportunitySummary) bool { return a.LastModified.Before(b.LastModified) },
+	"posted":        func(a, b OpportunitySummary) bool { return a.PostedDate < b.PostedDate },
+	"title":         func(a, b OpportunitySummary) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) },
+}
+
+// sortSummaries orders summaries by field, breaking ties by notice ID
+func sortSummaries(summaries []OpportunitySummary, field string) error {
+	descending := strings.HasPrefix(field, "-")
+	less, ok := apiSortFields[strings.TrimPrefix(field, "-")]
+	if !ok {
+		return fmt.Errorf("unknown sort field %q", field)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if descending {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return summaries[i].NoticeID < summaries[j].NoticeID
+	})
+	return nil
+}
+
+// OpportunityPage is the response body of the opportunity listing
+type OpportunityPage struct {
+	Total         int                  `json:"total"`
+	Page          int                  `json:"page"`
+	PerPage       int                  `json:"per_page"`
+	Pages         int                  `json:"pages"`
+	Opportunities []OpportunitySummary `json:"opportunities"`
+}
+
+// APIStats is the response body of /api/stats
+type APIStats struct {
+	TotalOpportunities  int            `json:"total_opportunities"`
+	ActiveOpportunities int            `json:"active_opportunities"`
+	DueWithin7Days      int            `json:"due_within_7_days"`
+	DueWithin30Days     int            `json:"due_within_30_days"`
+	NewLast7Days        int            `json:"new_last_7_days"`
+	ByQuery             map[string]int `json:"by_query"`
+	ByStage             map[string]int `json:"by_stage"`
+	GeneratedAt         time.Time      `json:"generated_at"`
+}
+
+// APIServer serves a read-only JSON API and HTML dashboard over tracked
+// opportunities:
+//
+//	GET /                          dashboard of upcoming deadlines and new notices
+//	GET /api/opportunities         filtered, sorted and paginated listing
+//	GET /api/opportunities/{id}    one opportunity with its version history
+//	GET /api/stats                 counts by deadline window and query
+type APIServer struct {
+	reader  OpportunityReader
+	verbose bool
+
+	mu        sync.Mutex
+	index     []OpportunitySummary
+	indexedAt time.Time
+}
+
+// NewAPIServer creates an API server reading from reader
+func NewAPIServer(reader OpportunityReader, verbose bool) *APIServer {
+	return &APIServer{
+		reader:  reader,
+		verbose: verbose,
+	}
+}
+
+// APIHandler serves the query API and dashboard over the live state
+func (m *Monitor) APIHandler() http.Handler {
+	return NewAPIServer(m.state, m.verbose).Handler()
+}
+
+// Handler returns the HTTP handler for the API and dashboard
+func (s *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/opportunities", s.handleList)
+	mux.HandleFunc("/api/opportunities/", s.handleGet)
+	mux.HandleFunc("/api/stats", s.handleStats)
+	mux.HandleFunc("/", s.handleDashboard)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// summaries returns every tracked opportunity, rebuilding the index when it
+// is older than apiIndexTTL. Callers must not modify the result.
+func (s *APIServer) summaries() []OpportunitySummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil && time.Since(s.indexedAt) < apiIndexTTL {
+		return s.index
+	}
+
+	listed := s.reader.ListOpportunities()
+	index := make([]OpportunitySummary, 0, len(listed))
+	for _, tracked := range listed {
+		// Versions hold the agency and NAICS code; list results may omit them
+		if len(tracked.Versions) == 0 {
+			if full, ok := s.reader.GetOpportunity(tracked.NoticeID); ok {
+				tracked = full
+			}
+		}
+		index = append(index, summarizeOpportunity(tracked))
+	}
+
+	if s.verbose {
+		log.Printf("Indexed %d opportunities for the API", len(index))
+	}
+	s.index = index
+	s.indexedAt = time.Now()
+	return index
+}
+
+// Search returns the opportunities matching filter in the given sort order
+func (s *APIServer) Search(filter OpportunityFilter, sortField string) ([]OpportunitySummary, error) {
+	matches := make([]OpportunitySummary, 0)
+	for _, summary := range s.summaries() {
+		if filter.Match(summary) {
+			matches = append(matches, summary)
+		}
+	}
+	if err := sortSummaries(matches, sortField); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// handleList serves GET /api/opportunities. Query parameters:
+//
+//	q                 text search
+//	query, agency, naics, set_aside
+//	stage, owner      capture pipeline stage and owner
+//	deadline_after    YYYY-MM-DD or RFC 3339
+//	deadline_before   YYYY-MM-DD or RFC 3339
+//	due_within        days from now, e.g. 14
+//	seen_since        YYYY-MM-DD or RFC 3339
+//	active            true to hide inactive notices
+//	sort              deadline, first_seen, last_modified, posted or title; "-" prefix for descending (default -first_seen)
+//	page, per_page    pagination (default 1 and 50, at most 500 per page)
+func (s *APIServer) handleList(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	filter, err := parseOpportunityFilter(params, time.Now())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := parsePositiveInt(params.Get("page"), 1)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "page: "+err.Error())
+		return
+	}
+	perPage, err := parsePositiveInt(params.Get("per_page"), DefaultAPIPageSize)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "per_page: "+err.Error())
+		return
+	}
+	if perPage > MaxAPIPageSize {
+		perPage = MaxAPIPageSize
+	}
+
+	sortField := params.Get("sort")
+	if sortField == "" {
+		sortField = "-first_seen"
+	}
+
+	matches, err := s.Search(filter, sortField)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := OpportunityPage{
+		Total:         len(matches),
+		Page:          page,
+		PerPage:       perPage,
+		Pages:         (len(matches) + perPage - 1) / perPage,
+		Opportunities: []OpportunitySummary{},
+	}
+	if start := (page - 1) * perPage; start < len(matches) {
+		end := start + perPage
+		if end > len(matches) {
+			end = len(matches)
+		}
+		result.Opportunities = matches[start:end]
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGet serves GET /api/opportunities/{id} with the full stored state
+func (s *APIServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	noticeID := strings.TrimPrefix(r.URL.Path, "/api/opportunities/")
+	if noticeID == "" || strings.Contains(noticeID, "/") {
+		writeAPIError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	tracked, ok := s.reader.GetOpportunity(noticeID)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("opportunity %s not found", noticeID))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		OpportunitySummary
+		State samgov.OpportunityState `json:"state"`
+	}{summarizeOpportunity(tracked), tracked})
+}
+
+// handleStats serves GET /api/stats
+func (s *APIServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Stats(time.Now()))
+}
+
+// Stats counts tracked opportunities by deadline window and query
+func (s *APIServer) Stats(now time.Time) APIStats {
+	stats := APIStats{
+		ByQuery:     make(map[string]int),
+		ByStage:     make(map[string]int),
+		GeneratedAt: now.UTC(),
+	}
+	today := now.UTC().Truncate(24 * time.Hour)
+	for _, summary := range s.summaries() {
+		stats.TotalOpportunities++
+		if summary.Active {
+			stats.ActiveOpportunities++
+		}
+		if !summary.deadline.IsZero() && !summary.deadline.Before(today) {
+			if summary.deadline.Before(now.AddDate(0, 0, 7)) {
+				stats.DueWithin7Days++
+			}
+			if summary.deadline.Before(now.AddDate(0, 0, 30)) {
+				stats.DueWithin30Days++
+			}
+		}
+		if !summary.FirstSeen.Before(now.AddDate(0, 0, -7)) {
+			stats.NewLast7Days++
+		}
+		for _, query := range summary.Queries {
+			stats.ByQuery[query]++
+		}
+		stats.ByStage[summary.Stage]++
+	}
+	return stats
+}
+
+// parseOpportunityFilter reads the listing filter from query parameters
+func parseOpportunityFilter(params url.Values, now time.Time) (OpportunityFilter, error) {
+	get := func(name string) string {
+		return strings.TrimSpace(params.Get(name))
+	}
+
+	filter := OpportunityFilter{
+		Search:   get("q"),
+		Query:    get("query"),
+		Agency:   get("agency"),
+		NAICS:    get("naics"),
+		SetAside: get("set_aside"),
+		Owner:    get("owner"),
+	}
+
+	if value := get("stage"); value != "" {
+		stage, err := samgov.ParseStage(value)
+		if err != nil {
+			return filter, fmt.Errorf("stage: %w", err)
+		}
+		filter.Stage = string(stage)
+	}
+
+	dates := []struct {
+		name   string
+		target *time.Time
+	}{
+		{"deadline_after", &filter.DeadlineAfter},
+		{"deadline_before", &filter.DeadlineBefore},
+		{"seen_since", &filter.SeenSince},
+	}
+	for _, date := range dates {
+		value := get(date.name)
+		if value == "" {
+			continue
+		}
+		parsed, ok := samgov.ParseDate(value)
+		if !ok {
+			return filter, fmt.Errorf("%s: invalid date %q (want YYYY-MM-DD or RFC 3339)", date.name, value)
+		}
+		*date.target = parsed
+	}
+
+	if value := get("due_within"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return filter, fmt.Errorf("due_within: must be a number of days, got %q", value)
+		}
+		// Date-only deadlines parse to midnight, so start from today
+		if filter.DeadlineAfter.IsZero() {
+			filter.DeadlineAfter = now.UTC().Truncate(24 * time.Hour)
+		}
+		filter.DeadlineBefore = now.AddDate(0, 0, days)
+	}
+
+	if value := get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("active: must be true or false, got %q", value)
+		}
+		filter.ActiveOnly = active
+	}
+
+	return filter, nil
+}
+
+// parsePositiveInt parses an optional positive integer parameter
+func parsePositiveInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("must be a positive integer, got %q", value)
+	}
+	return n, nil
+}
+
+// writeJSON writes body as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(body); err != nil {
+		log.Printf("Warning: failed to write API response: %v", err)
+	}
+}
+
+// writeAPIError writes a JSON error response
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,357 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// Report keys used when an award does not give an agency, NAICS code or awardee
+const (
+	unknownAgency  = "(unknown agency)"
+	unknownNAICS   = "(no NAICS code)"
+	unknownAwardee = "(unknown awardee)"
+)
+
+// awardMatch is an award notice found by a query that tracks awards
+type awardMatch struct {
+	Query       config.Query
+	Opportunity samgov.Opportunity
+}
+
+// splitAwardNotices separates award notices from the rest of a query's results
+func splitAwardNotices(opportunities []samgov.Opportunity) (others, awards []samgov.Opportunity) {
+	others = make([]samgov.Opportunity, 0, len(opportunities))
+	for _, opp := range opportunities {
+		if samgov.IsAwardNotice(opp.Type) {
+			awards = append(awards, opp)
+		} else {
+			others = append(others, opp)
+		}
+	}
+	return others, awards
+}
+
+// recordAwards records the award notices found this run, links them to the
+// tracked solicitations with the same solicitation number and notifies each
+// query about the tracked opportunities that were newly awarded. Awards
+// recorded earlier whose solicitation was not tracked yet are checked again,
+// so they are linked once it is. It returns the awards linked this run.
+func (m *Monitor) recordAwards(ctx context.Context, awards []awardMatch, report *RunReport) []samgov.AwardRecord {
+	unlinked := make(map[string]samgov.AwardRecord)
+	for _, record := range m.state.ListAwards() {
+		if record.LinkedNoticeID == "" && record.SolicitationNumber != "" {
+			unlinked[record.NoticeID] = record
+		}
+	}
+	if len(awards) == 0 && len(unlinked) == 0 {
+		return nil
+	}
+
+	solicitations := m.solicitationIndex()
+	now := time.Now()
+
+	linked := make([]samgov.AwardRecord, 0)
+	awarded := make(map[string][]samgov.Opportunity)
+	queries := make([]config.Query, 0)
+	link := func(record samgov.AwardRecord, query config.Query, opp samgov.Opportunity) {
+		report.AwardsLinked++
+		linked = append(linked, record)
+		if _, ok := awarded[query.Name]; !ok {
+			queries = append(queries, query)
+		}
+		awarded[query.Name] = append(awarded[query.Name], opp)
+
+		if m.verbose {
+			log.Printf("Award notice %s awards tracked solicitation %s to %s",
+				record.NoticeID, record.LinkedNoticeID, awardeeName(record))
+		}
+	}
+
+	for _, award := range awards {
+		record := samgov.NewAwardRecord(award.Opportunity, award.Query.Name, now)
+		record.LinkedNoticeID = linkedSolicitation(record, solicitations)
+
+		_, wasUnlinked := unlinked[record.NoticeID]
+		delete(unlinked, record.NoticeID)
+		if m.state.RecordAward(record) {
+			report.AwardsRecorded++
+		} else if !wasUnlinked {
+			continue
+		}
+		if record.LinkedNoticeID != "" {
+			link(record, award.Query, award.Opportunity)
+		}
+	}
+
+	// Earlier awards not seen this run are linked from their stored record
+	earlier := make([]samgov.AwardRecord, 0, len(unlinked))
+	for _, record := range unlinked {
+		earlier = append(earlier, record)
+	}
+	sort.Slice(earlier, func(i, j int) bool {
+		if !earlier[i].RecordedAt.Equal(earlier[j].RecordedAt) {
+			return earlier[i].RecordedAt.Before(earlier[j].RecordedAt)
+		}
+		return earlier[i].NoticeID < earlier[j].NoticeID
+	})
+	enabled := make(map[string]config.Query)
+	for _, query := range m.config.GetEnabledQueries() {
+		enabled[query.Name] = query
+	}
+	for _, record := range earlier {
+		if record.LinkedNoticeID = linkedSolicitation(record, solicitations); record.LinkedNoticeID == "" {
+			continue
+		}
+		m.state.RecordAward(record)
+		if query, ok := enabled[record.Query]; ok {
+			link(record, query, awardOpportunity(record))
+		} else {
+			report.AwardsLinked++
+			linked = append(linked, record)
+		}
+	}
+
+	for _, query := range queries {
+		opportunities := awarded[query.Name]
+		if m.dryRun {
+			log.Printf("[DRY RUN] Would send award notification for %d opportunities (%s)", len(opportunities), query.Name)
+			continue
+		}
+
+		if err := m.sendAwardNotification(ctx, query, opportunities); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("Award notification error for '%s': %s", query.Name, err.Error()))
+			log.Printf("Failed to send award notification for query '%s': %v", query.Name, err)
+		} else {
+			report.Notifications++
+		}
+	}
+
+	return linked
+}
+
+// linkedSolicitation returns the tracked solicitation an award notice awards,
+// or "" when none is tracked
+func linkedSolicitation(record samgov.AwardRecord, solicitations map[string]string) string {
+	number := samgov.NormalizeSolicitationNumber(record.SolicitationNumber)
+	if number == "" || solicitations[number] == record.NoticeID {
+		return ""
+	}
+	return solicitations[number]
+}
+
+// awardOpportunity rebuilds an award notice from its stored record
+func awardOpportunity(record samgov.AwardRecord) samgov.Opportunity {
+	opp := samgov.Opportunity{
+		NoticeID:        record.NoticeID,
+		Title:           record.Title,
+		SolicitationNum: record.SolicitationNumber,
+		FullParentPath:  record.Agency,
+		NAICSCode:       record.NAICSCode,
+		TypeOfSetAside:  record.SetAside,
+		UILink:          record.UILink,
+		Award: &samgov.Award{
+			Date:   record.AwardDate,
+			Number: record.AwardNumber,
+		},
+	}
+	if record.Amount != 0 {
+		opp.Award.Amount = record.Amount
+	}
+	if record.Awardee != "" || record.AwardeeUEI != "" {
+		opp.Award.Awardee = &samgov.Awardee{Name: record.Awardee, UEISAM: record.AwardeeUEI}
+	}
+	return opp
+}
+
+// solicitationIndex maps the normalized solicitation numbers of tracked
+// notices, other than award notices, to their notice IDs
+func (m *Monitor) solicitationIndex() map[string]string {
+	index := make(map[string]string)
+	for _, tracked := range m.state.ListOpportunities() {
+		// Versions are needed for the solicitation number; list results may omit them
+		if len(tracked.Versions) == 0 {
+			full, ok := m.state.GetOpportunity(tracked.NoticeID)
+			if !ok {
+				continue
+			}
+			tracked = full
+		}
+		latest, ok := tracked.LatestVersion()
+		if !ok || samgov.IsAwardNotice(latest.Snapshot.Type) {
+			continue
+		}
+		if number := samgov.NormalizeSolicitationNumber(latest.Snapshot.SolicitationNumber); number != "" {
+			index[number] = tracked.NoticeID
+		}
+	}
+	return index
+}
+
+// sendAwardNotification tells a query that tracked opportunities were awarded
+func (m *Monitor) sendAwardNotification(ctx context.Context, query config.Query, opportunities []samgov.Opportunity) error {
+	subject := fmt.Sprintf("🏆 %d Tracked SAM.gov Opportunities Awarded - %s", len(opportunities), query.Name)
+
+	notification := notify.NewNotificationBuilder().
+		WithQuery(query.Name, notificationPriority(query, opportunities)).
+		WithRecipients(query.Notification.Recipients).
+		WithChannels(query.Notification.Channels).
+		WithTemplate(query.Notification.Template).
+		WithAwards(opportunities).
+		WithSubject(subject).
+		WithMetadata("query_type", "awarded").
+		Build()
+
+	return m.notifyMgr.SendNotification(ctx, notification)
+}
+
+// awardClosures asks for the issues of newly awarded solicitations to be closed
+func awardClosures(records []samgov.AwardRecord) []notify.IssueClosure {
+	closures := make([]notify.IssueClosure, 0, len(records))
+	for _, record := range records {
+		detail := fmt.Sprintf("Awarded to %s", awardeeName(record))
+		if record.Amount != 0 {
+			detail += " for " + samgov.FormatDollars(record.Amount)
+		}
+		detail += fmt.Sprintf(" (award notice %s).", record.NoticeID)
+
+		closures = append(closures, notify.IssueClosure{
+			NoticeID: record.LinkedNoticeID,
+			Title:    record.Title,
+			Reason:   notify.ClosedAwarded,
+			Detail:   detail,
+		})
+	}
+	return closures
+}
+
+// awardeeName returns the awardee of a record, or a placeholder
+func awardeeName(record samgov.AwardRecord) string {
+	if record.Awardee == "" {
+		return unknownAwardee
+	}
+	return record.Awardee
+}
+
+// AwardReport summarizes who won the recorded awards
+type AwardReport struct {
+	Awards      int          `json:"awards"`
+	Linked      int          `json:"linked"` // Awards of tracked solicitations
+	TotalAmount float64      `json:"total_amount"`
+	ByAgency    []AwardGroup `json:"by_agency"`
+	ByNAICS     []AwardGroup `json:"by_naics"`
+}
+
+// AwardGroup is the awards of one agency or NAICS code
+type AwardGroup struct {
+	Key         string        `json:"key"`
+	Awards      int           `json:"awards"`
+	TotalAmount float64       `json:"total_amount"`
+	Winners     []AwardWinner `json:"winners"`
+}
+
+// AwardWinner is what one awardee won within a group
+type AwardWinner struct {
+	Awardee     string   `json:"awardee"`
+	UEI         string   `json:"uei,omitempty"`
+	Awards      int      `json:"awards"`
+	TotalAmount float64  `json:"total_amount"`
+	Titles      []string `json:"titles"`
+}
+
+// BuildAwardReport groups recorded awards by agency and by NAICS code, with
+// the winners of each group ordered by the total they were awarded
+func BuildAwardReport(records []samgov.AwardRecord) AwardReport {
+	report := AwardReport{Awards: len(records)}
+	for _, record := range records {
+		report.TotalAmount += record.Amount
+		if record.LinkedNoticeID != "" {
+			report.Linked++
+		}
+	}
+
+	report.ByAgency = groupAwards(records, func(record samgov.AwardRecord) string {
+		return awardAgency(record.Agency)
+	})
+	report.ByNAICS = groupAwards(records, func(record samgov.AwardRecord) string {
+		if record.NAICSCode == "" {
+			return unknownNAICS
+		}
+		return record.NAICSCode
+	})
+	return report
+}
+
+// groupAwards groups awards by key, largest totals first
+func groupAwards(records []samgov.AwardRecord, key func(samgov.AwardRecord) string) []AwardGroup {
+	groups := make(map[string]*AwardGroup)
+	winners := make(map[string]map[string]*AwardWinner)
+	for _, record := range records {
+		k := key(record)
+		group, ok := groups[k]
+		if !ok {
+			group = &AwardGroup{Key: k}
+			groups[k] = group
+			winners[k] = make(map[string]*AwardWinner)
+		}
+		group.Awards++
+		group.TotalAmount += record.Amount
+
+		// The UEI identifies an awardee better than its spelling
+		name := awardeeName(record)
+		id := strings.ToUpper(name)
+		if record.AwardeeUEI != "" {
+			id = record.AwardeeUEI
+		}
+		winner, ok := winners[k][id]
+		if !ok {
+			winner = &AwardWinner{Awardee: name, UEI: record.AwardeeUEI}
+			winners[k][id] = winner
+		}
+		winner.Awards++
+		winner.TotalAmount += record.Amount
+		winner.Titles = append(winner.Titles, record.Title)
+	}
+
+	result := make([]AwardGroup, 0, len(groups))
+	for k, group := range groups {
+		for _, winner := range winners[k] {
+			group.Winners = append(group.Winners, *winner)
+		}
+		sort.Slice(group.Winners, func(i, j int) bool {
+			a, b := group.Winners[i], group.Winners[j]
+			if a.TotalAmount != b.TotalAmount {
+				return a.TotalAmount > b.TotalAmount
+			}
+			return a.Awardee < b.Awardee
+		})
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalAmount != result[j].TotalAmount {
+			return result[i].TotalAmount > result[j].TotalAmount
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// awardAgency reduces an organization path such as
+// "DEPT OF DEFENSE.DEPT OF THE ARMY.AMC" to its department and sub-tier
+func awardAgency(path string) string {
+	if strings.TrimSpace(path) == "" {
+		return unknownAgency
+	}
+	parts := strings.Split(path, ".")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestRecordAwardsLinksSolicitationTrackedLater(t *testing.T) {
+	query := config.Query{Name: "Awards", Enabled: true, Advanced: config.AdvancedQuery{TrackAwards: true}}
+	award := samgov.Opportunity{
+		NoticeID:        "AW-1",
+		Title:           "Award: Cloud hosting",
+		Type:            "Award Notice",
+		SolicitationNum: "W912-24-R-0001",
+		Award: &samgov.Award{
+			Number:  "W912-24-C-0042",
+			Amount:  "125000",
+			Awardee: &samgov.Awardee{Name: "Acme Federal"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		seenAgain     bool // The award notice is found again once the solicitation is tracked
+		queryDisabled bool
+	}{
+		{name: "award found again", seenAgain: true},
+		{name: "award not found again"},
+		{name: "query no longer enabled", queryDisabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := LoadState(filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatalf("LoadState: %v", err)
+			}
+			cfg := &config.Config{Queries: []config.Query{query}}
+			m := &Monitor{config: cfg, state: state, dryRun: true}
+			run := func(awards ...awardMatch) (*RunReport, []samgov.AwardRecord) {
+				report := &RunReport{}
+				return report, m.recordAwards(context.Background(), awards, report)
+			}
+
+			// The award is posted before its solicitation is tracked
+			report, linked := run(awardMatch{Query: query, Opportunity: award})
+			if report.AwardsRecorded != 1 || report.AwardsLinked != 0 || len(linked) != 0 {
+				t.Fatalf("first run recorded %d and linked %d, want 1 and 0", report.AwardsRecorded, report.AwardsLinked)
+			}
+			report, _ = run(awardMatch{Query: query, Opportunity: award})
+			if report.AwardsRecorded != 0 || report.AwardsLinked != 0 {
+				t.Fatalf("second run recorded %d and linked %d, want nothing", report.AwardsRecorded, report.AwardsLinked)
+			}
+			recordedAt := state.ListAwards()[0].RecordedAt
+
+			solicitation := testOpportunity("SOL-1", "Cloud hosting")
+			solicitation.SolicitationNum = "W91224R0001"
+			state.AddOpportunity(solicitation)
+			if tt.queryDisabled {
+				cfg.Queries[0].Enabled = false
+			}
+
+			var awards []awardMatch
+			if tt.seenAgain {
+				awards = append(awards, awardMatch{Query: query, Opportunity: award})
+			}
+			report, linked = run(awards...)
+			if report.AwardsRecorded != 0 || report.AwardsLinked != 1 {
+				t.Errorf("recorded %d and linked %d, want 0 and 1", report.AwardsRecorded, report.AwardsLinked)
+			}
+			if len(linked) != 1 || linked[0].NoticeID != "AW-1" || linked[0].LinkedNoticeID != "SOL-1" {
+				t.Fatalf("linked = %+v, want AW-1 linked to SOL-1", linked)
+			}
+
+			stored := state.ListAwards()
+			if len(stored) != 1 || stored[0].LinkedNoticeID != "SOL-1" {
+				t.Fatalf("stored awards = %+v, want AW-1 linked to SOL-1", stored)
+			}
+			if !stored[0].RecordedAt.Equal(recordedAt) || stored[0].Awardee != "Acme Federal" || stored[0].Amount != 125000 {
+				t.Errorf("stored award = %+v, want the first recording kept", stored[0])
+			}
+
+			// Linked once; later runs leave it alone
+			report, linked = run(awards...)
+			if report.AwardsLinked != 0 || len(linked) != 0 {
+				t.Errorf("later run linked %d, want 0", report.AwardsLinked)
+			}
+		})
+	}
+}
+
+func TestAwardOpportunity(t *testing.T) {
+	record := samgov.NewAwardRecord(samgov.Opportunity{
+		NoticeID:        "AW-1",
+		Title:           "Award: Cloud hosting",
+		SolicitationNum: "W912-24-R-0001",
+		FullParentPath:  "DEPT OF DEFENSE",
+		Award: &samgov.Award{
+			Date:    "2024-03-01",
+			Number:  "W912-24-C-0042",
+			Amount:  "125000",
+			Awardee: &samgov.Awardee{Name: "Acme Federal", UEISAM: "ABC123"},
+		},
+	}, "Awards", time.Now())
+
+	opp := awardOpportunity(record)
+	if opp.NoticeID != "AW-1" || opp.SolicitationNum != "W912-24-R-0001" || opp.FullParentPath != "DEPT OF DEFENSE" {
+		t.Errorf("opportunity = %+v", opp)
+	}
+	if opp.Award == nil || opp.Award.Number != "W912-24-C-0042" || opp.Award.GetAmount() != 125000 {
+		t.Fatalf("award = %+v", opp.Award)
+	}
+	if opp.Award.Awardee == nil || opp.Award.Awardee.Name != "Acme Federal" || opp.Award.Awardee.UEISAM != "ABC123" {
+		t.Errorf("awardee = %+v", opp.Award.Awardee)
+	}
+}
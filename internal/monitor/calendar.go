@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// calendarName is the display name of the deadline feed in calendar clients
+const calendarName = "SAM.gov Monitor"
+
+// DeadlineCalendar builds an iCalendar feed with the response deadline of
+// every active tracked opportunity whose deadline has not passed
+func (m *Monitor) DeadlineCalendar() string {
+	now := time.Now()
+	opportunities := make([]samgov.Opportunity, 0)
+	for _, listed := range m.state.ListOpportunities() {
+		if listed.Deadline == nil {
+			continue
+		}
+		if deadline, ok := samgov.ParseDate(*listed.Deadline); !ok || deadline.Before(now) {
+			continue
+		}
+
+		// Versions are needed for the active flag; list results may omit them
+		tracked, ok := m.state.GetOpportunity(listed.NoticeID)
+		if !ok {
+			continue
+		}
+		if latest, ok := tracked.LatestVersion(); ok && strings.EqualFold(latest.Snapshot.Active, "no") {
+			continue
+		}
+		opportunities = append(opportunities, trackedOpportunity(tracked))
+	}
+
+	// A stable order keeps the file diffable between runs
+	sort.Slice(opportunities, func(i, j int) bool {
+		if *opportunities[i].ResponseDeadline != *opportunities[j].ResponseDeadline {
+			return *opportunities[i].ResponseDeadline < *opportunities[j].ResponseDeadline
+		}
+		return opportunities[i].NoticeID < opportunities[j].NoticeID
+	})
+
+	return notify.NewCalendarGenerator(false).GenerateDeadlineOnlyICS(opportunities, calendarName)
+}
+
+// writeCalendar writes the deadline feed to the configured file. Like the
+// state file, it is written to a temporary file and renamed into place.
+func (m *Monitor) writeCalendar() error {
+	if m.calendarFile == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.calendarFile), 0755); err != nil {
+		return fmt.Errorf("creating calendar directory: %w", err)
+	}
+
+	tempFile := m.calendarFile + ".tmp"
+	if err := os.WriteFile(tempFile, []byte(m.DeadlineCalendar()), 0644); err != nil {
+		return fmt.Errorf("writing temp calendar file: %w", err)
+	}
+	if err := os.Rename(tempFile, m.calendarFile); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("renaming calendar file: %w", err)
+	}
+	return nil
+}
+
+// CalendarHandler serves the deadline feed, built from the current state on
+// every request, for calendar clients to subscribe to
+func (m *Monitor) CalendarHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="deadlines.ics"`)
+		w.Header().Set("Cache-Control", "no-cache")
+		if r.Method == http.MethodHead {
+			return
+		}
+		fmt.Fprint(w, m.DeadlineCalendar())
+	})
+}
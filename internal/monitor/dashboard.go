@@ -0,0 +1,258 @@
+package monitor
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Dashboard windows and list sizes
+const (
+	dashboardDeadlineDays = 30
+	dashboardRecentDays   = 7
+	dashboardListSize     = 50
+)
+
+// dashboardTemplate renders the HTML dashboard
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Format("Jan 2, 2006")
+	},
+	"deadline": func(summary OpportunitySummary) string {
+		if summary.deadline.IsZero() {
+			if summary.Deadline != nil {
+				return *summary.Deadline
+			}
+			return "—"
+		}
+		return summary.deadline.Format("Jan 2, 2006")
+	},
+	"daysLeft": func(summary OpportunitySummary) int {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		return int(summary.deadline.UTC().Truncate(24*time.Hour).Sub(today).Hours() / 24)
+	},
+}).Parse(dashboardHTML))
+
+// DashboardData holds data for the dashboard template
+type DashboardData struct {
+	Stats        APIStats
+	Upcoming     []OpportunitySummary
+	Recent       []OpportunitySummary
+	DeadlineDays int
+	RecentDays   int
+}
+
+// handleDashboard serves the HTML dashboard at /
+func (s *APIServer) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := s.dashboardData(time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := dashboardTemplate.Execute(&buf, data); err != nil {
+		log.Printf("Warning: failed to render dashboard: %v", err)
+		http.Error(w, "failed to render dashboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(buf.Bytes())
+}
+
+// dashboardData lists active opportunities due soon and notices first seen
+// recently
+func (s *APIServer) dashboardData(now time.Time) (DashboardData, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	upcoming, err := s.Search(OpportunityFilter{
+		DeadlineAfter:  today,
+		DeadlineBefore: now.AddDate(0, 0, dashboardDeadlineDays),
+		ActiveOnly:     true,
+	}, "deadline")
+	if err != nil {
+		return DashboardData{}, err
+	}
+
+	recent, err := s.Search(OpportunityFilter{
+		SeenSince: now.AddDate(0, 0, -dashboardRecentDays),
+	}, "-first_seen")
+	if err != nil {
+		return DashboardData{}, err
+	}
+
+	if len(upcoming) > dashboardListSize {
+		upcoming = upcoming[:dashboardListSize]
+	}
+	if len(recent) > dashboardListSize {
+		recent = recent[:dashboardListSize]
+	}
+
+	return DashboardData{
+		Stats:        s.Stats(now),
+		Upcoming:     upcoming,
+		Recent:       recent,
+		DeadlineDays: dashboardDeadlineDays,
+		RecentDays:   dashboardRecentDays,
+	}, nil
+}
+
+// Dashboard template
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>SAM.gov Monitor</title>
+    <style>
+        body {
+            font-family: Arial, sans-serif;
+            line-height: 1.5;
+            color: #333;
+            max-width: 1100px;
+            margin: 0 auto;
+            padding: 20px;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 20px;
+            border-radius: 8px;
+        }
+        .header h1 {
+            margin: 0;
+            font-size: 24px;
+        }
+        .stats {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 15px;
+            margin: 20px 0;
+        }
+        .stat {
+            background: #f8f9fa;
+            border-left: 4px solid #667eea;
+            padding: 10px 15px;
+            min-width: 140px;
+        }
+        .stat strong {
+            display: block;
+            font-size: 22px;
+        }
+        form {
+            margin: 20px 0;
+        }
+        input[type=text] {
+            padding: 6px;
+            width: 300px;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-bottom: 30px;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #ddd;
+            vertical-align: top;
+        }
+        th {
+            background: #f8f9fa;
+        }
+        .urgent {
+            color: #dc3545;
+            font-weight: bold;
+        }
+        .notice-id {
+            font-family: monospace;
+            font-size: 0.85em;
+            color: #666;
+        }
+        .empty {
+            color: #666;
+            font-style: italic;
+        }
+        .footer {
+            color: #666;
+            font-size: 0.9em;
+        }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>SAM.gov Monitor</h1>
+        <div>Generated {{.Stats.GeneratedAt.Format "Jan 2, 2006 15:04 MST"}}</div>
+    </div>
+
+    <div class="stats">
+        <div class="stat"><strong>{{.Stats.TotalOpportunities}}</strong>tracked</div>
+        <div class="stat"><strong>{{.Stats.ActiveOpportunities}}</strong>active</div>
+        <div class="stat"><strong>{{.Stats.DueWithin7Days}}</strong>due within 7 days</div>
+        <div class="stat"><strong>{{.Stats.DueWithin30Days}}</strong>due within 30 days</div>
+        <div class="stat"><strong>{{.Stats.NewLast7Days}}</strong>new in 7 days</div>
+    </div>
+
+    <form action="/api/opportunities" method="get">
+        <input type="text" name="q" placeholder="Search title, notice ID or agency">
+        <button type="submit">Search API</button>
+    </form>
+
+    <h2>Upcoming Deadlines ({{.DeadlineDays}} days)</h2>
+    {{if .Upcoming}}
+    <table>
+        <tr><th>Deadline</th><th>Opportunity</th><th>Agency</th><th>NAICS</th><th>Stage</th><th>Queries</th></tr>
+        {{range .Upcoming}}
+        <tr>
+            <td>{{$days := daysLeft .}}<span{{if le $days 7}} class="urgent"{{end}}>{{deadline .}}</span><br>{{if eq $days 0}}today{{else}}{{$days}} days{{end}}</td>
+            <td><a href="{{.UILink}}">{{.Title}}</a><br><span class="notice-id">{{.NoticeID}}</span></td>
+            <td>{{.Agency}}</td>
+            <td>{{.NAICSCode}}</td>
+            <td>{{.Stage}}{{with .Owner}}<br>{{.}}{{end}}</td>
+            <td>{{range $i, $q := .Queries}}{{if $i}}, {{end}}{{$q}}{{end}}</td>
+        </tr>
+        {{end}}
+    </table>
+    {{else}}
+    <p class="empty">No active opportunities are due in the next {{.DeadlineDays}} days.</p>
+    {{end}}
+
+    <h2>Recent New Notices ({{.RecentDays}} days)</h2>
+    {{if .Recent}}
+    <table>
+        <tr><th>First Seen</th><th>Opportunity</th><th>Agency</th><th>Deadline</th><th>Queries</th></tr>
+        {{range .Recent}}
+        <tr>
+            <td>{{date .FirstSeen}}</td>
+            <td><a href="{{.UILink}}">{{.Title}}</a><br><span class="notice-id">{{.NoticeID}}</span></td>
+            <td>{{.Agency}}</td>
+            <td>{{deadline .}}</td>
+            <td>{{range $i, $q := .Queries}}{{if $i}}, {{end}}{{$q}}{{end}}</td>
+        </tr>
+        {{end}}
+    </table>
+    {{else}}
+    <p class="empty">No new notices in the last {{.RecentDays}} days.</p>
+    {{end}}
+
+    <p class="footer">
+        JSON API: <a href="/api/opportunities">/api/opportunities</a> ·
+        <a href="/api/stats">/api/stats</a>
+    </p>
+</body>
+</html>
+`
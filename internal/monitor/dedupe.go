@@ -0,0 +1,210 @@
+package monitor
+
+import (
+	"strings"
+
+	"github.com/yourusername/sam-gov-monitor/internal/attachments"
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+	"github.com/yourusername/sam-gov-monitor/internal/scoring"
+)
+
+// queryMatch is one query's new and updated opportunities from a run
+type queryMatch struct {
+	Query       config.Query
+	Diff        samgov.DiffResult
+	FilteredOut []samgov.Opportunity
+}
+
+// matchGroup is a set of notices matched by exactly the same queries. Each
+// group is sent as one notification, so a notice matched by several queries
+// is reported once, listing all of them.
+type matchGroup struct {
+	Query       config.Query // Combined query used to address the notification
+	Queries     []string     // Names of the matching queries, in config order
+	Diff        samgov.DiffResult
+	FilteredOut []samgov.Opportunity
+	MatchedBy   map[string][]string // Notice ID → matching query names
+}
+
+// mergeQueryMatches groups the run's new and updated notices by the queries
+// that matched them. Each notice keeps the version seen by the first
+// matching query. defaultChannels stands in for a query with no channels
+// when its channels are combined with another query's.
+func mergeQueryMatches(matches []queryMatch, defaultChannels []string) []matchGroup {
+	matchedBy := make(map[string][]string)
+	firstSeen := make(map[string]samgov.Opportunity)
+	updated := make(map[string]bool)
+	order := make([]string, 0)
+	queries := make(map[string]config.Query)
+
+	for _, match := range matches {
+		queries[match.Query.Name] = match.Query
+		record := func(opp samgov.Opportunity, isUpdate bool) {
+			if _, seen := firstSeen[opp.NoticeID]; !seen {
+				firstSeen[opp.NoticeID] = opp
+				updated[opp.NoticeID] = isUpdate
+				order = append(order, opp.NoticeID)
+			}
+			matchedBy[opp.NoticeID] = appendUnique(matchedBy[opp.NoticeID], match.Query.Name)
+		}
+		for _, opp := range match.Diff.New {
+			record(opp, false)
+		}
+		for _, opp := range match.Diff.Updated {
+			record(opp, true)
+		}
+	}
+
+	groups := make([]matchGroup, 0)
+	byKey := make(map[string]int)
+	for _, noticeID := range order {
+		names := matchedBy[noticeID]
+		key := strings.Join(names, "\x00")
+		index, ok := byKey[key]
+		if !ok {
+			members := make([]config.Query, 0, len(names))
+			for _, name := range names {
+				members = append(members, queries[name])
+			}
+			groups = append(groups, matchGroup{
+				Query:     combineQueries(members, defaultChannels),
+				Queries:   names,
+				Diff:      samgov.DiffResult{New: make([]samgov.Opportunity, 0), Updated: make([]samgov.Opportunity, 0)},
+				MatchedBy: make(map[string][]string),
+			})
+			index = len(groups) - 1
+			byKey[key] = index
+		}
+
+		group := &groups[index]
+		group.MatchedBy[noticeID] = names
+		if updated[noticeID] {
+			group.Diff.Updated = append(group.Diff.Updated, firstSeen[noticeID])
+		} else {
+			group.Diff.New = append(group.Diff.New, firstSeen[noticeID])
+		}
+	}
+
+	for i := range groups {
+		scoring.SortByScore(groups[i].Diff.New)
+		scoring.SortByScore(groups[i].Diff.Updated)
+	}
+
+	attachFilteredOut(groups, matches, matchedBy)
+	return groups
+}
+
+// attachFilteredOut gives each query's filtered-out list to the first group
+// with new opportunities that the query belongs to, leaving out notices
+// another query accepted
+func attachFilteredOut(groups []matchGroup, matches []queryMatch, matchedBy map[string][]string) {
+	for _, match := range matches {
+		if len(match.FilteredOut) == 0 {
+			continue
+		}
+		for i := range groups {
+			if len(groups[i].Diff.New) == 0 || !containsString(groups[i].Queries, match.Query.Name) {
+				continue
+			}
+			for _, opp := range match.FilteredOut {
+				if _, accepted := matchedBy[opp.NoticeID]; !accepted {
+					groups[i].FilteredOut = append(groups[i].FilteredOut, opp)
+				}
+			}
+			break
+		}
+	}
+}
+
+// combineQueries builds the query a shared notification is addressed with:
+// names joined, the most urgent priority, and every recipient, channel and
+// reminder. The notice is sent at once if any query sends at once, and uses
+// a template only when every query names the same one.
+func combineQueries(members []config.Query, defaultChannels []string) config.Query {
+	if len(members) == 1 {
+		return members[0]
+	}
+
+	combined := members[0]
+	names := make([]string, 0, len(members))
+	combined.Notification.Recipients = nil
+	combined.Notification.Channels = nil
+	combined.Notification.Reminders = nil
+	combined.Notification.Digest = true
+
+	rank := map[string]int{"low": 1, "medium": 2, "high": 3}
+	anyDefault := false
+	maxDownloads := 0
+	for _, member := range members {
+		names = append(names, member.Name)
+		if rank[member.Notification.Priority] > rank[combined.Notification.Priority] {
+			combined.Notification.Priority = member.Notification.Priority
+		}
+		for _, recipient := range member.Notification.Recipients {
+			combined.Notification.Recipients = appendUnique(combined.Notification.Recipients, recipient)
+		}
+		if len(member.Notification.Channels) == 0 {
+			anyDefault = true
+		}
+		for _, channel := range member.Notification.Channels {
+			combined.Notification.Channels = appendUnique(combined.Notification.Channels, channel)
+		}
+		for _, reminder := range member.Notification.Reminders {
+			combined.Notification.Reminders = appendUnique(combined.Notification.Reminders, reminder)
+		}
+
+		// Holding a notice for the digest would delay it for a query that
+		// reports immediately
+		if !member.Notification.Digest {
+			combined.Notification.Digest = false
+		}
+		if member.Notification.Template != combined.Notification.Template {
+			combined.Notification.Template = ""
+		}
+
+		combined.Advanced.AttachDocuments = combined.Advanced.AttachDocuments || member.Advanced.AttachDocuments
+		if member.Advanced.DownloadAttachments {
+			combined.Advanced.DownloadAttachments = true
+			if limit := downloadLimit(member.Advanced); limit > maxDownloads {
+				maxDownloads = limit
+			}
+		}
+	}
+	combined.Advanced.MaxAttachments = maxDownloads
+	combined.Name = strings.Join(names, " + ")
+
+	// A query without channels goes to every default channel; keep that when
+	// another query names specific ones
+	if anyDefault && len(combined.Notification.Channels) > 0 {
+		for _, channel := range defaultChannels {
+			combined.Notification.Channels = appendUnique(combined.Notification.Channels, channel)
+		}
+	}
+
+	return combined
+}
+
+// downloadLimit is the number of attachment downloads a query allows per run
+func downloadLimit(advanced config.AdvancedQuery) int {
+	if advanced.MaxAttachments == 0 {
+		return attachments.DefaultMaxDownloads
+	}
+	return advanced.MaxAttachments
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestMergeQueryMatches(t *testing.T) {
+	cloud := config.Query{Name: "Cloud"}
+	cyber := config.Query{Name: "Cyber"}
+	opp := func(id, title string) samgov.Opportunity {
+		return samgov.Opportunity{NoticeID: id, Title: title}
+	}
+
+	matches := []queryMatch{
+		{Query: cloud, Diff: samgov.DiffResult{
+			New:     []samgov.Opportunity{opp("A", "cloud's copy"), opp("B", "cloud only")},
+			Updated: []samgov.Opportunity{opp("C", "amended")},
+		}},
+		{Query: cyber, Diff: samgov.DiffResult{
+			New:     []samgov.Opportunity{opp("A", "cyber's copy"), opp("D", "cyber only")},
+			Updated: []samgov.Opportunity{opp("C", "amended")},
+		}},
+	}
+	groups := mergeQueryMatches(matches, nil)
+
+	want := []struct {
+		queries []string
+		name    string
+		new     []string
+		updated []string
+	}{
+		{[]string{"Cloud", "Cyber"}, "Cloud + Cyber", []string{"A"}, []string{"C"}},
+		{[]string{"Cloud"}, "Cloud", []string{"B"}, nil},
+		{[]string{"Cyber"}, "Cyber", []string{"D"}, nil},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(groups), len(want), groups)
+	}
+	for i, w := range want {
+		group := groups[i]
+		if !equalStrings(group.Queries, w.queries) || group.Query.Name != w.name {
+			t.Errorf("group %d: queries %v named %q, want %v named %q", i, group.Queries, group.Query.Name, w.queries, w.name)
+		}
+		if got := noticeIDs(group.Diff.New); !equalStrings(got, w.new) {
+			t.Errorf("group %d: new %v, want %v", i, got, w.new)
+		}
+		if got := noticeIDs(group.Diff.Updated); !equalStrings(got, w.updated) {
+			t.Errorf("group %d: updated %v, want %v", i, got, w.updated)
+		}
+		for _, id := range append(noticeIDs(group.Diff.New), noticeIDs(group.Diff.Updated)...) {
+			if !equalStrings(group.MatchedBy[id], w.queries) {
+				t.Errorf("group %d: %s matched by %v, want %v", i, id, group.MatchedBy[id], w.queries)
+			}
+		}
+	}
+
+	// A notice matched by several queries keeps the first query's version
+	if title := groups[0].Diff.New[0].Title; title != "cloud's copy" {
+		t.Errorf("shared notice title = %q, want the first query's version", title)
+	}
+}
+
+func TestAttachFilteredOut(t *testing.T) {
+	matches := []queryMatch{
+		{
+			Query: config.Query{Name: "Cloud"},
+			Diff: samgov.DiffResult{
+				New:     []samgov.Opportunity{{NoticeID: "A"}},
+				Updated: []samgov.Opportunity{{NoticeID: "U"}},
+			},
+			FilteredOut: []samgov.Opportunity{{NoticeID: "X"}, {NoticeID: "B"}},
+		},
+		{
+			Query:       config.Query{Name: "Cyber"},
+			Diff:        samgov.DiffResult{New: []samgov.Opportunity{{NoticeID: "A"}, {NoticeID: "B"}}},
+			FilteredOut: []samgov.Opportunity{{NoticeID: "Y"}},
+		},
+		{
+			Query:       config.Query{Name: "Updates"},
+			Diff:        samgov.DiffResult{Updated: []samgov.Opportunity{{NoticeID: "V"}}},
+			FilteredOut: []samgov.Opportunity{{NoticeID: "Z"}},
+		},
+	}
+	groups := mergeQueryMatches(matches, nil)
+
+	// Groups: [Cloud Cyber] {A}, [Cloud] {U}, [Cyber] {B}, [Updates] {V}
+	want := map[string][]string{
+		"Cloud + Cyber": {"X", "Y"}, // B was accepted by Cyber
+		"Cloud":         nil,        // Already attached to the first group
+		"Cyber":         nil,
+		"Updates":       nil, // No group with new notices
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for _, group := range groups {
+		wantIDs, ok := want[group.Query.Name]
+		if !ok {
+			t.Errorf("unexpected group %q", group.Query.Name)
+			continue
+		}
+		if got := noticeIDs(group.FilteredOut); !equalStrings(got, wantIDs) {
+			t.Errorf("%s: filtered out %v, want %v", group.Query.Name, got, wantIDs)
+		}
+	}
+}
+
+func TestCombineQueries(t *testing.T) {
+	defaults := []string{"email", "slack"}
+	query := func(name string, notification config.NotificationConfig) config.Query {
+		return config.Query{Name: name, Notification: notification}
+	}
+
+	tests := []struct {
+		name         string
+		members      []config.Query
+		wantPriority string
+		wantChannels []string
+		wantDigest   bool
+		wantTemplate string
+	}{
+		{
+			name:         "single query is unchanged",
+			members:      []config.Query{query("A", config.NotificationConfig{Priority: "low", Digest: true, Template: "brief"})},
+			wantPriority: "low",
+			wantDigest:   true,
+			wantTemplate: "brief",
+		},
+		{
+			name: "most urgent priority and every channel",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "low", Channels: []string{"email"}}),
+				query("B", config.NotificationConfig{Priority: "high", Channels: []string{"teams", "email"}}),
+			},
+			wantPriority: "high",
+			wantChannels: []string{"email", "teams"},
+		},
+		{
+			name: "query without channels adds the defaults",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "medium", Channels: []string{"teams"}}),
+				query("B", config.NotificationConfig{Priority: "medium"}),
+			},
+			wantPriority: "medium",
+			wantChannels: []string{"teams", "email", "slack"},
+		},
+		{
+			name: "no channels anywhere keeps the defaults implicit",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "medium"}),
+				query("B", config.NotificationConfig{Priority: "low"}),
+			},
+			wantPriority: "medium",
+		},
+		{
+			name: "immediate wins over digest",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "low", Digest: true}),
+				query("B", config.NotificationConfig{Priority: "low"}),
+			},
+			wantPriority: "low",
+		},
+		{
+			name: "digest when every query uses it",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "low", Digest: true}),
+				query("B", config.NotificationConfig{Priority: "low", Digest: true}),
+			},
+			wantPriority: "low",
+			wantDigest:   true,
+		},
+		{
+			name: "shared template is kept",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "low", Template: "brief"}),
+				query("B", config.NotificationConfig{Priority: "low", Template: "brief"}),
+			},
+			wantPriority: "low",
+			wantTemplate: "brief",
+		},
+		{
+			name: "different templates use the built-in one",
+			members: []config.Query{
+				query("A", config.NotificationConfig{Priority: "low", Template: "brief"}),
+				query("B", config.NotificationConfig{Priority: "low"}),
+				query("C", config.NotificationConfig{Priority: "low", Template: "brief"}),
+			},
+			wantPriority: "low",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combined := combineQueries(tt.members, defaults)
+			if combined.Notification.Priority != tt.wantPriority {
+				t.Errorf("priority = %q, want %q", combined.Notification.Priority, tt.wantPriority)
+			}
+			if !equalStrings(combined.Notification.Channels, tt.wantChannels) {
+				t.Errorf("channels = %v, want %v", combined.Notification.Channels, tt.wantChannels)
+			}
+			if combined.Notification.Digest != tt.wantDigest {
+				t.Errorf("digest = %v, want %v", combined.Notification.Digest, tt.wantDigest)
+			}
+			if combined.Notification.Template != tt.wantTemplate {
+				t.Errorf("template = %q, want %q", combined.Notification.Template, tt.wantTemplate)
+			}
+		})
+	}
+}
+
+func TestCombineQueriesRecipientsAndAttachments(t *testing.T) {
+	combined := combineQueries([]config.Query{
+		{
+			Name:         "A",
+			Notification: config.NotificationConfig{Recipients: []string{"a@example.com"}, Reminders: []string{"7d"}},
+			Advanced:     config.AdvancedQuery{DownloadAttachments: true, MaxAttachments: 3},
+		},
+		{
+			Name:         "B",
+			Notification: config.NotificationConfig{Recipients: []string{"b@example.com", "a@example.com"}, Reminders: []string{"24h", "7d"}},
+			Advanced:     config.AdvancedQuery{AttachDocuments: true},
+		},
+	}, nil)
+
+	if want := []string{"a@example.com", "b@example.com"}; !equalStrings(combined.Notification.Recipients, want) {
+		t.Errorf("recipients = %v, want %v", combined.Notification.Recipients, want)
+	}
+	if want := []string{"7d", "24h"}; !equalStrings(combined.Notification.Reminders, want) {
+		t.Errorf("reminders = %v, want %v", combined.Notification.Reminders, want)
+	}
+	if !combined.Advanced.DownloadAttachments || !combined.Advanced.AttachDocuments || combined.Advanced.MaxAttachments != 3 {
+		t.Errorf("advanced = %+v, want downloads of up to 3 attached", combined.Advanced)
+	}
+}
+
+// noticeIDs lists the opportunities' notice IDs in order
+func noticeIDs(opportunities []samgov.Opportunity) []string {
+	var ids []string
+	for _, opp := range opportunities {
+		ids = append(ids, opp.NoticeID)
+	}
+	return ids
+}
@@ -1,12 +1,12 @@
 package monitor
 
 import (
-	"crypto/sha256"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
 	"github.com/yourusername/sam-gov-monitor/internal/samgov"
 )
 
@@ -23,7 +23,7 @@ func NewOpportunityDiffer(verbose bool) *OpportunityDiffer {
 }
 
 // DiffOpportunities compares current opportunities against stored state
-func (d *OpportunityDiffer) DiffOpportunities(current []samgov.Opportunity, state *State) samgov.DiffResult {
+func (d *OpportunityDiffer) DiffOpportunities(current []samgov.Opportunity, state StateStore) samgov.DiffResult {
 	result := samgov.DiffResult{
 		New:      make([]samgov.Opportunity, 0),
 		Updated:  make([]samgov.Opportunity, 0),
@@ -43,14 +43,15 @@ func (d *OpportunityDiffer) DiffOpportunities(current []samgov.Opportunity, stat
 			if d.verbose {
 				log.Printf("NEW: %s - %s", opp.NoticeID, opp.Title)
 			}
-			
+
 		case "updated":
+			opp.Changes = classification.Changes
 			result.Updated = append(result.Updated, opp)
 			if d.verbose {
-				log.Printf("UPDATED: %s - %s (Changes: %s)", 
-					opp.NoticeID, opp.Title, strings.Join(classification.Changes, ", "))
+				log.Printf("UPDATED: %s - %s (Changes: %s)",
+					opp.NoticeID, opp.Title, formatChanges(classification.Changes))
 			}
-			
+
 		case "existing":
 			result.Existing = append(result.Existing, opp)
 			if d.verbose && len(result.Existing) <= 3 { // Limit verbose output
@@ -69,13 +70,13 @@ func (d *OpportunityDiffer) DiffOpportunities(current []samgov.Opportunity, stat
 
 // OpportunityClassification describes how an opportunity has changed
 type OpportunityClassification struct {
-	Type     string   // "new", "updated", "existing"
-	Changes  []string // List of fields that changed
+	Type     string               // "new", "updated", "existing"
+	Changes  []samgov.FieldChange // Fields that changed, with old and new values
 	Previous *samgov.OpportunityState
 }
 
 // classifyOpportunity determines if an opportunity is new, updated, or existing
-func (d *OpportunityDiffer) classifyOpportunity(current samgov.Opportunity, state *State) OpportunityClassification {
+func (d *OpportunityDiffer) classifyOpportunity(current samgov.Opportunity, state StateStore) OpportunityClassification {
 	previous, exists := state.GetOpportunity(current.NoticeID)
 	if !exists {
 		return OpportunityClassification{
@@ -98,74 +99,22 @@ func (d *OpportunityDiffer) classifyOpportunity(current samgov.Opportunity, stat
 	}
 }
 
-// detectChanges identifies what fields have changed in an opportunity
-func (d *OpportunityDiffer) detectChanges(previous samgov.OpportunityState, current samgov.Opportunity) []string {
-	changes := make([]string, 0)
-
-	// Check title change
-	if previous.Title != current.Title {
-		changes = append(changes, "title")
-	}
-
-	// Check deadline change
-	prevDeadline := ""
-	if previous.Deadline != nil {
-		prevDeadline = *previous.Deadline
-	}
-	
-	currentDeadline := ""
-	if current.ResponseDeadline != nil {
-		currentDeadline = *current.ResponseDeadline
-	}
-	
-	if prevDeadline != currentDeadline {
-		changes = append(changes, "deadline")
-	}
-
-	// Check content hash change (detects description or other field changes)
-	currentHash := d.calculateContentHash(current)
-	if previous.Hash != currentHash {
-		// Only add if we haven't already detected specific changes
-		if len(changes) == 0 {
-			changes = append(changes, "content")
-		}
-	}
-
-	return changes
+// detectChanges compares an opportunity against its latest stored version
+func (d *OpportunityDiffer) detectChanges(previous samgov.OpportunityState, current samgov.Opportunity) []samgov.FieldChange {
+	return opportunityChanges(previous, current)
 }
 
-// calculateContentHash creates a hash of the opportunity's content for change detection
-func (d *OpportunityDiffer) calculateContentHash(opp samgov.Opportunity) string {
-	// Include key fields that we care about for change detection
-	content := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s",
-		opp.NoticeID,
-		opp.Title,
-		opp.PostedDate,
-		opp.Type,
-		opp.TypeOfSetAside,
-		opp.NAICSCode,
-		func() string {
-			if opp.ResponseDeadline != nil {
-				return *opp.ResponseDeadline
-			}
-			return ""
-		}(),
-		// Truncate description for hash to avoid noise from minor formatting changes
-		func() string {
-			desc := strings.TrimSpace(opp.Description)
-			if len(desc) > 500 {
-				desc = desc[:500]
-			}
-			return desc
-		}(),
-	)
-
-	hash := sha256.Sum256([]byte(content))
-	return fmt.Sprintf("%x", hash)
+// formatChanges renders field changes for log output
+func formatChanges(changes []samgov.FieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		parts = append(parts, change.String())
+	}
+	return strings.Join(parts, "; ")
 }
 
 // FilterSignificantChanges filters out minor changes that don't warrant notifications
-func (d *OpportunityDiffer) FilterSignificantChanges(updated []samgov.Opportunity, state *State) []samgov.Opportunity {
+func (d *OpportunityDiffer) FilterSignificantChanges(updated []samgov.Opportunity, state StateStore) []samgov.Opportunity {
 	significant := make([]samgov.Opportunity, 0)
 
 	for _, opp := range updated {
@@ -180,24 +129,18 @@ func (d *OpportunityDiffer) FilterSignificantChanges(updated []samgov.Opportunit
 }
 
 // isSignificantChange determines if a change is significant enough to notify about
-func (d *OpportunityDiffer) isSignificantChange(current samgov.Opportunity, state *State) bool {
+func (d *OpportunityDiffer) isSignificantChange(current samgov.Opportunity, state StateStore) bool {
 	previous, exists := state.GetOpportunity(current.NoticeID)
 	if !exists {
 		return true // New opportunities are always significant
 	}
 
 	changes := d.detectChanges(previous, current)
-	
-	// Title changes are always significant
-	for _, change := range changes {
-		if change == "title" {
-			return true
-		}
-	}
 
-	// Deadline changes are significant
+	// Changes to the title, deadline, set-aside or documents are always significant
 	for _, change := range changes {
-		if change == "deadline" {
+		switch change.Field {
+		case "Title", "Response Deadline", "Set-Aside", "Documents":
 			return true
 		}
 	}
@@ -214,27 +157,64 @@ func (d *OpportunityDiffer) isSignificantChange(current samgov.Opportunity, stat
 	return false
 }
 
-// DetectExpiredOpportunities finds opportunities that are no longer active
-func (d *OpportunityDiffer) DetectExpiredOpportunities(current []samgov.Opportunity, state *State, maxAge time.Duration) []samgov.OpportunityState {
-	expired := make([]samgov.OpportunityState, 0)
-	
-	// Create a map of current opportunity IDs for quick lookup
-	currentIDs := make(map[string]bool)
+// ExpiredOpportunity is a tracked opportunity that is no longer open for
+// responses
+type ExpiredOpportunity struct {
+	samgov.OpportunityState
+	Reason string // notify.ClosedExpired or notify.ClosedAwarded
+	Detail string // Why the opportunity is considered closed
+}
+
+// DetectExpiredOpportunities finds tracked opportunities that are no longer
+// open: award notices, notices marked inactive and notices whose response
+// deadline has passed. Notices without a deadline expire once they have not
+// been seen for maxAge; the others drop out of searches after the lookback
+// window while still open, so not being seen says nothing about them.
+func (d *OpportunityDiffer) DetectExpiredOpportunities(current []samgov.Opportunity, state StateStore, maxAge time.Duration) []ExpiredOpportunity {
+	expired := make([]ExpiredOpportunity, 0)
+
+	// Create a map of current opportunities for quick lookup
+	currentByID := make(map[string]samgov.Opportunity)
 	for _, opp := range current {
-		currentIDs[opp.NoticeID] = true
+		currentByID[opp.NoticeID] = opp
 	}
 
 	// Check stored opportunities
-	cutoff := time.Now().Add(-maxAge)
-	for noticeID, stored := range state.Opportunities {
-		// Skip if we saw this opportunity in current results
-		if currentIDs[noticeID] {
-			continue
+	now := time.Now()
+	today := now.UTC().Truncate(24 * time.Hour)
+	cutoff := now.Add(-maxAge)
+	for _, stored := range state.ListOpportunities() {
+		// The latest sighting decides the type and active flag
+		var latest samgov.OpportunitySnapshot
+		opp, seen := currentByID[stored.NoticeID]
+		if seen {
+			latest = samgov.Snapshot(opp)
+		} else {
+			// Versions are needed for the snapshot; list results may omit them
+			if tracked, ok := state.GetOpportunity(stored.NoticeID); ok {
+				stored = tracked
+			}
+			if version, ok := stored.LatestVersion(); ok {
+				latest = version.Snapshot
+			}
+		}
+
+		deadline, hasDeadline := time.Time{}, false
+		if stored.Deadline != nil {
+			deadline, hasDeadline = samgov.ParseDate(*stored.Deadline)
 		}
 
-		// Consider expired if not seen recently and past cutoff
-		if stored.LastSeen.Before(cutoff) {
-			expired = append(expired, stored)
+		switch {
+		case samgov.IsAwardNotice(latest.Type):
+			expired = append(expired, ExpiredOpportunity{stored, notify.ClosedAwarded, "An award notice was posted on SAM.gov."})
+		case strings.EqualFold(latest.Active, "no"):
+			expired = append(expired, ExpiredOpportunity{stored, notify.ClosedExpired, "The notice is marked inactive on SAM.gov."})
+		case hasDeadline && deadline.Before(today):
+			expired = append(expired, ExpiredOpportunity{stored, notify.ClosedExpired,
+				fmt.Sprintf("The response deadline (%s) has passed.", *stored.Deadline)})
+		case !seen && !hasDeadline && stored.LastSeen.Before(cutoff):
+			expired = append(expired, ExpiredOpportunity{stored, notify.ClosedExpired,
+				fmt.Sprintf("The notice has not been seen in searches since %s.", stored.LastSeen.Format("Jan 2, 2006"))})
 		}
 	}
 
@@ -246,14 +226,14 @@ func (d *OpportunityDiffer) DetectExpiredOpportunities(current []samgov.Opportun
 }
 
 // AnalyzeOpportunityTrends provides insights about opportunity patterns
-func (d *OpportunityDiffer) AnalyzeOpportunityTrends(current []samgov.Opportunity, state *State) TrendAnalysis {
+func (d *OpportunityDiffer) AnalyzeOpportunityTrends(current []samgov.Opportunity, state StateStore) TrendAnalysis {
 	analysis := TrendAnalysis{
 		TotalOpportunities: len(current),
-		ByType:            make(map[string]int),
-		ByOrganization:    make(map[string]int),
-		BySetAside:        make(map[string]int),
-		NewToday:          0,
-		NewThisWeek:       0,
+		ByType:             make(map[string]int),
+		ByOrganization:     make(map[string]int),
+		BySetAside:         make(map[string]int),
+		NewToday:           0,
+		NewThisWeek:        0,
 	}
 
 	today := time.Now().Truncate(24 * time.Hour)
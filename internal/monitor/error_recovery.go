@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -16,32 +17,42 @@ import (
 type ErrorType string
 
 const (
-	ErrorTypeNetwork       ErrorType = "network"
-	ErrorTypeAPI          ErrorType = "api"
-	ErrorTypeRateLimit    ErrorType = "rate_limit"
+	ErrorTypeNetwork        ErrorType = "network"
+	ErrorTypeAPI            ErrorType = "api"
+	ErrorTypeRateLimit      ErrorType = "rate_limit"
 	ErrorTypeAuthentication ErrorType = "auth"
-	ErrorTypeValidation   ErrorType = "validation"
-	ErrorTypeTimeout      ErrorType = "timeout"
-	ErrorTypeUnknown      ErrorType = "unknown"
+	ErrorTypeValidation     ErrorType = "validation"
+	ErrorTypeTimeout        ErrorType = "timeout"
+	ErrorTypeBudget         ErrorType = "budget"
+	ErrorTypeCircuitOpen    ErrorType = "circuit_open"
+	ErrorTypeUnknown        ErrorType = "unknown"
 )
 
 // QueryResult represents the result of executing a single query
 type QueryResult struct {
-	Query         config.Query
-	Opportunities []samgov.Opportunity
-	Success       bool
-	Error         error
-	ErrorType     ErrorType
-	Duration      time.Duration
-	RetryCount    int
+	Query           config.Query
+	Opportunities   []samgov.Opportunity
+	Success         bool
+	Error           error
+	ErrorType       ErrorType
+	Duration        time.Duration
+	RetryCount      int
+	TotalRecords    int
+	Pages           int
+	Truncated       bool
+	TruncatedReason string
+	// Fallback marks results of a broader search than the query's own,
+	// made to recover from a failure. They are reported, not notified.
+	Fallback bool
 }
 
 // PartialFailureHandler manages recovery from partial query failures
 type PartialFailureHandler struct {
-	verbose         bool
-	maxRetries      int
-	retryDelay      time.Duration
-	failureThreshold float64 // Percentage of queries that can fail before stopping
+	verbose          bool
+	maxRetries       int
+	failureThreshold float64     // Percentage of queries that can fail before stopping
+	retryAllowed     func() bool // Reports whether the daily budget has room for a recovery search
+	limiter          *samgov.RateLimiter
 }
 
 // NewPartialFailureHandler creates a new error recovery handler
@@ -49,23 +60,34 @@ func NewPartialFailureHandler(verbose bool) *PartialFailureHandler {
 	return &PartialFailureHandler{
 		verbose:         verbose,
 		maxRetries:      2,
-		retryDelay:      30 * time.Second,
 		failureThreshold: 0.5, // 50% of queries can fail
 	}
 }
 
+// SetRetryBudget makes recovery searches ask allowed first, so they never
+// spend the requests reserved for other queries
+func (pfh *PartialFailureHandler) SetRetryBudget(allowed func() bool) {
+	pfh.retryAllowed = allowed
+}
+
+// SetRateLimiter gives each query one of the requests the limiter reserved
+// for the run, claimed by its first request however many pages it has
+func (pfh *PartialFailureHandler) SetRateLimiter(limiter *samgov.RateLimiter) {
+	pfh.limiter = limiter
+}
+
 // ExecuteQueriesWithRecovery executes queries with partial failure recovery
 func (pfh *PartialFailureHandler) ExecuteQueriesWithRecovery(
 	ctx context.Context,
 	queries []config.Query,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 ) ([]QueryResult, error) {
 	results := make([]QueryResult, len(queries))
-	
+
 	// First pass: execute all queries
-	pfh.executeQueriesParallel(ctx, queries, client, queryBuilder, results)
-	
+	pfh.executeQueriesParallel(ctx, queries, searcher, queryBuilder, results)
+
 	// Analyze results and determine recovery strategy
 	failedQueries := pfh.analyzeFailures(results)
 	if len(failedQueries) == 0 {
@@ -88,7 +110,7 @@ func (pfh *PartialFailureHandler) ExecuteQueriesWithRecovery(
 	}
 
 	// Retry failed queries with recovery strategies
-	pfh.retryFailedQueries(ctx, failedQueries, client, queryBuilder, results)
+	pfh.retryFailedQueries(ctx, failedQueries, searcher, queryBuilder, results)
 
 	// Final analysis
 	finalFailures := pfh.countFailures(results)
@@ -104,7 +126,7 @@ func (pfh *PartialFailureHandler) ExecuteQueriesWithRecovery(
 func (pfh *PartialFailureHandler) executeQueriesParallel(
 	ctx context.Context,
 	queries []config.Query,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 	results []QueryResult,
 ) {
@@ -140,7 +162,13 @@ func (pfh *PartialFailureHandler) executeQueriesParallel(
 				return
 			}
 
-			results[index] = pfh.executeQuery(ctx, q, client, queryBuilder)
+			queryCtx, release := ctx, func() {}
+			if pfh.limiter != nil {
+				queryCtx, release = pfh.limiter.WithReservation(ctx)
+			}
+			defer release()
+
+			results[index] = pfh.executeQuery(queryCtx, q, searcher, queryBuilder)
 		}(i, query)
 	}
 
@@ -151,11 +179,11 @@ func (pfh *PartialFailureHandler) executeQueriesParallel(
 func (pfh *PartialFailureHandler) executeQuery(
 	ctx context.Context,
 	query config.Query,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 ) QueryResult {
 	start := time.Now()
-	
+
 	result := QueryResult{
 		Query: query,
 	}
@@ -169,8 +197,8 @@ func (pfh *PartialFailureHandler) executeQuery(
 		return result
 	}
 
-	// Execute search with retry
-	response, err := client.SearchWithRetry(ctx, params)
+	// Execute search across every page; retries happen inside the searcher
+	response, err := samgov.Paginate(ctx, searcher, params)
 	result.Duration = time.Since(start)
 
 	if err != nil {
@@ -184,6 +212,10 @@ func (pfh *PartialFailureHandler) executeQuery(
 
 	result.Success = true
 	result.Opportunities = response.OpportunitiesData
+	result.TotalRecords = response.TotalRecords
+	result.Pages = response.Pages
+	result.Truncated = response.Truncated
+	result.TruncatedReason = response.TruncatedReason
 
 	if pfh.verbose {
 		log.Printf("Query '%s' succeeded: %d opportunities found in %v",
@@ -219,7 +251,7 @@ func (pfh *PartialFailureHandler) analyzeFailures(results []QueryResult) []int {
 func (pfh *PartialFailureHandler) retryFailedQueries(
 	ctx context.Context,
 	failedIndices []int,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 	results []QueryResult,
 ) {
@@ -240,154 +272,134 @@ func (pfh *PartialFailureHandler) retryFailedQueries(
 
 	// Apply recovery strategies based on error type
 	for errorType, indices := range errorGroups {
-		pfh.applyRecoveryStrategy(ctx, errorType, indices, client, queryBuilder, results)
+		pfh.applyRecoveryStrategy(ctx, errorType, indices, searcher, queryBuilder, results)
 	}
 }
 
-// applyRecoveryStrategy applies specific recovery based on error type
+// applyRecoveryStrategy applies specific recovery based on error type. Rate
+// limits, network errors and timeouts were already retried with backoff by the
+// search client, so another round here would spend the quota a second time.
+// Server errors and rejected parameters get one search with fewer parameters.
 func (pfh *PartialFailureHandler) applyRecoveryStrategy(
 	ctx context.Context,
 	errorType ErrorType,
 	indices []int,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 	results []QueryResult,
 ) {
 	switch errorType {
-	case ErrorTypeRateLimit:
-		// For rate limit errors, wait longer and retry with spacing
-		pfh.retryWithDelay(ctx, indices, client, queryBuilder, results, 
-			2*time.Minute, 30*time.Second)
-
-	case ErrorTypeNetwork, ErrorTypeTimeout:
-		// For network issues, retry with shorter delay
-		pfh.retryWithDelay(ctx, indices, client, queryBuilder, results,
-			30*time.Second, 10*time.Second)
-
 	case ErrorTypeAPI:
 		// For API errors, try simplified queries
-		pfh.retryWithSimplifiedQueries(ctx, indices, client, queryBuilder, results)
-
-	case ErrorTypeAuthentication:
-		// For auth errors, don't retry (will likely fail again)
-		if pfh.verbose {
-			log.Printf("Skipping retry for %d authentication errors", len(indices))
-		}
+		pfh.retryWithSimplifiedQueries(ctx, indices, searcher, queryBuilder, results)
 
 	case ErrorTypeValidation:
 		// For validation errors, try with fallback parameters
-		pfh.retryWithFallbackParams(ctx, indices, client, queryBuilder, results)
+		pfh.retryWithFallbackParams(ctx, indices, searcher, queryBuilder, results)
 
 	default:
-		// For unknown errors, try simple retry
-		pfh.retryWithDelay(ctx, indices, client, queryBuilder, results,
-			pfh.retryDelay, 0)
+		if pfh.verbose {
+			log.Printf("Skipping retry for %d %s errors", len(indices), errorType)
+		}
 	}
 }
 
-// retryWithDelay retries queries after waiting
-func (pfh *PartialFailureHandler) retryWithDelay(
+// retryWithSimplifiedQueries tries simpler versions of failed queries
+func (pfh *PartialFailureHandler) retryWithSimplifiedQueries(
 	ctx context.Context,
 	indices []int,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 	results []QueryResult,
-	initialDelay, spacingDelay time.Duration,
 ) {
-	if initialDelay > 0 {
-		select {
-		case <-ctx.Done():
-			return
-		case <-time.After(initialDelay):
-		}
-	}
-
-	for i, index := range indices {
-		if spacingDelay > 0 && i > 0 {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(spacingDelay):
-			}
-		}
-
-		query := results[index].Query
-		if pfh.verbose {
-			log.Printf("Retrying query '%s' (was %s)", query.Name, results[index].ErrorType)
-		}
-
-		retryResult := pfh.executeQuery(ctx, query, client, queryBuilder)
-		retryResult.RetryCount = results[index].RetryCount + 1
-		results[index] = retryResult
-	}
+	pfh.retryBroader(ctx, indices, searcher, queryBuilder, results, "simplified", pfh.simplifyQuery)
 }
 
-// retryWithSimplifiedQueries tries simpler versions of failed queries
-func (pfh *PartialFailureHandler) retryWithSimplifiedQueries(
+// retryWithFallbackParams tries queries with fallback parameters
+func (pfh *PartialFailureHandler) retryWithFallbackParams(
 	ctx context.Context,
 	indices []int,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 	results []QueryResult,
 ) {
-	for _, index := range indices {
-		query := results[index].Query
-		simplifiedQuery := pfh.simplifyQuery(query)
-		
-		if pfh.verbose {
-			log.Printf("Retrying query '%s' with simplified parameters", query.Name)
-		}
-
-		retryResult := pfh.executeQuery(ctx, simplifiedQuery, client, queryBuilder)
-		retryResult.RetryCount = results[index].RetryCount + 1
-		results[index] = retryResult
-	}
+	pfh.retryBroader(ctx, indices, searcher, queryBuilder, results, "fallback", pfh.createFallbackQuery)
 }
 
-// retryWithFallbackParams tries queries with fallback parameters
-func (pfh *PartialFailureHandler) retryWithFallbackParams(
+// retryBroader runs each failed query once more as the broader query built by
+// broaden. A query that cannot be broadened without losing all of its search
+// terms keeps its failure, as does one whose recovery search fails. Results
+// of a recovery search are marked Fallback.
+func (pfh *PartialFailureHandler) retryBroader(
 	ctx context.Context,
 	indices []int,
-	client *samgov.RetryClient,
+	searcher samgov.Searcher,
 	queryBuilder *QueryBuilder,
 	results []QueryResult,
+	kind string,
+	broaden func(config.Query) (config.Query, bool),
 ) {
 	for _, index := range indices {
 		query := results[index].Query
-		fallbackQuery := pfh.createFallbackQuery(query)
-		
+		broader, ok := broaden(query)
+		if !ok {
+			if pfh.verbose {
+				log.Printf("Not retrying query '%s': no title or organizationName to keep in a %s search", query.Name, kind)
+			}
+			continue
+		}
+		if pfh.retryAllowed != nil && !pfh.retryAllowed() {
+			if pfh.verbose {
+				log.Printf("Not retrying query '%s': the rest of today's API budget is reserved", query.Name)
+			}
+			return
+		}
+
 		if pfh.verbose {
-			log.Printf("Retrying query '%s' with fallback parameters", query.Name)
+			log.Printf("Retrying query '%s' with %s parameters", query.Name, kind)
 		}
 
-		retryResult := pfh.executeQuery(ctx, fallbackQuery, client, queryBuilder)
-		retryResult.RetryCount = results[index].RetryCount + 1
+		retryResult := pfh.executeQuery(ctx, broader, searcher, queryBuilder)
+		results[index].RetryCount++
+		if !retryResult.Success {
+			continue
+		}
+		retryResult.Query = query
+		retryResult.RetryCount = results[index].RetryCount
+		retryResult.Fallback = true
 		results[index] = retryResult
 	}
 }
 
-// simplifyQuery creates a simplified version of a query
-func (pfh *PartialFailureHandler) simplifyQuery(query config.Query) config.Query {
+// simplifyQuery creates a simplified version of a query, keeping its search
+// terms. It reports false when the query has no title or organizationName,
+// since what is left would match every notice.
+func (pfh *PartialFailureHandler) simplifyQuery(query config.Query) (config.Query, bool) {
 	simplified := query
 	params := make(map[string]interface{})
 
 	// Keep only essential parameters
 	for key, value := range query.Parameters {
 		switch key {
-		case "title", "organizationName":
+		case "title", "organizationName", "lookbackDays":
 			params[key] = value
 		case "ptype":
 			// Simplify to just solicitations
 			params[key] = "s"
 		}
 	}
+	if params["title"] == nil && params["organizationName"] == nil {
+		return query, false
+	}
 
 	simplified.Parameters = params
-	return simplified
+	return simplified, true
 }
 
-// createFallbackQuery creates a fallback version of a query
-func (pfh *PartialFailureHandler) createFallbackQuery(query config.Query) config.Query {
+// createFallbackQuery creates a fallback version of a query that searches on
+// its title or, failing that, its organization. It reports false when the
+// query has neither, rather than falling back to an unfiltered search.
+func (pfh *PartialFailureHandler) createFallbackQuery(query config.Query) (config.Query, bool) {
 	fallback := query
 	params := make(map[string]interface{})
 
@@ -397,12 +409,14 @@ func (pfh *PartialFailureHandler) createFallbackQuery(query config.Query) config
 	} else if org, exists := query.Parameters["organizationName"]; exists {
 		params["organizationName"] = org
 	} else {
-		// Last resort: search for any solicitations
-		params["ptype"] = "s"
+		return query, false
+	}
+	if lookback, exists := query.Parameters["lookbackDays"]; exists {
+		params["lookbackDays"] = lookback
 	}
 
 	fallback.Parameters = params
-	return fallback
+	return fallback, true
 }
 
 // categorizeError determines the type of error
@@ -413,8 +427,16 @@ func (pfh *PartialFailureHandler) categorizeError(err error) ErrorType {
 
 	errorMsg := err.Error()
 
+	switch {
+	case errors.Is(err, samgov.ErrBudgetExhausted):
+		return ErrorTypeBudget
+	case errors.Is(err, samgov.ErrCircuitOpen):
+		return ErrorTypeCircuitOpen
+	}
+
 	// Check for API errors first
-	if apiErr, ok := err.(*samgov.APIError); ok {
+	var apiErr *samgov.APIError
+	if errors.As(err, &apiErr) {
 		switch apiErr.StatusCode {
 		case 401, 403:
 			return ErrorTypeAuthentication
@@ -0,0 +1,168 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// recoverySearcher fails every search that still has a naicsCode, and every
+// search for the title "unrecoverable", with err
+type recoverySearcher struct {
+	err error
+
+	mu       sync.Mutex
+	searches map[string][]map[string]string // Parameters searched, by title
+}
+
+func (s *recoverySearcher) Search(ctx context.Context, params map[string]string) (*samgov.SearchResponse, error) {
+	s.mu.Lock()
+	s.searches[params["title"]] = append(s.searches[params["title"]], params)
+	s.mu.Unlock()
+
+	if params["naicsCode"] != "" || params["title"] == "unrecoverable" {
+		return nil, s.err
+	}
+	return &samgov.SearchResponse{
+		TotalRecords:      1,
+		OpportunitiesData: []samgov.Opportunity{{NoticeID: "N-" + params["title"]}},
+	}, nil
+}
+
+func TestPartialFailureRecovery(t *testing.T) {
+	serverError := &samgov.APIError{StatusCode: 500, Message: "API returned status 500"}
+	badRequest := &samgov.APIError{StatusCode: 400, Message: "API returned status 400"}
+	rateLimited := &samgov.APIError{StatusCode: 429, Message: "API returned status 429"}
+
+	tests := []struct {
+		name         string
+		err          error
+		params       map[string]interface{}
+		budget       bool
+		wantSearches int // Searches made for the failing query
+		wantSuccess  bool
+		wantParams   map[string]string // Parameters of the recovery search
+	}{
+		{
+			name:         "server error retries a simplified query",
+			err:          serverError,
+			params:       map[string]interface{}{"title": "zero trust", "naicsCode": "541512", "ptype": []interface{}{"o", "k"}},
+			budget:       true,
+			wantSearches: 2,
+			wantSuccess:  true,
+			wantParams:   map[string]string{"title": "zero trust", "ptype": "s"},
+		},
+		{
+			name:         "rejected parameters retry with a fallback query",
+			err:          badRequest,
+			params:       map[string]interface{}{"title": "zero trust", "naicsCode": "541512", "ptype": "o"},
+			budget:       true,
+			wantSearches: 2,
+			wantSuccess:  true,
+			wantParams:   map[string]string{"title": "zero trust"},
+		},
+		{
+			name:         "no search terms keeps the failure",
+			err:          badRequest,
+			params:       map[string]interface{}{"naicsCode": "541512", "ptype": "o"},
+			budget:       true,
+			wantSearches: 1,
+		},
+		{
+			name:         "simplifying without search terms keeps the failure",
+			err:          serverError,
+			params:       map[string]interface{}{"naicsCode": "541512", "ptype": "o"},
+			budget:       true,
+			wantSearches: 1,
+		},
+		{
+			name:         "no retry when the budget is reserved",
+			err:          serverError,
+			params:       map[string]interface{}{"title": "zero trust", "naicsCode": "541512"},
+			budget:       false,
+			wantSearches: 1,
+		},
+		{
+			name:         "rate limits are not retried again",
+			err:          rateLimited,
+			params:       map[string]interface{}{"title": "zero trust", "naicsCode": "541512"},
+			budget:       true,
+			wantSearches: 1,
+		},
+		{
+			name:         "failed recovery search keeps the failure",
+			err:          serverError,
+			params:       map[string]interface{}{"title": "unrecoverable", "naicsCode": "541512"},
+			budget:       true,
+			wantSearches: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &recoverySearcher{err: tt.err, searches: make(map[string][]map[string]string)}
+			handler := NewPartialFailureHandler(false)
+			handler.SetRetryBudget(func() bool { return tt.budget })
+
+			// Two healthy queries keep the failure rate below the threshold
+			queries := []config.Query{
+				{Name: "Failing", Enabled: true, Parameters: tt.params},
+				{Name: "Healthy 1", Enabled: true, Parameters: map[string]interface{}{"title": "healthy one"}},
+				{Name: "Healthy 2", Enabled: true, Parameters: map[string]interface{}{"title": "healthy two"}},
+			}
+			results, err := handler.ExecuteQueriesWithRecovery(context.Background(), queries, searcher, NewQueryBuilder(7))
+			if err != nil {
+				t.Fatalf("ExecuteQueriesWithRecovery: %v", err)
+			}
+
+			failing := searcher.searches[stringParam(tt.params["title"])]
+			if len(failing) != tt.wantSearches {
+				t.Fatalf("searches for the failing query = %d, want %d", len(failing), tt.wantSearches)
+			}
+
+			result := results[0]
+			if result.Query.Name != "Failing" || result.Query.Parameters["naicsCode"] == nil {
+				t.Errorf("result query = %+v, want the original query", result.Query)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Fatalf("success = %v, want %v (error %v)", result.Success, tt.wantSuccess, result.Error)
+			}
+			if !tt.wantSuccess {
+				if result.Error == nil || result.Fallback {
+					t.Errorf("failed result has error %v and fallback %v", result.Error, result.Fallback)
+				}
+				return
+			}
+
+			if !result.Fallback {
+				t.Errorf("recovered result is not marked as a fallback")
+			}
+			if result.RetryCount != 1 {
+				t.Errorf("retry count = %d, want 1", result.RetryCount)
+			}
+			recovery := failing[len(failing)-1]
+			for _, key := range []string{"title", "naicsCode", "ptype", "organizationName"} {
+				if recovery[key] != tt.wantParams[key] {
+					t.Errorf("recovery search %s = %q, want %q", key, recovery[key], tt.wantParams[key])
+				}
+			}
+			if recovery["postedFrom"] != failing[0]["postedFrom"] {
+				t.Errorf("recovery search posted from %s, want %s", recovery["postedFrom"], failing[0]["postedFrom"])
+			}
+			for _, healthy := range results[1:] {
+				if !healthy.Success || healthy.Fallback {
+					t.Errorf("%s: success %v, fallback %v", healthy.Query.Name, healthy.Success, healthy.Fallback)
+				}
+			}
+		})
+	}
+}
+
+// stringParam returns a string query parameter, or "" for a missing one
+func stringParam(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// unseenExpiry is how long a notice without a deadline can go unseen in
+// searches before its issue is closed
+const unseenExpiry = 30 * 24 * time.Hour
+
+// closeExpiredIssues closes the GitHub issues of tracked notices that have
+// expired or been awarded, given the opportunities seen in this run and the
+// awards newly linked to tracked solicitations
+func (m *Monitor) closeExpiredIssues(ctx context.Context, results []samgov.QueryResult, awarded []samgov.AwardRecord) (int, error) {
+	if !m.notifyMgr.HasIssueTrackers() {
+		return 0, nil
+	}
+
+	current := make([]samgov.Opportunity, 0)
+	for _, result := range results {
+		if result.Error == nil {
+			current = append(current, result.Opportunities...)
+		}
+	}
+
+	expired := NewOpportunityDiffer(m.verbose).DetectExpiredOpportunities(current, m.state, unseenExpiry)
+	if len(expired) == 0 && len(awarded) == 0 {
+		return 0, nil
+	}
+
+	// Awards explain the closing better than expiry does
+	closures := awardClosures(awarded)
+	closing := make(map[string]bool)
+	for _, closure := range closures {
+		closing[closure.NoticeID] = true
+	}
+	for _, opp := range expired {
+		if closing[opp.NoticeID] {
+			continue
+		}
+		closures = append(closures, notify.IssueClosure{
+			NoticeID: opp.NoticeID,
+			Title:    opp.Title,
+			Reason:   opp.Reason,
+			Detail:   opp.Detail,
+		})
+	}
+
+	closed, err := m.notifyMgr.CloseIssues(ctx, closures)
+	if err != nil {
+		log.Printf("Closed %d issues of expired opportunities: %v", closed, err)
+	}
+	return closed, err
+}
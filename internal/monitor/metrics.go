@@ -13,24 +13,29 @@ import (
 
 // Metrics tracks performance and operational statistics
 type Metrics struct {
-	mu sync.RWMutex
-
 	// Run-level metrics
-	TotalRuns           int                 `json:"total_runs"`
-	LastRunTime         time.Time           `json:"last_run_time"`
-	AverageRunDuration  time.Duration       `json:"average_run_duration"`
-	RunDurations        []time.Duration     `json:"run_durations"`
+	TotalRuns          int             `json:"total_runs"`
+	LastRunTime        time.Time       `json:"last_run_time"`
+	AverageRunDuration time.Duration   `json:"average_run_duration"`
+	RunDurations       []time.Duration `json:"run_durations"`
+	RunDuration        *Histogram      `json:"run_duration"`
+
+	// Query-level metrics
+	QueryMetrics map[string]*QueryMetrics `json:"query_metrics"`
 
-	// Query-level metrics  
-	QueryMetrics        map[string]*QueryMetrics `json:"query_metrics"`
-	
 	// API-level metrics
-	TotalAPIRequests    int                 `json:"total_api_requests"`
-	SuccessfulRequests  int                 `json:"successful_requests"`
-	FailedRequests      int                 `json:"failed_requests"`
-	TotalRetries        int                 `json:"total_retries"`
-	AverageResponseTime time.Duration       `json:"average_response_time"`
-	ResponseTimes       []time.Duration     `json:"response_times"`
+	TotalAPIRequests    int             `json:"total_api_requests"`
+	SuccessfulRequests  int             `json:"successful_requests"`
+	FailedRequests      int             `json:"failed_requests"`
+	TotalRetries        int             `json:"total_retries"`
+	AverageResponseTime time.Duration   `json:"average_response_time"`
+	ResponseTimes       []time.Duration `json:"response_times"`
+	ResponseTime        *Histogram      `json:"response_time"`
+	RetryReasons        map[string]int  `json:"retry_reasons"`
+
+	// Daily API quota as of the last run
+	QuotaLimit     int `json:"quota_limit"`
+	QuotaRemaining int `json:"quota_remaining"`
 
 	// Opportunity metrics
 	TotalOpportunities     int            `json:"total_opportunities"`
@@ -44,9 +49,10 @@ type Metrics struct {
 	LastErrorTime       time.Time           `json:"last_error_time"`
 
 	// Notification metrics
-	NotificationsSent   int                 `json:"notifications_sent"`
-	NotificationErrors  int                 `json:"notification_errors"`
-	NotificationsByType map[string]int      `json:"notifications_by_type"`
+	NotificationsSent        int            `json:"notifications_sent"`
+	NotificationErrors       int            `json:"notification_errors"`
+	NotificationsByType      map[string]int `json:"notifications_by_type"`
+	NotificationErrorsByType map[string]int `json:"notification_errors_by_type"`
 
 	// Performance thresholds
 	SlowQueryThreshold    time.Duration     `json:"slow_query_threshold"`
@@ -55,19 +61,21 @@ type Metrics struct {
 
 // QueryMetrics tracks metrics for individual queries
 type QueryMetrics struct {
-	Name                     string        `json:"name"`
-	ExecutionCount           int           `json:"execution_count"`
-	SuccessCount             int           `json:"success_count"`
-	FailureCount             int           `json:"failure_count"`
-	AverageDuration          time.Duration `json:"average_duration"`
-	LastExecuted             time.Time     `json:"last_executed"`
-	TotalOpportunities       int           `json:"total_opportunities"`
-	LastError                string        `json:"last_error"`
-	Durations                []time.Duration `json:"durations"`
-	AverageTime              time.Duration `json:"average_time"`
-	LastOpportunityCount     int           `json:"last_opportunity_count"`
-	TotalOpportunitiesFound  int           `json:"total_opportunities_found"`
-	ErrorCount               int           `json:"error_count"`
+	Name                    string          `json:"name"`
+	ExecutionCount          int             `json:"execution_count"`
+	SuccessCount            int             `json:"success_count"`
+	FailureCount            int             `json:"failure_count"`
+	AverageDuration         time.Duration   `json:"average_duration"`
+	LastExecuted            time.Time       `json:"last_executed"`
+	TotalOpportunities      int             `json:"total_opportunities"`
+	LastError               string          `json:"last_error"`
+	Durations               []time.Duration `json:"durations"`
+	AverageTime             time.Duration   `json:"average_time"`
+	LastOpportunityCount    int             `json:"last_opportunity_count"`
+	TotalOpportunitiesFound int             `json:"total_opportunities_found"`
+	ErrorCount              int             `json:"error_count"`
+	NewOpportunities        int             `json:"new_opportunities"`
+	UpdatedOpportunities    int             `json:"updated_opportunities"`
 }
 
 // SlowQuery represents a query that exceeded performance thresholds
@@ -77,8 +85,65 @@ type SlowQuery struct {
 	Timestamp time.Time     `json:"timestamp"`
 }
 
+// Bucket upper bounds, in seconds, of the duration histograms
+var (
+	responseTimeBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	runDurationBuckets  = []float64{1, 5, 15, 30, 60, 120, 300, 600}
+)
+
+// Histogram counts observations in cumulative buckets, the way Prometheus
+// histograms do, so it can be kept across runs and exported as is
+type Histogram struct {
+	Buckets []float64 `json:"buckets"` // Upper bounds
+	Counts  []int     `json:"counts"`  // Observations at or below each bound
+	Count   int       `json:"count"`
+	Sum     float64   `json:"sum"`
+}
+
+// NewHistogram creates an empty histogram with the given bucket bounds
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		Buckets: append([]float64(nil), buckets...),
+		Counts:  make([]int, len(buckets)),
+	}
+}
+
+// Observe adds a value to the histogram
+func (h *Histogram) Observe(value float64) {
+	h.Count++
+	h.Sum += value
+	for i, bound := range h.Buckets {
+		if value <= bound {
+			h.Counts[i]++
+		}
+	}
+}
+
+// copy returns a deep copy of the histogram
+func (h *Histogram) copy() *Histogram {
+	c := *h
+	c.Buckets = append([]float64(nil), h.Buckets...)
+	c.Counts = append([]int(nil), h.Counts...)
+	return &c
+}
+
+// validHistogram returns h, or an empty histogram when h is missing or its
+// buckets no longer match, such as in a metrics file from an older version
+func validHistogram(h *Histogram, buckets []float64) *Histogram {
+	if h == nil || len(h.Buckets) != len(buckets) || len(h.Counts) != len(buckets) {
+		return NewHistogram(buckets)
+	}
+	for i, bound := range buckets {
+		if h.Buckets[i] != bound {
+			return NewHistogram(buckets)
+		}
+	}
+	return h
+}
+
 // MetricsCollector manages metrics collection and reporting
 type MetricsCollector struct {
+	mu       sync.RWMutex
 	metrics  *Metrics
 	verbose  bool
 	filePath string
@@ -90,25 +155,32 @@ func NewMetricsCollector(filePath string, verbose bool) *MetricsCollector {
 		filePath: filePath,
 		verbose:  verbose,
 		metrics: &Metrics{
-			QueryMetrics:          make(map[string]*QueryMetrics),
-			OpportunitiesPerQuery: make(map[string]int),
-			ErrorCounts:          make(map[string]int),
-			NotificationsByType:  make(map[string]int),
-			SlowQueryThreshold:   10 * time.Second, // Default threshold
+			QueryMetrics:             make(map[string]*QueryMetrics),
+			OpportunitiesPerQuery:    make(map[string]int),
+			ErrorCounts:              make(map[string]int),
+			NotificationsByType:      make(map[string]int),
+			NotificationErrorsByType: make(map[string]int),
+			RetryReasons:             make(map[string]int),
+			ResponseTime:             NewHistogram(responseTimeBuckets),
+			RunDuration:              NewHistogram(runDurationBuckets),
+			SlowQueryThreshold:       10 * time.Second, // Default threshold
 		},
 	}
 
-	// Load existing metrics if file exists
-	mc.loadMetricsFromFile()
-	
+	// Load existing metrics if file exists; without a file they are kept in
+	// memory only
+	if filePath != "" {
+		mc.loadMetricsFromFile()
+	}
+
 	return mc
 }
 
 // RecordRunStart marks the beginning of a monitoring run
 func (mc *MetricsCollector) RecordRunStart() time.Time {
-	mc.metrics.mu.Lock()
-	defer mc.metrics.mu.Unlock()
-	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	mc.metrics.TotalRuns++
 	startTime := time.Now()
 	
@@ -121,9 +193,9 @@ func (mc *MetricsCollector) RecordRunStart() time.Time {
 
 // RecordRunEnd marks the completion of a monitoring run
 func (mc *MetricsCollector) RecordRunEnd(startTime time.Time) {
-	mc.metrics.mu.Lock()
-	defer mc.metrics.mu.Unlock()
-	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	duration := time.Since(startTime)
 	mc.metrics.LastRunTime = time.Now()
 	mc.metrics.RunDurations = append(mc.metrics.RunDurations, duration)
@@ -132,14 +204,15 @@ func (mc *MetricsCollector) RecordRunEnd(startTime time.Time) {
 	if len(mc.metrics.RunDurations) > 100 {
 		mc.metrics.RunDurations = mc.metrics.RunDurations[1:]
 	}
-	
+
 	// Calculate average
 	total := time.Duration(0)
 	for _, d := range mc.metrics.RunDurations {
 		total += d
 	}
 	mc.metrics.AverageRunDuration = total / time.Duration(len(mc.metrics.RunDurations))
-	
+	mc.metrics.RunDuration.Observe(duration.Seconds())
+
 	if mc.verbose {
 		log.Printf("Monitoring run completed in %v (average: %v)", 
 			duration, mc.metrics.AverageRunDuration)
@@ -148,9 +221,9 @@ func (mc *MetricsCollector) RecordRunEnd(startTime time.Time) {
 
 // RecordQueryExecution records metrics for a query execution
 func (mc *MetricsCollector) RecordQueryExecution(query config.Query, duration time.Duration, success bool, error error, opportunityCount int) {
-	mc.metrics.mu.Lock()
-	defer mc.metrics.mu.Unlock()
-	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	queryName := query.Name
 	
 	// Initialize query metrics if not exists
@@ -223,9 +296,9 @@ func (mc *MetricsCollector) RecordQueryExecution(query config.Query, duration ti
 
 // RecordAPIRequest records metrics for API requests
 func (mc *MetricsCollector) RecordAPIRequest(duration time.Duration, success bool, retryCount int) {
-	mc.metrics.mu.Lock()
-	defer mc.metrics.mu.Unlock()
-	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	mc.metrics.TotalAPIRequests++
 	mc.metrics.TotalRetries += retryCount
 	mc.metrics.ResponseTimes = append(mc.metrics.ResponseTimes, duration)
@@ -234,14 +307,15 @@ func (mc *MetricsCollector) RecordAPIRequest(duration time.Duration, success boo
 	if len(mc.metrics.ResponseTimes) > 200 {
 		mc.metrics.ResponseTimes = mc.metrics.ResponseTimes[1:]
 	}
-	
+
 	// Calculate average response time
 	total := time.Duration(0)
 	for _, rt := range mc.metrics.ResponseTimes {
 		total += rt
 	}
 	mc.metrics.AverageResponseTime = total / time.Duration(len(mc.metrics.ResponseTimes))
-	
+	mc.metrics.ResponseTime.Observe(duration.Seconds())
+
 	if success {
 		mc.metrics.SuccessfulRequests++
 	} else {
@@ -251,9 +325,9 @@ func (mc *MetricsCollector) RecordAPIRequest(duration time.Duration, success boo
 
 // RecordOpportunities records opportunity-related metrics
 func (mc *MetricsCollector) RecordOpportunities(total, new, updated int) {
-	mc.metrics.mu.Lock()
-	defer mc.metrics.mu.Unlock()
-	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	mc.metrics.TotalOpportunities += total
 	mc.metrics.NewOpportunities += new
 	mc.metrics.UpdatedOpportunities += updated
@@ -265,22 +339,59 @@ func (mc *MetricsCollector) RecordOpportunities(total, new, updated int) {
 
 // RecordNotification records notification metrics
 func (mc *MetricsCollector) RecordNotification(notificationType string, success bool) {
-	mc.metrics.mu.Lock()
-	defer mc.metrics.mu.Unlock()
-	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	if success {
 		mc.metrics.NotificationsSent++
 		mc.metrics.NotificationsByType[notificationType]++
 	} else {
 		mc.metrics.NotificationErrors++
+		mc.metrics.NotificationErrorsByType[notificationType]++
+	}
+}
+
+// RecordRetries records the retries of a run and the errors that caused them
+func (mc *MetricsCollector) RecordRetries(retries int, reasons map[string]int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.metrics.TotalRetries += retries
+	for reason, count := range reasons {
+		mc.metrics.RetryReasons[reason] += count
 	}
 }
 
+// RecordQuota records how much of the daily API quota is left
+func (mc *MetricsCollector) RecordQuota(remaining, limit int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.metrics.QuotaRemaining = remaining
+	mc.metrics.QuotaLimit = limit
+}
+
+// RecordQueryChanges records the new and updated opportunities a query found
+func (mc *MetricsCollector) RecordQueryChanges(queryName string, new, updated int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.metrics.QueryMetrics[queryName] == nil {
+		mc.metrics.QueryMetrics[queryName] = &QueryMetrics{
+			Name: queryName,
+		}
+	}
+
+	qm := mc.metrics.QueryMetrics[queryName]
+	qm.NewOpportunities += new
+	qm.UpdatedOpportunities += updated
+}
+
 // GetMetrics returns a copy of current metrics
 func (mc *MetricsCollector) GetMetrics() Metrics {
-	mc.metrics.mu.RLock()
-	defer mc.metrics.mu.RUnlock()
-	
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
 	// Deep copy the metrics
 	metricsCopy := *mc.metrics
 	
@@ -305,25 +416,43 @@ func (mc *MetricsCollector) GetMetrics() Metrics {
 	for k, v := range mc.metrics.NotificationsByType {
 		metricsCopy.NotificationsByType[k] = v
 	}
-	
+
+	metricsCopy.NotificationErrorsByType = make(map[string]int)
+	for k, v := range mc.metrics.NotificationErrorsByType {
+		metricsCopy.NotificationErrorsByType[k] = v
+	}
+
+	metricsCopy.RetryReasons = make(map[string]int)
+	for k, v := range mc.metrics.RetryReasons {
+		metricsCopy.RetryReasons[k] = v
+	}
+
+	metricsCopy.ResponseTime = mc.metrics.ResponseTime.copy()
+	metricsCopy.RunDuration = mc.metrics.RunDuration.copy()
+
 	return metricsCopy
 }
 
-// SaveMetrics persists metrics to file
+// SaveMetrics persists metrics to file. Like the state file, it is written
+// to a temporary file and renamed into place, so readers never see half of it.
 func (mc *MetricsCollector) SaveMetrics() error {
-	mc.metrics.mu.RLock()
-	defer mc.metrics.mu.RUnlock()
-	
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
 	data, err := json.MarshalIndent(mc.metrics, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling metrics: %w", err)
 	}
-	
-	err = os.WriteFile(mc.filePath, data, 0644)
-	if err != nil {
+
+	tempFile := mc.filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
 		return fmt.Errorf("writing metrics file: %w", err)
 	}
-	
+	if err := os.Rename(tempFile, mc.filePath); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("renaming metrics file: %w", err)
+	}
+
 	if mc.verbose {
 		log.Printf("Metrics saved to %s", mc.filePath)
 	}
@@ -349,7 +478,7 @@ func (mc *MetricsCollector) loadMetricsFromFile() {
 		}
 		return
 	}
-	
+
 	// Initialize maps if nil
 	if loadedMetrics.QueryMetrics == nil {
 		loadedMetrics.QueryMetrics = make(map[string]*QueryMetrics)
@@ -363,7 +492,15 @@ func (mc *MetricsCollector) loadMetricsFromFile() {
 	if loadedMetrics.NotificationsByType == nil {
 		loadedMetrics.NotificationsByType = make(map[string]int)
 	}
-	
+	if loadedMetrics.NotificationErrorsByType == nil {
+		loadedMetrics.NotificationErrorsByType = make(map[string]int)
+	}
+	if loadedMetrics.RetryReasons == nil {
+		loadedMetrics.RetryReasons = make(map[string]int)
+	}
+	loadedMetrics.ResponseTime = validHistogram(loadedMetrics.ResponseTime, responseTimeBuckets)
+	loadedMetrics.RunDuration = validHistogram(loadedMetrics.RunDuration, runDurationBuckets)
+
 	mc.metrics = &loadedMetrics
 	
 	if mc.verbose {
@@ -5,52 +5,101 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/yourusername/sam-gov-monitor/internal/attachments"
+	"github.com/yourusername/sam-gov-monitor/internal/cache"
 	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/filter"
 	"github.com/yourusername/sam-gov-monitor/internal/notify"
 	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+	"github.com/yourusername/sam-gov-monitor/internal/scoring"
 )
 
 // Monitor manages the monitoring process
 type Monitor struct {
-	client      *samgov.Client
-	config      *config.Config
-	state       *State
-	builder     *QueryBuilder
-	notifyMgr   *notify.NotificationManager
-	verbose     bool
-	dryRun      bool
-	lookbackDays int
-	debugEmail  bool
+	client          *samgov.Client
+	searcher        samgov.Searcher
+	cache           *cache.CachedSearcher
+	config          *config.Config
+	state           StateStore
+	builder         *QueryBuilder
+	notifyMgr       *notify.NotificationManager
+	digest          *notify.DigestNotificationManager
+	outbox          *notify.Outbox
+	verbose         bool
+	dryRun          bool
+	lookbackDays    int
+	debugEmail      bool
+	limiter         *samgov.RateLimiter
+	retrier         *samgov.StatsTrackingRetryClient
+	breaker         *samgov.CircuitBreaker
+	recovery        *PartialFailureHandler
+	descriptions    *samgov.DescriptionFetcher
+	downloader      *attachments.Downloader
+	calendarFile    string
+	metrics         *MetricsCollector
+	metricsFile     string
+	metricsTextfile string
 }
 
 // Options for creating a new Monitor
 type Options struct {
-	APIKey       string
-	Config       *config.Config
-	StateFile    string
-	Verbose      bool
-	DryRun       bool
-	LookbackDays int
-	DebugEmail   bool
+	APIKey          string
+	Config          *config.Config
+	StateFile       string
+	StateBackend    string // "json" (default) or "sqlite"
+	Verbose         bool
+	DryRun          bool
+	LookbackDays    int
+	DebugEmail      bool
+	CacheDir        string        // Directory for cached search responses
+	CacheTTL        time.Duration // How long cached responses are served
+	NoCache         bool          // Disable the response cache
+	CalendarFile    string        // Deadline feed (.ics) rewritten after each run; empty disables
+	MetricsFile     string        // Metrics kept across runs; empty keeps them in memory
+	MetricsTextfile string        // Prometheus text file rewritten after each run; empty disables
 }
 
+// DefaultCacheTTL is how long identical searches are served from cache
+const DefaultCacheTTL = 1 * time.Hour
+
+const (
+	// circuitBreakerMaxFailures consecutive API failures open the circuit
+	circuitBreakerMaxFailures = 3
+	// circuitBreakerResetTimeout is how long an open circuit rejects searches
+	circuitBreakerResetTimeout = 5 * time.Minute
+)
+
 // RunReport contains the results of a monitoring run
 type RunReport struct {
-	StartTime       time.Time            `json:"start_time"`
-	EndTime         time.Time            `json:"end_time"`
-	Duration        time.Duration        `json:"duration"`
-	QueriesRun      int                  `json:"queries_run"`
-	QueriesSucceded int                  `json:"queries_succeeded"`
-	QueriesFailed   int                  `json:"queries_failed"`
-	NewOpps         int                  `json:"new_opportunities"`
-	UpdatedOpps     int                  `json:"updated_opportunities"`
-	TotalOpps       int                  `json:"total_opportunities"`
-	Notifications   int                  `json:"notifications_sent"`
-	Errors          []string             `json:"errors"`
-	QueryResults    []samgov.QueryResult `json:"query_results"`
+	StartTime           time.Time            `json:"start_time"`
+	EndTime             time.Time            `json:"end_time"`
+	Duration            time.Duration        `json:"duration"`
+	QueriesRun          int                  `json:"queries_run"`
+	QueriesSucceded     int                  `json:"queries_succeeded"`
+	QueriesFailed       int                  `json:"queries_failed"`
+	QueriesTruncated    int                  `json:"queries_truncated"`
+	QueriesFallback     int                  `json:"queries_fallback"`
+	NewOpps             int                  `json:"new_opportunities"`
+	UpdatedOpps         int                  `json:"updated_opportunities"`
+	TotalOpps           int                  `json:"total_opportunities"`
+	Notifications       int                  `json:"notifications_sent"`
+	Reminders           int                  `json:"reminders_sent"`
+	Redelivered         int                  `json:"notifications_redelivered"`
+	IssuesClosed        int                  `json:"issues_closed"`
+	AwardsRecorded      int                  `json:"awards_recorded"`
+	AwardsLinked        int                  `json:"awards_linked"`
+	CacheHits           int                  `json:"cache_hits"`
+	CacheMisses         int                  `json:"cache_misses"`
+	RetryStats          samgov.RetryStats    `json:"retry_stats"`
+	CircuitBreakerState string               `json:"circuit_breaker_state"`
+	ErrorReport         string               `json:"error_report,omitempty"`
+	Errors              []string             `json:"errors"`
+	QueryResults        []samgov.QueryResult `json:"query_results"`
 }
 
 // New creates a new Monitor instance
@@ -68,44 +117,169 @@ func New(opts Options) (*Monitor, error) {
 	}
 
 	// Initialize state
-	state, err := LoadState(opts.StateFile)
+	state, err := OpenStateStore(opts.StateBackend, opts.StateFile, opts.Verbose)
 	if err != nil {
 		return nil, fmt.Errorf("loading state: %w", err)
 	}
 
-	// Initialize notification manager
+	// Initialize notification manager. Queries with digest enabled queue
+	// their notifications in state until the digest schedule comes round.
+	digestSchedule, err := opts.Config.Notifications.Digest.ParseSchedule()
+	if err != nil {
+		return nil, err
+	}
 	notifyConfig := buildNotificationConfig()
-	notifyMgr := notify.NewNotificationManager(notifyConfig, opts.Verbose)
+	digest := notify.NewDigestNotificationManager(notifyConfig, opts.Verbose, true)
+	digest.SetDigestSchedule(digestSchedule)
+	digest.SetDigestStore(state)
+	notifyMgr := digest.NotificationManager
+	registerNamedChannels(notifyMgr, opts.Config, opts.Verbose)
+	warnUnreachableChannels(notifyMgr, opts.Config)
+
+	// Every delivery is recorded in the outbox, saved with the state, so
+	// that channels that were down are retried on the next run
+	maxAttempts, retention, err := opts.Config.Notifications.Outbox.ParsePolicy()
+	if err != nil {
+		return nil, err
+	}
+	outbox := notify.NewOutbox(state, notify.OutboxPolicy{MaxAttempts: maxAttempts, Retention: retention})
+	notifyMgr.SetOutbox(outbox)
+
+	// GitHub issue numbers are kept per notice so amendments and expiry
+	// update the existing issue
+	notifyMgr.SetIssueStore(state)
+
+	// Queries and channels may name user-supplied templates, checked when
+	// the config was loaded
+	notifyMgr.SetTemplateLibrary(notify.NewTemplateLibrary(opts.Config.Notifications.TemplatePath()))
+
+	// Every HTTP attempt waits on the rate limiter; cache hits never reach it.
+	// Searches are retried, and repeated failures open the circuit breaker.
+	limiter := samgov.NewRateLimiter(samgov.RateLimitConfigFromEnv(), state, opts.Verbose)
+	client := samgov.NewClient(opts.APIKey)
+	client.SetRateLimiter(limiter)
+	retrier := samgov.NewStatsTrackingRetryClientFromClient(client, samgov.RetryConfigFromEnv(), opts.Verbose)
+	breaker := samgov.NewCircuitBreaker(circuitBreakerMaxFailures, circuitBreakerResetTimeout)
+	var searcher samgov.Searcher = samgov.NewCircuitBreakerSearcher(retrier, breaker)
+
+	// Each query holds one reserved request, and recovery searches for failed
+	// queries share the retry budget
+	recovery := NewPartialFailureHandler(opts.Verbose)
+	recovery.SetRateLimiter(limiter)
+	recovery.SetRetryBudget(retrier.BudgetAllowsRetry)
+
+	// API requests and deliveries are recorded for the metrics endpoint. The
+	// monitor logs runs and queries itself, so the collector stays quiet.
+	metrics := NewMetricsCollector(opts.MetricsFile, false)
+	client.SetRequestObserver(func(duration time.Duration, err error) {
+		metrics.RecordAPIRequest(duration, err == nil, 0)
+	})
+	notifyMgr.SetDeliveryObserver(func(channel string, err error) {
+		metrics.RecordNotification(channel, err == nil)
+	})
+
+	// Descriptions are cached next to the state file so each is downloaded once
+	descriptionDir := ""
+	if opts.StateFile != "" {
+		descriptionDir = filepath.Join(filepath.Dir(opts.StateFile), "descriptions")
+	}
+	descriptions, err := samgov.NewDescriptionFetcher(client, descriptionDir, opts.Verbose)
+	if err != nil {
+		return nil, fmt.Errorf("initializing description fetcher: %w", err)
+	}
+
+	// Attachments are kept per notice next to the state file; without one
+	// there is nowhere to record them, so downloads are disabled
+	var downloader *attachments.Downloader
+	if opts.StateFile != "" {
+		downloader, err = attachments.NewDownloader(client, filepath.Join(filepath.Dir(opts.StateFile), "attachments"), opts.Verbose)
+		if err != nil {
+			return nil, fmt.Errorf("initializing attachment downloader: %w", err)
+		}
+	}
+
+	var cached *cache.CachedSearcher
+	if !opts.NoCache && opts.CacheDir != "" {
+		ttl := opts.CacheTTL
+		if ttl <= 0 {
+			ttl = DefaultCacheTTL
+		}
+		responseCache, err := cache.NewCache(opts.CacheDir, ttl, opts.Verbose)
+		if err != nil {
+			return nil, fmt.Errorf("initializing cache: %w", err)
+		}
+		cached = cache.NewCachedSearcher(searcher, responseCache, opts.Verbose)
+		searcher = cached
+	}
 
 	return &Monitor{
-		client:       samgov.NewClient(opts.APIKey),
-		config:       opts.Config,
-		state:        state,
-		builder:      NewQueryBuilder(opts.LookbackDays),
-		notifyMgr:    notifyMgr,
-		verbose:      opts.Verbose,
-		dryRun:       opts.DryRun,
-		lookbackDays: opts.LookbackDays,
-		debugEmail:   opts.DebugEmail,
+		client:          client,
+		searcher:        searcher,
+		cache:           cached,
+		config:          opts.Config,
+		state:           state,
+		builder:         NewQueryBuilder(opts.LookbackDays),
+		notifyMgr:       notifyMgr,
+		digest:          digest,
+		outbox:          outbox,
+		verbose:         opts.Verbose,
+		dryRun:          opts.DryRun,
+		lookbackDays:    opts.LookbackDays,
+		debugEmail:      opts.DebugEmail,
+		limiter:         limiter,
+		retrier:         retrier,
+		breaker:         breaker,
+		recovery:        recovery,
+		descriptions:    descriptions,
+		downloader:      downloader,
+		calendarFile:    opts.CalendarFile,
+		metrics:         metrics,
+		metricsFile:     opts.MetricsFile,
+		metricsTextfile: opts.MetricsTextfile,
 	}, nil
 }
 
+// Close releases the state store
+func (m *Monitor) Close() error {
+	return m.state.Close()
+}
+
 // Run executes all enabled queries and processes results
 func (m *Monitor) Run(ctx context.Context) error {
+	return m.RunQueries(ctx, m.config.GetEnabledQueries())
+}
+
+// RunQueries executes the given queries and processes results
+func (m *Monitor) RunQueries(ctx context.Context, queries []config.Query) error {
 	report := &RunReport{
 		StartTime:    time.Now(),
 		QueryResults: make([]samgov.QueryResult, 0),
 		Errors:       make([]string, 0),
 	}
 
+	var cacheStart cache.CacheStats
+	if m.cache != nil {
+		cacheStart = m.cache.Stats()
+	}
+	m.retrier.ResetStats()
+	runStart := m.metrics.RecordRunStart()
+
 	defer func() {
 		report.EndTime = time.Now()
 		report.Duration = report.EndTime.Sub(report.StartTime)
+		if m.cache != nil {
+			stats := m.cache.Stats()
+			report.CacheHits = stats.Hits - cacheStart.Hits
+			report.CacheMisses = stats.Misses - cacheStart.Misses
+		}
+		report.RetryStats = m.retrier.GetStats()
+		report.CircuitBreakerState = m.breaker.State()
+		m.recordRunMetrics(report, runStart)
 		m.logReport(report)
 	}()
 
 	if m.verbose {
-		log.Printf("Starting monitoring run with %d enabled queries", len(m.config.GetEnabledQueries()))
+		log.Printf("Starting monitoring run with %d queries", len(queries))
 	}
 
 	// Send debug email if requested (before processing queries)
@@ -118,8 +292,17 @@ func (m *Monitor) Run(ctx context.Context) error {
 		}
 	}
 
-	// Execute all queries concurrently
-	results, err := m.runQueries(ctx)
+	// Resend deliveries that failed on earlier runs before anything new
+	if !m.dryRun {
+		redelivered, err := m.retryDeliveries(ctx)
+		report.Redelivered = redelivered
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("redelivery: %s", err.Error()))
+		}
+	}
+
+	// Execute all queries with retry and partial failure recovery
+	results, err := m.runQueries(ctx, queries, report)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 		return fmt.Errorf("running queries: %w", err)
@@ -128,7 +311,12 @@ func (m *Monitor) Run(ctx context.Context) error {
 	report.QueryResults = results
 	report.QueriesRun = len(results)
 
-	// Process results
+	// Detect new and updated opportunities for every query before state
+	// changes, so a notice matched by several queries is seen the same way
+	// by each of them
+	matches := make([]queryMatch, 0, len(results))
+	awards := make([]awardMatch, 0)
+	awardNotices := make(map[string]bool)
 	for _, result := range results {
 		if result.Error != nil {
 			report.QueriesFailed++
@@ -136,50 +324,141 @@ func (m *Monitor) Run(ctx context.Context) error {
 			continue
 		}
 
+		// A broader recovery search is not the query's own result, so its
+		// notices are neither notified nor stored
+		if result.Fallback {
+			report.QueriesFallback++
+			report.Errors = append(report.Errors, fmt.Sprintf("Query '%s': only a broader recovery search succeeded; %d results not notified", result.QueryName, len(result.Opportunities)))
+			continue
+		}
+
 		report.QueriesSucceded++
 		report.TotalOpps += len(result.Opportunities)
+		if result.Truncated {
+			report.QueriesTruncated++
+		}
 
-		// Detect new and updated opportunities
-		diff := m.diffOpportunities(result.Opportunities)
-		
-		newCount := len(diff.New)
-		updatedCount := len(diff.Updated)
-		
-		report.NewOpps += newCount
-		report.UpdatedOpps += updatedCount
+		// Award notices of queries that track awards are recorded, not reported
+		query := m.findQueryByName(result.QueryName)
+		opportunities := result.Opportunities
+		if query != nil && query.Advanced.TrackAwards {
+			var found []samgov.Opportunity
+			opportunities, found = splitAwardNotices(opportunities)
+			for _, opp := range found {
+				awards = append(awards, awardMatch{Query: *query, Opportunity: opp})
+				awardNotices[opp.NoticeID] = true
+			}
+		}
+
+		diff := m.diffOpportunities(opportunities)
+		m.metrics.RecordQueryChanges(result.QueryName, len(diff.New), len(diff.Updated))
 
 		if m.verbose {
-			log.Printf("Query '%s': %d total, %d new, %d updated", 
-				result.QueryName, len(result.Opportunities), newCount, updatedCount)
+			log.Printf("Query '%s': %d total, %d new, %d updated",
+				result.QueryName, len(result.Opportunities), len(diff.New), len(diff.Updated))
 		}
 
-		// Update state with all opportunities
-		for _, opp := range result.Opportunities {
-			m.state.AddOpportunity(opp)
+		if query != nil {
+			matches = append(matches, queryMatch{Query: *query, Diff: diff, FilteredOut: result.FilteredOut})
 		}
+	}
 
-		// Send notifications for new/updated opportunities
-		if !m.dryRun && (newCount > 0 || updatedCount > 0) {
-			query := m.findQueryByName(result.QueryName)
-			if query != nil {
-				err := m.sendNotifications(ctx, *query, diff, result.FilteredOut)
-				if err != nil {
-					report.Errors = append(report.Errors, fmt.Sprintf("Notification error for '%s': %s", result.QueryName, err.Error()))
-					log.Printf("Failed to send notifications for query '%s': %v", result.QueryName, err)
-				} else {
-					report.Notifications++
-					if m.verbose {
-						log.Printf("Notifications sent for query '%s': %d new + %d updated", result.QueryName, newCount, updatedCount)
-					}
+	// Each notice is reported once, together with every query that matched it
+	groups := mergeQueryMatches(matches, m.notifyMgr.DefaultChannels())
+	documents := make(map[string][]samgov.Document)
+	for _, group := range groups {
+		newCount := len(group.Diff.New)
+		updatedCount := len(group.Diff.Updated)
+		report.NewOpps += newCount
+		report.UpdatedOpps += updatedCount
+
+		if m.verbose && len(group.Queries) > 1 {
+			log.Printf("%d new + %d updated opportunities matched by %d queries: %s",
+				newCount, updatedCount, len(group.Queries), strings.Join(group.Queries, ", "))
+		}
+
+		// Download documents before state is updated, while the previously
+		// stored documents are still available for deduplication
+		var groupDocuments map[string][]samgov.Document
+		if group.Query.Advanced.DownloadAttachments {
+			if m.dryRun {
+				if m.verbose {
+					log.Printf("[DRY RUN] Would download attachments for %d opportunities", newCount+updatedCount)
+				}
+			} else {
+				groupDocuments = m.downloadAttachments(ctx, group.Query, append(append([]samgov.Opportunity{}, group.Diff.New...), group.Diff.Updated...))
+				for noticeID, docs := range groupDocuments {
+					documents[noticeID] = docs
 				}
 			}
-		} else if m.dryRun && (newCount > 0 || updatedCount > 0) {
+		}
+
+		// Send notifications for new/updated opportunities
+		if m.dryRun {
 			if m.verbose {
 				log.Printf("[DRY RUN] Would send notifications for %d new + %d updated opportunities", newCount, updatedCount)
 			}
+			continue
+		}
+
+		if err := m.sendNotifications(ctx, group.Query, group.Diff, group.FilteredOut, groupDocuments, group.MatchedBy); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("Notification error for '%s': %s", group.Query.Name, err.Error()))
+			log.Printf("Failed to send notifications for query '%s': %v", group.Query.Name, err)
+		} else {
+			report.Notifications++
+			if m.verbose {
+				log.Printf("Notifications sent for query '%s': %d new + %d updated", group.Query.Name, newCount, updatedCount)
+			}
+		}
+	}
+
+	// Update state with all opportunities
+	for _, result := range results {
+		if result.Error != nil || result.Fallback {
+			continue
+		}
+		for _, opp := range result.Opportunities {
+			if awardNotices[opp.NoticeID] {
+				continue
+			}
+			m.state.AddOpportunity(opp)
+			m.state.AddDocuments(opp.NoticeID, documents[opp.NoticeID])
+			m.state.AddQueryMatch(opp.NoticeID, result.QueryName)
 		}
 	}
 
+	// Link award notices to the solicitations they award
+	awarded := m.recordAwards(ctx, awards, report)
+
+	// Close the issues of notices that have expired or been awarded
+	if !m.dryRun {
+		closed, err := m.closeExpiredIssues(ctx, results, awarded)
+		report.IssuesClosed = closed
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("closing issues: %s", err.Error()))
+		}
+	}
+
+	// Remind about tracked opportunities whose deadlines are approaching
+	reminders, err := m.sendReminders(ctx, time.Now(), report.StartTime)
+	report.Reminders = reminders
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("reminders: %s", err.Error()))
+	}
+
+	// Send queued digest notifications once the digest schedule is due
+	if !m.dryRun {
+		if err := m.sendDueDigest(ctx); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("digest: %s", err.Error()))
+			log.Printf("Failed to send digest: %v", err)
+		}
+	}
+
+	// Drop deliveries older than the outbox retention
+	if expired := m.outbox.Prune(time.Now()); expired > 0 {
+		log.Printf("Dropped %d undelivered notifications older than the outbox retention", expired)
+	}
+
 	// Update last run time
 	m.state.SetLastRun(time.Now())
 
@@ -189,118 +468,230 @@ func (m *Monitor) Run(ctx context.Context) error {
 			report.Errors = append(report.Errors, fmt.Sprintf("saving state: %s", err.Error()))
 			return fmt.Errorf("saving state: %w", err)
 		}
+
+		if err := m.writeCalendar(); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("writing calendar: %s", err.Error()))
+			log.Printf("Failed to write deadline calendar: %v", err)
+		}
 	}
 
 	return nil
 }
 
-// runQueries executes all enabled queries with rate limiting
-func (m *Monitor) runQueries(ctx context.Context) ([]samgov.QueryResult, error) {
-	enabledQueries := m.config.GetEnabledQueries()
-	results := make([]samgov.QueryResult, len(enabledQueries))
-	
-	// Sequential execution with rate limiting to avoid 429 errors
-	// SAM.gov API has strict rate limits, so we execute queries sequentially
-	// with a delay between each request
-	
+// sendDueDigest sends the queued digest notifications if the digest schedule
+// is due. Anything that fails to send stays queued.
+func (m *Monitor) sendDueDigest(ctx context.Context) error {
+	if !m.digest.DigestDue(time.Now()) {
+		return nil
+	}
+	log.Printf("Sending digest of %d queued notifications", m.digest.GetDigestStats().PendingCount)
+	return m.digest.ProcessPendingDigests(ctx)
+}
+
+// FlushDigest sends every queued digest notification now, regardless of the
+// digest schedule, and saves the remaining queue
+func (m *Monitor) FlushDigest(ctx context.Context) error {
+	pending := m.digest.GetDigestStats().PendingCount
+	if pending == 0 {
+		log.Printf("Digest queue is empty, nothing to send")
+		return nil
+	}
+
+	if m.dryRun {
+		log.Printf("[DRY RUN] Would send digest of %d queued notifications", pending)
+		return nil
+	}
+
+	log.Printf("Sending digest of %d queued notifications", pending)
+	sendErr := m.digest.ProcessPendingDigests(ctx)
+	if err := m.state.Save(); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	if sendErr != nil {
+		return fmt.Errorf("sending digest: %w", sendErr)
+	}
+	return nil
+}
+
+// downloadAttachments saves the resource files of the given opportunities,
+// skipping links already downloaded. It returns the new documents by notice ID.
+func (m *Monitor) downloadAttachments(ctx context.Context, query config.Query, opportunities []samgov.Opportunity) map[string][]samgov.Document {
+	documents := make(map[string][]samgov.Document)
+	if m.downloader == nil {
+		log.Printf("Query '%s': attachment downloads need a state file, skipping", query.Name)
+		return documents
+	}
+
+	remaining := query.Advanced.MaxAttachments
+	if remaining == 0 {
+		remaining = attachments.DefaultMaxDownloads
+	}
+
+	total := 0
+	for _, opp := range opportunities {
+		if remaining <= 0 || ctx.Err() != nil {
+			break
+		}
+		if len(opp.ResourceLinks) == 0 {
+			continue
+		}
+
+		var known []samgov.Document
+		if previous, exists := m.state.GetOpportunity(opp.NoticeID); exists {
+			known = previous.Documents
+		}
+
+		docs, err := m.downloader.Download(ctx, opp, known, remaining)
+		if err != nil {
+			log.Printf("Failed to save attachments for %s: %v", opp.NoticeID, err)
+		}
+		if len(docs) > 0 {
+			documents[opp.NoticeID] = docs
+			remaining -= len(docs)
+			total += len(docs)
+		}
+	}
+
+	if m.verbose {
+		log.Printf("Query '%s': downloaded %d attachments", query.Name, total)
+	}
+	return documents
+}
+
+// runQueries executes queries through the PartialFailureHandler, which
+// retries failed queries with recovery strategies
+func (m *Monitor) runQueries(ctx context.Context, enabledQueries []config.Query, report *RunReport) ([]samgov.QueryResult, error) {
+	results := make([]samgov.QueryResult, 0, len(enabledQueries))
+
+	// The client's rate limiter spaces requests out and enforces the
+	// per-minute and daily budgets
+
 	// Check daily request count
 	currentCount, _ := m.state.GetDailyRequestCount()
 	totalRequests := len(enabledQueries)
-	dailyLimit := 10 // Non-federal account limit
-	if os.Getenv("SAM_ACCOUNT_TYPE") == "federal" {
-		dailyLimit = 1000
-	}
-	
-	remainingRequests := dailyLimit - currentCount
+	dailyLimit := m.limiter.DailyLimit()
+
+	remainingRequests := m.limiter.DailyRemaining()
 	log.Printf("Daily API usage: %d/%d requests used (UTC day)", currentCount, dailyLimit)
-	log.Printf("Will make %d API requests (%d remaining after this run)", totalRequests, remainingRequests - totalRequests)
-	
+	log.Printf("Will make at least %d API requests (%d remaining after this run)", totalRequests, remainingRequests-totalRequests)
+
 	if totalRequests > remainingRequests {
 		log.Printf("WARNING: Request count would exceed daily limit! Consider reducing queries.")
-		if remainingRequests <= 0 {
+		// With a cache, repeated searches may still be answered without spending quota
+		if remainingRequests <= 0 && m.cache == nil {
 			return nil, fmt.Errorf("daily API limit already reached (%d/%d)", currentCount, dailyLimit)
 		}
 	}
-	
-	for i, query := range enabledQueries {
-		// Add delay between requests (except for the first one)
-		if i > 0 {
-			delay := 10 * time.Second // 10 seconds between requests
-			if m.verbose {
-				log.Printf("Waiting %v before next query to avoid rate limits", delay)
-			}
-			time.Sleep(delay)
+
+	// Retries may not spend the requests the remaining queries still need
+	m.limiter.ReserveForRun(totalRequests)
+	defer m.limiter.ReserveForRun(0)
+
+	recovered, err := m.recovery.ExecuteQueriesWithRecovery(ctx, enabledQueries, m.searcher, m.builder)
+	if err != nil {
+		// Too many failures to attempt recovery; still process what succeeded
+		log.Printf("WARNING: %v", err)
+		report.Errors = append(report.Errors, fmt.Sprintf("recovery: %s", err.Error()))
+	}
+
+	failed := 0
+	for _, r := range recovered {
+		result := m.buildQueryResult(ctx, r)
+		results = append(results, result)
+		m.state.UpdateQueryMetrics(result.QueryName, result.ExecutionTime, len(result.Opportunities), result.Error)
+		m.metrics.RecordQueryExecution(r.Query, result.ExecutionTime, result.Error == nil, result.Error, len(result.Opportunities))
+		if result.Error != nil {
+			failed++
 		}
-		
-		if m.verbose {
-			log.Printf("Starting query %d/%d: %s", i+1, totalRequests, query.Name)
-		}
-		
-		start := time.Now()
-		result := m.executeQuery(ctx, query)
-		result.ExecutionTime = time.Since(start)
-		
-		results[i] = result
-		
+
 		if m.verbose {
 			if result.Error != nil {
-				log.Printf("Query '%s' failed in %v: %s", query.Name, result.ExecutionTime, result.Error.Error())
+				log.Printf("Query '%s' failed in %v: %s", result.QueryName, result.ExecutionTime, result.Error.Error())
 			} else {
-				log.Printf("Query '%s' completed in %v: %d opportunities", query.Name, result.ExecutionTime, len(result.Opportunities))
+				log.Printf("Query '%s' completed in %v: %d opportunities", result.QueryName, result.ExecutionTime, len(result.Opportunities))
 			}
 		}
 	}
-	
+
+	if failed > 0 {
+		report.ErrorReport = m.recovery.GenerateErrorReport(recovered)
+	}
+
 	return results, nil
 }
 
-// executeQuery runs a single query
-func (m *Monitor) executeQuery(ctx context.Context, query config.Query) samgov.QueryResult {
+// buildQueryResult converts a recovery result into a QueryResult, applying
+// the query's advanced filters to the opportunities returned
+func (m *Monitor) buildQueryResult(ctx context.Context, r QueryResult) samgov.QueryResult {
+	query := r.Query
 	result := samgov.QueryResult{
 		QueryName:     query.Name,
 		Opportunities: make([]samgov.Opportunity, 0),
+		ExecutionTime: r.Duration,
 	}
 
-	// Build API parameters
-	params, err := m.builder.BuildParams(query)
-	if err != nil {
-		result.Error = fmt.Errorf("building parameters: %w", err)
+	if !r.Success {
+		result.Error = fmt.Errorf("API search: %w", r.Error)
 		return result
 	}
 
-	if m.verbose {
-		log.Printf("Query '%s' parameters: %v", query.Name, params)
-	}
+	// Update last successful query time
+	m.state.SetLastSuccessfulQuery(time.Now())
 
-	// Increment daily request counter
-	requestCount := m.state.IncrementDailyRequests()
-	if m.verbose {
-		log.Printf("Making API request %d for today (UTC)", requestCount)
-	}
-	
-	// Execute search
-	response, err := m.client.Search(ctx, params)
-	if err != nil {
-		result.Error = fmt.Errorf("API search: %w", err)
+	result.TotalRecords = r.TotalRecords
+	result.Pages = r.Pages
+	result.Truncated = r.Truncated
+	result.Fallback = r.Fallback
+	if r.Fallback {
+		// Fetching descriptions would spend quota on notices never notified
+		log.Printf("WARNING: Query '%s' failed; a broader recovery search found %d records, which are reported only",
+			query.Name, r.TotalRecords)
+		result.Opportunities = r.Opportunities
 		return result
 	}
-	
-	// Update last successful query time
-	m.state.SetLastSuccessfulQuery(time.Now())
-	
+	if r.Truncated {
+		log.Printf("WARNING: Query '%s' truncated at %d of %d records: %s",
+			query.Name, len(r.Opportunities), r.TotalRecords, r.TruncatedReason)
+	}
+
 	// Log response details
 	if m.verbose {
-		log.Printf("API Response: TotalRecords=%d, Returned=%d, Limit=%d, Offset=%d", 
-			response.TotalRecords, len(response.OpportunitiesData), response.Limit, response.Offset)
+		log.Printf("API Response: TotalRecords=%d, Returned=%d, Pages=%d, Retries=%d",
+			r.TotalRecords, len(r.Opportunities), r.Pages, r.RetryCount)
+	}
+
+	// Resolve description links so keyword filters see the full text
+	opportunities := r.Opportunities
+	if query.Advanced.FetchDescriptions && len(opportunities) > 0 {
+		maxFetches := query.Advanced.MaxDescriptions
+		if maxFetches == 0 {
+			maxFetches = samgov.DefaultMaxDescriptions
+		}
+		fetched, _ := m.descriptions.Enrich(ctx, opportunities, maxFetches)
+		if m.verbose {
+			log.Printf("Query '%s': fetched %d descriptions", query.Name, fetched)
+		}
 	}
 
 	// Apply advanced filtering if configured
-	opportunities := response.OpportunitiesData
 	var filteredOut []samgov.Opportunity
 	if len(opportunities) > 0 {
 		opportunities, filteredOut = m.applyAdvancedFilters(opportunities, query.Advanced)
 	}
 
+	// Rank the remaining matches and drop those below the minimum score
+	if query.Scoring.Enabled() && len(opportunities) > 0 {
+		var dropped []samgov.Opportunity
+		// Scores never raise an opportunity above the query's priority
+		scorer := scoring.New(query.Scoring)
+		scorer.SetQueryPriority(string(notificationPriority(query, nil)))
+		opportunities, dropped = scorer.Apply(opportunities)
+		filteredOut = append(filteredOut, dropped...)
+		if m.verbose {
+			log.Printf("Query '%s': scored %d opportunities, %d below minimum score", query.Name, len(opportunities)+len(dropped), len(dropped))
+		}
+	}
+
 	result.Opportunities = opportunities
 	result.FilteredOut = filteredOut
 	return result
@@ -308,19 +699,31 @@ func (m *Monitor) executeQuery(ctx context.Context, query config.Query) samgov.Q
 
 // applyAdvancedFilters applies client-side filtering and returns both accepted and filtered opportunities
 func (m *Monitor) applyAdvancedFilters(opportunities []samgov.Opportunity, advanced config.AdvancedQuery) (accepted []samgov.Opportunity, filteredOut []samgov.Opportunity) {
-	if len(advanced.Include) == 0 && len(advanced.Exclude) == 0 && advanced.MaxDaysOld == 0 {
+	if len(advanced.Include) == 0 && len(advanced.Exclude) == 0 && advanced.MaxDaysOld == 0 && advanced.Filter == "" {
 		return opportunities, nil // No filters configured
 	}
 
+	// The expression was checked when the config loaded, so a failure here
+	// only happens for configs built in code
+	var expr *filter.Expr
+	if advanced.Filter != "" {
+		compiled, err := filter.Compile(advanced.Filter)
+		if err != nil {
+			log.Printf("Warning: ignoring invalid filter expression: %v", err)
+		} else {
+			expr = compiled
+		}
+	}
+
 	accepted = make([]samgov.Opportunity, 0)
 	filteredOut = make([]samgov.Opportunity, 0)
 	
 	if m.verbose {
 		log.Printf("Applying advanced filters to %d opportunities", len(opportunities))
 	}
-	
+
 	for _, opp := range opportunities {
-		if m.matchesAdvancedCriteria(opp, advanced) {
+		if m.matchesAdvancedCriteria(opp, advanced, expr) {
 			accepted = append(accepted, opp)
 		} else {
 			filteredOut = append(filteredOut, opp)
@@ -329,7 +732,7 @@ func (m *Monitor) applyAdvancedFilters(opportunities []samgov.Opportunity, advan
 			}
 		}
 	}
-	
+
 	if m.verbose {
 		log.Printf("Advanced filtering: %d → %d opportunities (%d filtered out)", 
 			len(opportunities), len(accepted), len(filteredOut))
@@ -338,11 +741,12 @@ func (m *Monitor) applyAdvancedFilters(opportunities []samgov.Opportunity, advan
 	return accepted, filteredOut
 }
 
-// matchesAdvancedCriteria checks if opportunity matches advanced filters
-func (m *Monitor) matchesAdvancedCriteria(opp samgov.Opportunity, advanced config.AdvancedQuery) bool {
+// matchesAdvancedCriteria checks if opportunity matches advanced filters.
+// The filter expression, when set, must hold in addition to the list settings.
+func (m *Monitor) matchesAdvancedCriteria(opp samgov.Opportunity, advanced config.AdvancedQuery, expr *filter.Expr) bool {
 	// First check exclude keywords - if any match, reject immediately
 	for _, keyword := range advanced.Exclude {
-		if containsIgnoreCase(opp.Title, keyword) || containsIgnoreCase(opp.Description, keyword) {
+		if containsIgnoreCase(opp.Title, keyword) || containsIgnoreCase(opp.DescriptionBody(), keyword) {
 			if m.verbose {
 				log.Printf("  Excluded by keyword '%s': %s", keyword, opp.Title)
 			}
@@ -360,8 +764,8 @@ func (m *Monitor) matchesAdvancedCriteria(opp samgov.Opportunity, advanced confi
 			if isGenericTerm(keyword) && !hasRelevantContext(opp, keyword) {
 				continue
 			}
-			
-			if containsIgnoreCase(opp.Title, keyword) || containsIgnoreCase(opp.Description, keyword) {
+
+			if containsIgnoreCase(opp.Title, keyword) || containsIgnoreCase(opp.DescriptionBody(), keyword) {
 				found = true
 				matchedKeyword = keyword
 				break
@@ -415,10 +819,20 @@ func (m *Monitor) matchesAdvancedCriteria(opp samgov.Opportunity, advanced confi
 		}
 	}
 
+	// Check filter expression
+	if expr != nil && !expr.Match(opp) {
+		if m.verbose {
+			log.Printf("  Rejected by filter expression: %s", opp.Title)
+		}
+		return false
+	}
+
 	return true
 }
 
-// diffOpportunities compares current opportunities with state
+// diffOpportunities compares current opportunities with state. Amendments
+// of notices whose capture decision is final (won, lost or no-bid) are
+// recorded but not reported.
 func (m *Monitor) diffOpportunities(current []samgov.Opportunity) samgov.DiffResult {
 	diff := samgov.DiffResult{
 		New:      make([]samgov.Opportunity, 0),
@@ -428,7 +842,8 @@ func (m *Monitor) diffOpportunities(current []samgov.Opportunity) samgov.DiffRes
 
 	for _, opp := range current {
 		if previous, exists := m.state.GetOpportunity(opp.NoticeID); exists {
-			if m.hasOpportunityChanged(previous, opp) {
+			if changes := opportunityChanges(previous, opp); len(changes) > 0 && !previous.PipelineStage().Closed() {
+				opp.Changes = changes
 				diff.Updated = append(diff.Updated, opp)
 			} else {
 				diff.Existing = append(diff.Existing, opp)
@@ -441,42 +856,75 @@ func (m *Monitor) diffOpportunities(current []samgov.Opportunity) samgov.DiffRes
 	return diff
 }
 
-// hasOpportunityChanged determines if an opportunity has been modified
-func (m *Monitor) hasOpportunityChanged(previous samgov.OpportunityState, current samgov.Opportunity) bool {
-	// Simple comparison - in production you might want to hash the content
-	return previous.Title != current.Title ||
-		   (previous.Deadline == nil && current.ResponseDeadline != nil) ||
-		   (previous.Deadline != nil && current.ResponseDeadline == nil) ||
-		   (previous.Deadline != nil && current.ResponseDeadline != nil && *previous.Deadline != *current.ResponseDeadline)
-}
-
 // logReport prints the monitoring run report
 func (m *Monitor) logReport(report *RunReport) {
 	log.Printf("=== Monitoring Run Complete ===")
 	log.Printf("Duration: %v", report.Duration)
 	log.Printf("Queries: %d run, %d succeeded, %d failed", report.QueriesRun, report.QueriesSucceded, report.QueriesFailed)
+	if report.QueriesTruncated > 0 {
+		log.Printf("Truncated: %d queries stopped early because the daily request budget ran out", report.QueriesTruncated)
+	}
+	if report.QueriesFallback > 0 {
+		log.Printf("Fallback: %d queries failed and only a broader recovery search succeeded; their results were not notified", report.QueriesFallback)
+	}
 	log.Printf("Opportunities: %d total, %d new, %d updated", report.TotalOpps, report.NewOpps, report.UpdatedOpps)
-	
+
 	if report.Notifications > 0 {
 		log.Printf("Notifications: %d sent", report.Notifications)
 	}
-	
+	if report.Reminders > 0 {
+		log.Printf("Deadline reminders: %d sent", report.Reminders)
+	}
+	if report.Redelivered > 0 {
+		log.Printf("Redelivered: %d notifications that failed on earlier runs", report.Redelivered)
+	}
+	if report.IssuesClosed > 0 {
+		log.Printf("Issues closed: %d for expired or awarded opportunities", report.IssuesClosed)
+	}
+	if report.AwardsRecorded > 0 {
+		log.Printf("Awards: %d recorded, %d of tracked opportunities", report.AwardsRecorded, report.AwardsLinked)
+	}
+	if stats := m.outbox.Stats(); stats.Failed > 0 {
+		log.Printf("Outbox: %d failed deliveries awaiting retry or replay", stats.Failed)
+	}
+
+	if report.CacheHits > 0 || report.CacheMisses > 0 {
+		log.Printf("Cache: %d hits, %d misses", report.CacheHits, report.CacheMisses)
+	}
+
+	if report.RetryStats.TotalRetries > 0 || report.RetryStats.FailedRequests > 0 {
+		log.Printf("Retries: %d across %d searches (%d failed)",
+			report.RetryStats.TotalRetries, report.RetryStats.TotalRequests, report.RetryStats.FailedRequests)
+	}
+
+	if report.CircuitBreakerState != "" && report.CircuitBreakerState != "closed" {
+		log.Printf("Circuit breaker: %s", report.CircuitBreakerState)
+	}
+
 	if len(report.Errors) > 0 {
 		log.Printf("Errors: %d", len(report.Errors))
 		for _, err := range report.Errors {
 			log.Printf("  - %s", err)
 		}
 	}
-	
+
 	if m.verbose {
 		log.Printf("Query execution times:")
 		for _, result := range report.QueryResults {
 			status := "✓"
 			if result.Error != nil {
 				status = "✗"
+			} else if result.Fallback {
+				status = "~"
+			} else if result.Truncated {
+				status = "…"
 			}
 			log.Printf("  %s %s: %v (%d opportunities)", status, result.QueryName, result.ExecutionTime, len(result.Opportunities))
 		}
+
+		if report.ErrorReport != "" {
+			log.Printf("%s", report.ErrorReport)
+		}
 	}
 }
 
@@ -560,9 +1008,9 @@ func hasRelevantContext(opp samgov.Opportunity, genericTerm string) bool {
 		"counter", "drone", "UAS", "maritime", "border", "defense",
 		"military", "tactical", "strategic", "sensor fusion",
 	}
-	
-	text := strings.ToLower(opp.Title + " " + opp.Description)
-	
+
+	text := strings.ToLower(opp.Title + " " + opp.DescriptionBody())
+
 	// Check if any context keyword is present
 	for _, context := range contextKeywords {
 		if strings.Contains(text, context) {
@@ -613,6 +1061,12 @@ func buildNotificationConfig() notify.NotificationConfig {
 		IconEmoji:  os.Getenv("SLACK_ICON_EMOJI"),
 	}
 
+	// Teams configuration
+	config.Teams = notify.TeamsConfig{
+		Enabled:    os.Getenv("TEAMS_WEBHOOK") != "",
+		WebhookURL: os.Getenv("TEAMS_WEBHOOK"),
+	}
+
 	// GitHub configuration - only enable if all required fields are set
 	githubToken := os.Getenv("GITHUB_TOKEN")
 	githubOwner := os.Getenv("GITHUB_OWNER")
@@ -637,7 +1091,7 @@ func buildNotificationConfig() notify.NotificationConfig {
 	} else {
 		log.Printf("GitHub notifications DISABLED: missing environment variables")
 	}
-	
+
 	config.GitHub = notify.GitHubConfig{
 		Enabled:     githubEnabled,
 		Token:       githubToken,
@@ -645,23 +1099,137 @@ func buildNotificationConfig() notify.NotificationConfig {
 		Repository:  githubRepo,
 		Labels:      getEnvStringSlice("GITHUB_LABELS"),
 		AssignUsers: getEnvStringSlice("GITHUB_ASSIGN_USERS"),
+		APIURL:      os.Getenv("GITHUB_API_URL"), // Set by GitHub Actions, including on Enterprise Server
+	}
+
+	// Webhook configuration
+	config.Webhook = notify.WebhookConfig{
+		Enabled:    os.Getenv("WEBHOOK_URL") != "",
+		URL:        os.Getenv("WEBHOOK_URL"),
+		Secret:     os.Getenv("WEBHOOK_SECRET"),
+		Headers:    getEnvHeaders("WEBHOOK_HEADERS"),
+		MaxRetries: getEnvInt("WEBHOOK_MAX_RETRIES", 0),
 	}
 
 	return config
 }
 
+// warnUnreachableChannels logs every configured notifier that no enabled
+// query sends to. Queries without channels reach every default notifier;
+// queries that list channels reach only those.
+func warnUnreachableChannels(notifyMgr *notify.NotificationManager, cfg *config.Config) {
+	reachable := make(map[string]bool)
+	for _, query := range cfg.GetEnabledQueries() {
+		if len(query.Notification.Channels) == 0 {
+			for _, channel := range notifyMgr.DefaultChannels() {
+				reachable[channel] = true
+			}
+			continue
+		}
+		for _, channel := range query.Notification.Channels {
+			reachable[channel] = true
+		}
+	}
+
+	for _, channel := range notifyMgr.DefaultChannels() {
+		if !reachable[channel] {
+			log.Printf("Warning: %s notifications are configured but no enabled query lists the '%s' channel", channel, channel)
+		}
+	}
+	names := make([]string, 0, len(cfg.Notifications.Channels))
+	for name, channel := range cfg.Notifications.Channels {
+		if channel.Validate() == nil && !reachable[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		log.Printf("Warning: notification channel '%s' is configured but no enabled query lists it", name)
+	}
+}
+
+// registerNamedChannels adds the channels from the notifications block of the
+// config. Channels that fail validation are only reachable by disabled queries
+// (Load rejects the rest) and are skipped.
+func registerNamedChannels(notifyMgr *notify.NotificationManager, cfg *config.Config, verbose bool) {
+	for name, channel := range cfg.Notifications.Channels {
+		if err := channel.Validate(); err != nil {
+			if verbose {
+				log.Printf("Skipping notification channel '%s': %v", name, err)
+			}
+			continue
+		}
+
+		switch channel.Type {
+		case config.ChannelTypeEmail:
+			port := channel.SMTPPort
+			if port == 0 {
+				port = 587
+			}
+			useTLS := true
+			if channel.UseTLS != nil {
+				useTLS = *channel.UseTLS
+			}
+			notifyMgr.RegisterChannel(name, notify.NewEmailNotifier(notify.EmailConfig{
+				Enabled:     true,
+				SMTPHost:    channel.SMTPHost,
+				SMTPPort:    port,
+				Username:    channel.Username,
+				Password:    channel.Password,
+				FromAddress: channel.From,
+				ToAddresses: channel.To,
+				UseTLS:      useTLS,
+				Template:    channel.Template,
+			}, verbose))
+		case config.ChannelTypeSlack:
+			notifyMgr.RegisterChannel(name, notify.NewSlackNotifier(notify.SlackConfig{
+				Enabled:    true,
+				WebhookURL: channel.WebhookURL,
+				Channel:    channel.Channel,
+				Username:   channel.Username,
+				IconEmoji:  channel.IconEmoji,
+				Template:   channel.Template,
+			}, verbose))
+		case config.ChannelTypeTeams:
+			notifyMgr.RegisterChannel(name, notify.NewTeamsNotifier(notify.TeamsConfig{
+				Enabled:    true,
+				WebhookURL: channel.WebhookURL,
+			}, verbose))
+		case config.ChannelTypeGitHub:
+			notifyMgr.RegisterChannel(name, notify.NewGitHubNotifier(notify.GitHubConfig{
+				Enabled:     true,
+				Token:       channel.Token,
+				Owner:       channel.Owner,
+				Repository:  channel.Repository,
+				Labels:      channel.Labels,
+				AssignUsers: channel.AssignUsers,
+				APIURL:      channel.APIURL,
+				Template:    channel.Template,
+			}, verbose))
+		case config.ChannelTypeWebhook:
+			notifyMgr.RegisterChannel(name, notify.NewWebhookNotifier(notify.WebhookConfig{
+				Enabled:    true,
+				URL:        channel.URL,
+				Secret:     channel.Secret,
+				Headers:    channel.Headers,
+				MaxRetries: channel.MaxRetries,
+			}, verbose))
+		}
+	}
+}
+
 // sendNotifications sends notifications for opportunities
-func (m *Monitor) sendNotifications(ctx context.Context, query config.Query, diff samgov.DiffResult, filteredOut []samgov.Opportunity) error {
+func (m *Monitor) sendNotifications(ctx context.Context, query config.Query, diff samgov.DiffResult, filteredOut []samgov.Opportunity, documents map[string][]samgov.Document, matchedBy map[string][]string) error {
 	// Send notifications for new opportunities
 	if len(diff.New) > 0 {
-		if err := m.sendNewOpportunityNotifications(ctx, query, diff.New, filteredOut); err != nil {
+		if err := m.sendNewOpportunityNotifications(ctx, query, diff.New, filteredOut, documents, matchedBy); err != nil {
 			return fmt.Errorf("sending new opportunity notifications: %w", err)
 		}
 	}
 
 	// Send notifications for updated opportunities
 	if len(diff.Updated) > 0 {
-		if err := m.sendUpdatedOpportunityNotifications(ctx, query, diff.Updated); err != nil {
+		if err := m.sendUpdatedOpportunityNotifications(ctx, query, diff.Updated, documents, matchedBy); err != nil {
 			return fmt.Errorf("sending updated opportunity notifications: %w", err)
 		}
 	}
@@ -670,11 +1238,8 @@ func (m *Monitor) sendNotifications(ctx context.Context, query config.Query, dif
 }
 
 // sendNewOpportunityNotifications sends notifications for new opportunities
-func (m *Monitor) sendNewOpportunityNotifications(ctx context.Context, query config.Query, opportunities []samgov.Opportunity, filteredOut []samgov.Opportunity) error {
-	priority := notify.Priority(query.Notification.Priority)
-	if priority == "" {
-		priority = notify.PriorityMedium
-	}
+func (m *Monitor) sendNewOpportunityNotifications(ctx context.Context, query config.Query, opportunities []samgov.Opportunity, filteredOut []samgov.Opportunity, documents map[string][]samgov.Document, matchedBy map[string][]string) error {
+	priority := notificationPriority(query, opportunities)
 
 	subject := fmt.Sprintf("🚨 %d New SAM.gov Opportunities - %s", len(opportunities), query.Name)
 
@@ -682,10 +1247,13 @@ func (m *Monitor) sendNewOpportunityNotifications(ctx context.Context, query con
 	builder := notify.NewNotificationBuilder().
 		WithQuery(query.Name, priority).
 		WithRecipients(query.Notification.Recipients).
+		WithChannels(query.Notification.Channels).
+		WithTemplate(query.Notification.Template).
 		WithOpportunities(opportunities).
+		WithMatchedBy(matchedBy).
 		WithSubject(subject).
 		WithMetadata("query_type", "new")
-	
+
 	// Add filtered opportunities if any
 	if len(filteredOut) > 0 {
 		builder = builder.WithFilteredOpportunities(filteredOut)
@@ -699,16 +1267,14 @@ func (m *Monitor) sendNewOpportunityNotifications(ctx context.Context, query con
 		attachment := calGen.CreateCalendarAttachment(opportunities, query.Name)
 		notification.Attachments = []notify.Attachment{attachment}
 	}
+	notification.Attachments = append(notification.Attachments, m.documentAttachments(query, opportunities, documents)...)
 
-	return m.notifyMgr.SendNotification(ctx, notification)
+	return m.deliver(ctx, query, notification)
 }
 
 // sendUpdatedOpportunityNotifications sends notifications for updated opportunities
-func (m *Monitor) sendUpdatedOpportunityNotifications(ctx context.Context, query config.Query, opportunities []samgov.Opportunity) error {
-	priority := notify.Priority(query.Notification.Priority)
-	if priority == "" {
-		priority = notify.PriorityMedium
-	}
+func (m *Monitor) sendUpdatedOpportunityNotifications(ctx context.Context, query config.Query, opportunities []samgov.Opportunity, documents map[string][]samgov.Document, matchedBy map[string][]string) error {
+	priority := notificationPriority(query, opportunities)
 
 	subject := fmt.Sprintf("🔄 %d Updated SAM.gov Opportunities - %s", len(opportunities), query.Name)
 
@@ -716,12 +1282,65 @@ func (m *Monitor) sendUpdatedOpportunityNotifications(ctx context.Context, query
 	notification := notify.NewNotificationBuilder().
 		WithQuery(query.Name, priority).
 		WithRecipients(query.Notification.Recipients).
+		WithChannels(query.Notification.Channels).
+		WithTemplate(query.Notification.Template).
 		WithUpdatedOpportunities(opportunities).
+		WithMatchedBy(matchedBy).
 		WithSubject(subject).
 		WithMetadata("query_type", "updated").
 		Build()
 
-	return m.notifyMgr.SendNotification(ctx, notification)
+	// Amendments often move deadlines; attach the current ones
+	if m.hasDeadlines(opportunities) {
+		calGen := notify.NewCalendarGenerator(m.verbose)
+		notification.Attachments = []notify.Attachment{calGen.CreateCalendarAttachment(opportunities, query.Name)}
+	}
+	notification.Attachments = append(notification.Attachments, m.documentAttachments(query, opportunities, documents)...)
+
+	return m.deliver(ctx, query, notification)
+}
+
+// deliver sends a notification now, or queues it for the next digest when
+// the query uses digest mode. High-priority notifications, deadlines within
+// three days and batches of ten or more are never held back.
+func (m *Monitor) deliver(ctx context.Context, query config.Query, notification notify.Notification) error {
+	if !query.Notification.Digest {
+		return m.notifyMgr.SendNotification(ctx, notification)
+	}
+
+	if err := m.digest.QueueNotification(ctx, notification); err != nil {
+		return err
+	}
+	if m.verbose {
+		log.Printf("Query '%s': notification queued for digest (%d pending)", query.Name, m.digest.GetDigestStats().PendingCount)
+	}
+	return nil
+}
+
+// notificationPriority uses the most urgent per-opportunity priority when the
+// query scores its matches, and the query's configured priority otherwise
+func notificationPriority(query config.Query, opportunities []samgov.Opportunity) notify.Priority {
+	if scored := scoring.HighestPriority(opportunities); scored != "" {
+		return notify.Priority(scored)
+	}
+	if query.Notification.Priority != "" {
+		return notify.Priority(query.Notification.Priority)
+	}
+	return notify.PriorityMedium
+}
+
+// documentAttachments loads the documents downloaded this run for the given
+// opportunities, if the query attaches them to emails
+func (m *Monitor) documentAttachments(query config.Query, opportunities []samgov.Opportunity, documents map[string][]samgov.Document) []notify.Attachment {
+	if !query.Advanced.AttachDocuments || len(documents) == 0 {
+		return nil
+	}
+
+	docs := make([]samgov.Document, 0)
+	for _, opp := range opportunities {
+		docs = append(docs, documents[opp.NoticeID]...)
+	}
+	return attachments.EmailAttachments(docs)
 }
 
 // sendDebugEmail sends a test email to verify email configuration
@@ -767,10 +1386,14 @@ func (m *Monitor) findQueryByName(name string) *config.Query {
 	return nil
 }
 
-// hasDeadlines checks if any opportunities have response deadlines
+// hasDeadlines checks if any opportunities have upcoming response deadlines
 func (m *Monitor) hasDeadlines(opportunities []samgov.Opportunity) bool {
+	now := time.Now()
 	for _, opp := range opportunities {
-		if opp.ResponseDeadline != nil && *opp.ResponseDeadline != "" {
+		if opp.ResponseDeadline == nil {
+			continue
+		}
+		if deadline, ok := samgov.ParseDate(*opp.ResponseDeadline); ok && deadline.After(now) {
 			return true
 		}
 	}
@@ -817,6 +1440,24 @@ func getEnvStringSlice(key string) []string {
 	return nil
 }
 
+// getEnvHeaders parses "Name: value" pairs separated by commas
+func getEnvHeaders(key string) map[string]string {
+	pairs := getEnvStringSlice(key)
+	if len(pairs) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			log.Printf("Ignoring malformed header in %s: %q", key, pair)
+			continue
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
 // Simple integer parsing to avoid importing strconv
 func parseInt(s string) (int, error) {
 	result := 0
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+)
+
+// retryDeliveries resends the deliveries that failed on earlier runs and
+// still have attempts left under the outbox policy
+func (m *Monitor) retryDeliveries(ctx context.Context) (int, error) {
+	retryable := m.outbox.Retryable()
+	if len(retryable) == 0 {
+		return 0, nil
+	}
+
+	log.Printf("Retrying %d failed notification deliveries", len(retryable))
+	sent, err := m.notifyMgr.RetryDeliveries(ctx, retryable)
+	if err != nil {
+		log.Printf("Redelivered %d of %d notifications: %v", sent, len(retryable), err)
+	}
+	return sent, err
+}
+
+// ListDeliveries returns the outbox deliveries with the given status, or all
+// of them when status is empty
+func (m *Monitor) ListDeliveries(status notify.DeliveryStatus) []notify.Delivery {
+	return m.outbox.List(status)
+}
+
+// ReplayDeliveries resends the given failed deliveries, or every failed
+// delivery when ids is empty, regardless of how often they were tried, and
+// saves the results
+func (m *Monitor) ReplayDeliveries(ctx context.Context, ids []string) error {
+	var deliveries []notify.Delivery
+	if len(ids) == 0 {
+		deliveries = m.outbox.List(notify.DeliveryFailed)
+	} else {
+		for _, id := range ids {
+			delivery, ok := m.outbox.Get(id)
+			if !ok {
+				return fmt.Errorf("no delivery with ID '%s'", id)
+			}
+			if delivery.Status == notify.DeliverySent {
+				return fmt.Errorf("delivery '%s' was already sent", id)
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	if len(deliveries) == 0 {
+		log.Printf("No failed deliveries to replay")
+		return nil
+	}
+
+	if m.dryRun {
+		for _, delivery := range deliveries {
+			log.Printf("[DRY RUN] Would replay delivery %s to %s: %s", delivery.ID, delivery.Channel, delivery.Subject)
+		}
+		return nil
+	}
+
+	log.Printf("Replaying %d failed deliveries", len(deliveries))
+	sent, sendErr := m.notifyMgr.RetryDeliveries(ctx, deliveries)
+	log.Printf("Replayed %d of %d deliveries", sent, len(deliveries))
+
+	if err := m.state.Save(); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	if sendErr != nil {
+		return fmt.Errorf("replaying deliveries: %w", sendErr)
+	}
+	return nil
+}
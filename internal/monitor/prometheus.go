@@ -0,0 +1,232 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metricsPrefix starts the name of every exported metric
+const metricsPrefix = "samgov_monitor_"
+
+// prometheusContentType is the media type of the Prometheus text format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes the metrics in the Prometheus text exposition
+// format. Counters add up over every run recorded in the metrics file.
+func (mc *MetricsCollector) WritePrometheus(w io.Writer) error {
+	metrics := mc.GetMetrics()
+	p := &promWriter{}
+
+	p.family("runs_total", "counter", "Monitoring runs started.")
+	p.sample("runs_total", nil, float64(metrics.TotalRuns))
+	if !metrics.LastRunTime.IsZero() {
+		p.family("last_run_timestamp_seconds", "gauge", "Unix time the last monitoring run finished.")
+		p.sample("last_run_timestamp_seconds", nil, float64(metrics.LastRunTime.Unix()))
+	}
+	p.histogram("run_duration_seconds", "Duration of monitoring runs.", metrics.RunDuration)
+
+	p.family("api_requests_total", "counter", "SAM.gov API requests, including retries, by result.")
+	p.sample("api_requests_total", []string{"result", "success"}, float64(metrics.SuccessfulRequests))
+	p.sample("api_requests_total", []string{"result", "failure"}, float64(metrics.FailedRequests))
+	p.histogram("api_request_duration_seconds", "Duration of SAM.gov API requests.", metrics.ResponseTime)
+	p.family("api_retries_total", "counter", "SAM.gov API search attempts that were retries.")
+	p.sample("api_retries_total", nil, float64(metrics.TotalRetries))
+	p.family("api_errors_total", "counter", "Failed SAM.gov API search attempts by reason.")
+	for _, reason := range sortedKeys(metrics.RetryReasons) {
+		p.sample("api_errors_total", []string{"reason", reason}, float64(metrics.RetryReasons[reason]))
+	}
+	if metrics.QuotaLimit > 0 {
+		p.family("api_quota_limit", "gauge", "Daily SAM.gov API request quota.")
+		p.sample("api_quota_limit", nil, float64(metrics.QuotaLimit))
+		p.family("api_quota_remaining", "gauge", "SAM.gov API requests left for the UTC day as of the last run.")
+		p.sample("api_quota_remaining", nil, float64(metrics.QuotaRemaining))
+	}
+
+	queries := make([]string, 0, len(metrics.QueryMetrics))
+	for name := range metrics.QueryMetrics {
+		queries = append(queries, name)
+	}
+	sort.Strings(queries)
+
+	p.family("query_executions_total", "counter", "Query executions by result.")
+	for _, name := range queries {
+		qm := metrics.QueryMetrics[name]
+		p.sample("query_executions_total", []string{"query", name, "result", "success"}, float64(qm.SuccessCount))
+		p.sample("query_executions_total", []string{"query", name, "result", "failure"}, float64(qm.FailureCount))
+	}
+	p.family("query_opportunities_total", "counter", "Opportunities returned by each query.")
+	for _, name := range queries {
+		p.sample("query_opportunities_total", []string{"query", name}, float64(metrics.QueryMetrics[name].TotalOpportunities))
+	}
+	p.family("query_new_opportunities_total", "counter", "New opportunities found by each query.")
+	for _, name := range queries {
+		p.sample("query_new_opportunities_total", []string{"query", name}, float64(metrics.QueryMetrics[name].NewOpportunities))
+	}
+	p.family("query_updated_opportunities_total", "counter", "Updated opportunities found by each query.")
+	for _, name := range queries {
+		p.sample("query_updated_opportunities_total", []string{"query", name}, float64(metrics.QueryMetrics[name].UpdatedOpportunities))
+	}
+
+	p.family("notifications_sent_total", "counter", "Notifications delivered by channel.")
+	for _, channel := range sortedKeys(metrics.NotificationsByType) {
+		p.sample("notifications_sent_total", []string{"channel", channel}, float64(metrics.NotificationsByType[channel]))
+	}
+	p.family("notification_failures_total", "counter", "Failed notification deliveries by channel.")
+	for _, channel := range sortedKeys(metrics.NotificationErrorsByType) {
+		p.sample("notification_failures_total", []string{"channel", channel}, float64(metrics.NotificationErrorsByType[channel]))
+	}
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// WriteTextfile writes the metrics in the Prometheus text format to path,
+// for the node_exporter textfile collector or a push to a Pushgateway. It is
+// written to a temporary file and renamed so a scrape never sees half of it.
+func (mc *MetricsCollector) WriteTextfile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating metrics directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := mc.WritePrometheus(&buf); err != nil {
+		return err
+	}
+
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing temp metrics file: %w", err)
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("renaming metrics file: %w", err)
+	}
+	return nil
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (mc *MetricsCollector) Handler() http.Handler {
+	return metricsHandler(func() *MetricsCollector { return mc })
+}
+
+// MetricsFileHandler serves the metrics saved in a metrics file, read again
+// on every scrape, so it can run alongside a monitor process updating it
+func MetricsFileHandler(path string) http.Handler {
+	return metricsHandler(func() *MetricsCollector { return NewMetricsCollector(path, false) })
+}
+
+// metricsHandler serves the metrics of the collector returned by collector
+func metricsHandler(collector func() *MetricsCollector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", prometheusContentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		if r.Method == http.MethodHead {
+			return
+		}
+		collector().WritePrometheus(w)
+	})
+}
+
+// promWriter builds a Prometheus text exposition
+type promWriter struct {
+	buf bytes.Buffer
+}
+
+// family writes the HELP and TYPE lines of a metric
+func (p *promWriter) family(name, kind, help string) {
+	fmt.Fprintf(&p.buf, "# HELP %s%s %s\n", metricsPrefix, name, help)
+	fmt.Fprintf(&p.buf, "# TYPE %s%s %s\n", metricsPrefix, name, kind)
+}
+
+// sample writes one sample; labels are name, value pairs
+func (p *promWriter) sample(name string, labels []string, value float64) {
+	p.buf.WriteString(metricsPrefix + name)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], escapeLabelValue(labels[i+1])))
+		}
+		p.buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	p.buf.WriteString(" " + formatMetricValue(value) + "\n")
+}
+
+// histogram writes a histogram's buckets, sum and count
+func (p *promWriter) histogram(name, help string, h *Histogram) {
+	if h == nil {
+		return
+	}
+	p.family(name, "histogram", help)
+	for i, bound := range h.Buckets {
+		p.sample(name+"_bucket", []string{"le", formatMetricValue(bound)}, float64(h.Counts[i]))
+	}
+	p.sample(name+"_bucket", []string{"le", "+Inf"}, float64(h.Count))
+	p.sample(name+"_sum", nil, h.Sum)
+	p.sample(name+"_count", nil, float64(h.Count))
+}
+
+// escapeLabelValue escapes backslashes, quotes and newlines in a label value
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatMetricValue formats a sample value as briefly as possible
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of counts in order
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordRunMetrics records the outcome of a run, then saves the metrics file
+// and rewrites the Prometheus text file unless this is a dry run
+func (m *Monitor) recordRunMetrics(report *RunReport, start time.Time) {
+	m.metrics.RecordRetries(report.RetryStats.TotalRetries, report.RetryStats.RetryReasons)
+	m.metrics.RecordQuota(m.limiter.DailyRemaining(), m.limiter.DailyLimit())
+	m.metrics.RecordOpportunities(report.TotalOpps, report.NewOpps, report.UpdatedOpps)
+	m.metrics.RecordRunEnd(start)
+
+	if m.dryRun {
+		return
+	}
+
+	if m.metricsFile != "" {
+		if err := m.metrics.SaveMetrics(); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("saving metrics: %s", err.Error()))
+			log.Printf("Failed to save metrics: %v", err)
+		}
+	}
+	if m.metricsTextfile != "" {
+		if err := m.metrics.WriteTextfile(m.metricsTextfile); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("writing metrics textfile: %s", err.Error()))
+			log.Printf("Failed to write metrics textfile: %v", err)
+		}
+	}
+}
+
+// MetricsHandler serves the monitor's metrics in the Prometheus text format
+func (m *Monitor) MetricsHandler() http.Handler {
+	return m.metrics.Handler()
+}
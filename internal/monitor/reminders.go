@@ -0,0 +1,212 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// dueReminder is the reminder owed for one tracked opportunity
+type dueReminder struct {
+	Opportunity samgov.Opportunity
+	Offset      time.Duration // Most urgent offset passed, used for the message
+	Queries     []string      // Queries with an unsent reminder for the notice
+	Keys        []string      // Every passed offset, recorded as sent together
+}
+
+// reminderKey identifies a reminder sent for one query, so a query added
+// later still gets its own. The deadline is part of the key so an extended
+// deadline gets its reminders again.
+func reminderKey(query string, offset time.Duration, deadline string) string {
+	return fmt.Sprintf("%s|%s@%s", query, offset, deadline)
+}
+
+// sendReminders notifies queries about tracked opportunities whose deadline
+// is now within one of the query's reminder offsets. Each offset is sent
+// once per notice and query; when several have passed since the last run only the
+// most urgent is sent. Notices first seen at or after since were just
+// reported with their deadline, so their passed reminders are recorded
+// without sending.
+func (m *Monitor) sendReminders(ctx context.Context, now, since time.Time) (int, error) {
+	offsets := make(map[string][]time.Duration)
+	queries := make(map[string]config.Query)
+	for _, query := range m.config.GetEnabledQueries() {
+		parsed, err := config.ParseReminders(query.Notification.Reminders)
+		if err != nil || len(parsed) == 0 {
+			continue
+		}
+		offsets[query.Name] = parsed
+		queries[query.Name] = query
+	}
+	if len(offsets) == 0 {
+		return 0, nil
+	}
+
+	byOffset := make(map[time.Duration][]dueReminder)
+	for _, tracked := range m.state.ListOpportunities() {
+		due, ok := m.dueReminder(tracked.NoticeID, offsets, now)
+		if !ok {
+			continue
+		}
+		if !tracked.FirstSeen.Before(since) {
+			m.state.MarkRemindersSent(tracked.NoticeID, due.Keys)
+			continue
+		}
+		byOffset[due.Offset] = append(byOffset[due.Offset], due)
+	}
+
+	// Most urgent reminders first
+	order := make([]time.Duration, 0, len(byOffset))
+	for offset := range byOffset {
+		order = append(order, offset)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+
+	sent := 0
+	var firstErr error
+	for _, offset := range order {
+		reminders := byOffset[offset]
+		sort.SliceStable(reminders, func(i, j int) bool {
+			return *reminders[i].Opportunity.ResponseDeadline < *reminders[j].Opportunity.ResponseDeadline
+		})
+
+		keys := make(map[string][]string)
+		matches := make([]queryMatch, 0)
+		index := make(map[string]int)
+		for _, due := range reminders {
+			keys[due.Opportunity.NoticeID] = due.Keys
+			for _, name := range due.Queries {
+				i, ok := index[name]
+				if !ok {
+					matches = append(matches, queryMatch{Query: queries[name]})
+					i = len(matches) - 1
+					index[name] = i
+				}
+				matches[i].Diff.New = append(matches[i].Diff.New, due.Opportunity)
+			}
+		}
+
+		// A notice due for several queries is reminded once, naming all of them
+		for _, group := range mergeQueryMatches(matches, m.notifyMgr.DefaultChannels()) {
+			if m.dryRun {
+				log.Printf("[DRY RUN] Would send %s deadline reminder for %d opportunities (%s)",
+					config.FormatReminder(offset), len(group.Diff.New), group.Query.Name)
+				continue
+			}
+
+			if err := m.sendReminderNotification(ctx, group, offset); err != nil {
+				log.Printf("Failed to send deadline reminder for query '%s': %v", group.Query.Name, err)
+				if firstErr == nil {
+					firstErr = fmt.Errorf("sending deadline reminder for '%s': %w", group.Query.Name, err)
+				}
+				// Reminders in the outbox are resent from there, not re-reminded
+				if !notify.IsQueuedForRetry(err) {
+					continue
+				}
+			}
+
+			for _, opp := range group.Diff.New {
+				m.state.MarkRemindersSent(opp.NoticeID, keys[opp.NoticeID])
+			}
+			sent++
+		}
+	}
+
+	return sent, firstErr
+}
+
+// dueReminder works out which reminder, if any, is owed for a tracked notice
+func (m *Monitor) dueReminder(noticeID string, offsets map[string][]time.Duration, now time.Time) (dueReminder, bool) {
+	// Versions are needed to describe the notice; list results may omit them
+	tracked, ok := m.state.GetOpportunity(noticeID)
+	if !ok || tracked.Deadline == nil {
+		return dueReminder{}, false
+	}
+	// Nothing is left to respond to once a bid is submitted or declined
+	if stage := tracked.PipelineStage(); stage == samgov.StageSubmitted || stage.Closed() {
+		return dueReminder{}, false
+	}
+	deadline, ok := samgov.ParseDate(*tracked.Deadline)
+	if !ok || !deadline.After(now) {
+		return dueReminder{}, false
+	}
+	remaining := deadline.Sub(now)
+
+	due := dueReminder{}
+	for _, name := range tracked.Queries {
+		owed := false
+		for _, offset := range offsets[name] {
+			if remaining > offset {
+				continue
+			}
+			key := reminderKey(name, offset, *tracked.Deadline)
+			due.Keys = appendUnique(due.Keys, key)
+			if containsString(tracked.RemindersSent, key) {
+				continue
+			}
+			owed = true
+			if due.Offset == 0 || offset < due.Offset {
+				due.Offset = offset
+			}
+		}
+		if owed {
+			due.Queries = append(due.Queries, name)
+		}
+	}
+	if len(due.Queries) == 0 {
+		return dueReminder{}, false
+	}
+
+	due.Opportunity = trackedOpportunity(tracked)
+	return due, true
+}
+
+// trackedOpportunity rebuilds the notice as last seen from its stored state
+func trackedOpportunity(tracked samgov.OpportunityState) samgov.Opportunity {
+	opp := samgov.Opportunity{
+		NoticeID:         tracked.NoticeID,
+		Title:            tracked.Title,
+		ResponseDeadline: tracked.Deadline,
+		UILink:           fmt.Sprintf("https://sam.gov/opp/%s/view", tracked.NoticeID),
+		ResourceLinks:    tracked.ResourceLinks,
+	}
+	if latest, ok := tracked.LatestVersion(); ok {
+		opp.SolicitationNum = latest.Snapshot.SolicitationNumber
+		opp.Type = latest.Snapshot.Type
+		opp.PostedDate = latest.Snapshot.PostedDate
+		opp.TypeOfSetAside = latest.Snapshot.SetAside
+		opp.NAICSCode = latest.Snapshot.NAICSCode
+		opp.FullParentPath = latest.Snapshot.Organization
+	}
+	return opp
+}
+
+// sendReminderNotification sends one deadline reminder with a calendar of
+// the deadlines attached
+func (m *Monitor) sendReminderNotification(ctx context.Context, group matchGroup, offset time.Duration) error {
+	opportunities := group.Diff.New
+	dueIn := config.FormatReminder(offset)
+	subject := fmt.Sprintf("⏰ %d SAM.gov Deadlines Within %s - %s", len(opportunities), dueIn, group.Query.Name)
+
+	notification := notify.NewNotificationBuilder().
+		WithQuery(group.Query.Name, notificationPriority(group.Query, opportunities)).
+		WithRecipients(group.Query.Notification.Recipients).
+		WithChannels(group.Query.Notification.Channels).
+		WithTemplate(group.Query.Notification.Template).
+		WithReminder(dueIn, opportunities).
+		WithMatchedBy(group.MatchedBy).
+		WithSubject(subject).
+		WithMetadata("query_type", "reminder").
+		Build()
+
+	calGen := notify.NewCalendarGenerator(m.verbose)
+	notification.Attachments = []notify.Attachment{calGen.CreateCalendarAttachment(opportunities, group.Query.Name)}
+
+	return m.notifyMgr.SendNotification(ctx, notification)
+}
@@ -0,0 +1,235 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+)
+
+// DefaultRunTimeout bounds a single scheduled run
+const DefaultRunTimeout = 5 * time.Minute
+
+// schedulerTick is the longest the scheduler sleeps between checks for due queries
+const schedulerTick = time.Minute
+
+// Scheduler runs each enabled query on its own schedule in a long-running
+// process, sharing one Monitor (and so one State) across runs. When the daily
+// request budget is short, high-priority queries get quota first.
+type Scheduler struct {
+	monitor    *Monitor
+	queries    []config.Query
+	schedules  map[string]config.Schedule
+	next       map[string]time.Time
+	runTimeout time.Duration
+	verbose    bool
+}
+
+// NewScheduler parses the schedule of every enabled query. A query that ran
+// before resumes from its last execution recorded in State; one that never
+// ran is due immediately.
+func NewScheduler(m *Monitor, runTimeout time.Duration, verbose bool) (*Scheduler, error) {
+	if runTimeout <= 0 {
+		runTimeout = DefaultRunTimeout
+	}
+
+	s := &Scheduler{
+		monitor:    m,
+		queries:    m.config.GetEnabledQueries(),
+		schedules:  make(map[string]config.Schedule),
+		next:       make(map[string]time.Time),
+		runTimeout: runTimeout,
+		verbose:    verbose,
+	}
+
+	now := time.Now()
+	for _, query := range s.queries {
+		schedule, err := config.ParseSchedule(query.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("query '%s': %w", query.Name, err)
+		}
+		s.schedules[query.Name] = schedule
+
+		s.next[query.Name] = now
+		if metrics, ok := m.state.GetQueryMetrics(query.Name); ok && !metrics.LastExecuted.IsZero() {
+			s.next[query.Name] = schedule.Next(metrics.LastExecuted)
+		}
+	}
+
+	return s, nil
+}
+
+// Run executes due queries until ctx is cancelled. A run in progress when ctx
+// is cancelled is allowed to finish so results are notified and saved.
+func (s *Scheduler) Run(ctx context.Context) error {
+	log.Printf("Daemon started with %d scheduled queries", len(s.queries))
+	if s.verbose {
+		for _, query := range s.queries {
+			log.Printf("  %s: schedule %q, next run %s", query.Name, scheduleSpec(query), s.next[query.Name].Format(time.RFC3339))
+		}
+	}
+
+	for {
+		s.runDue()
+
+		wait := time.Until(s.nextWake())
+		if wait < time.Second {
+			wait = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Daemon shutting down: %v", ctx.Err())
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// runDue runs every query whose next run time has passed, subject to budget.
+// When no query runs, a due digest is still sent.
+func (s *Scheduler) runDue() {
+	now := time.Now()
+
+	due := make([]config.Query, 0)
+	for _, query := range s.queries {
+		if !s.next[query.Name].After(now) {
+			due = append(due, query)
+		}
+	}
+
+	admitted := make([]config.Query, 0)
+	if len(due) > 0 {
+		admitted = s.admit(due, now)
+	}
+	if len(admitted) == 0 {
+		s.sendDueDigest()
+		return
+	}
+
+	for _, query := range admitted {
+		s.next[query.Name] = s.schedules[query.Name].Next(now)
+	}
+
+	// Not derived from the daemon context so a shutdown lets this run finish
+	runCtx, cancel := context.WithTimeout(context.Background(), s.runTimeout)
+	defer cancel()
+
+	log.Printf("Scheduled run: %d of %d due queries", len(admitted), len(due))
+	if err := s.monitor.RunQueries(runCtx, admitted); err != nil {
+		log.Printf("Scheduled run failed: %v", err)
+	}
+}
+
+// sendDueDigest sends queued digest notifications between query runs
+func (s *Scheduler) sendDueDigest() {
+	if s.monitor.dryRun || !s.monitor.digest.DigestDue(time.Now()) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.runTimeout)
+	defer cancel()
+
+	if err := s.monitor.sendDueDigest(ctx); err != nil {
+		log.Printf("Scheduled digest failed: %v", err)
+	}
+	if err := s.monitor.state.Save(); err != nil {
+		log.Printf("Failed to save state after digest: %v", err)
+	}
+}
+
+// admit orders due queries by priority and keeps those the daily budget can
+// afford. Medium and low priority queries may not spend the requests reserved
+// for high-priority runs still scheduled before the UTC day ends. Deferred
+// queries stay due and are reconsidered on the next tick.
+func (s *Scheduler) admit(due []config.Query, now time.Time) []config.Query {
+	sort.SliceStable(due, func(i, j int) bool {
+		return priorityRank(due[i]) < priorityRank(due[j])
+	})
+
+	remaining := s.monitor.limiter.DailyRemaining()
+	reserve := s.highPriorityReserve(now)
+
+	admitted := make([]config.Query, 0, len(due))
+	for _, query := range due {
+		// Each query costs at least one request; extra pages are charged as they happen
+		available := remaining
+		if priorityRank(query) > 0 {
+			available -= reserve
+		}
+
+		// Cached responses may still answer a query once the budget is spent
+		if available <= 0 && s.monitor.cache == nil {
+			if s.verbose {
+				log.Printf("Deferring query '%s': %d requests left today, %d reserved for high priority",
+					query.Name, remaining, reserve)
+			}
+			continue
+		}
+
+		admitted = append(admitted, query)
+		remaining--
+	}
+
+	return admitted
+}
+
+// highPriorityReserve counts high-priority runs scheduled after now and
+// before the next UTC midnight
+func (s *Scheduler) highPriorityReserve(now time.Time) int {
+	midnight := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+	reserve := 0
+	for _, query := range s.queries {
+		if priorityRank(query) != 0 {
+			continue
+		}
+
+		schedule := s.schedules[query.Name]
+		t := s.next[query.Name]
+		if !t.After(now) {
+			t = schedule.Next(now)
+		}
+		for t.Before(midnight) && reserve < 10000 {
+			reserve++
+			t = schedule.Next(t)
+		}
+	}
+
+	return reserve
+}
+
+// nextWake returns the earliest upcoming run time. Deferred queries are
+// already due, so they are only rechecked on the regular tick.
+func (s *Scheduler) nextWake() time.Time {
+	now := time.Now()
+	earliest := now.Add(schedulerTick)
+	for _, t := range s.next {
+		if t.After(now) && t.Before(earliest) {
+			earliest = t
+		}
+	}
+	return earliest
+}
+
+// priorityRank orders high before medium before low; unset counts as medium
+func priorityRank(query config.Query) int {
+	switch query.Notification.Priority {
+	case "high":
+		return 0
+	case "low":
+		return 2
+	default:
+		return 1
+	}
+}
+
+func scheduleSpec(query config.Query) string {
+	if query.Schedule == "" {
+		return config.DefaultSchedule
+	}
+	return query.Schedule
+}
@@ -0,0 +1,1266 @@
+package monitor
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+
+	_ "modernc.org/sqlite" // pure-Go driver registered as "sqlite"
+)
+
+// sqliteSchema creates the state tables. Every observed version of an
+// opportunity is appended to opportunity_history.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS opportunities (
+	notice_id              TEXT PRIMARY KEY,
+	title                  TEXT NOT NULL,
+	deadline               TEXT,
+	hash                   TEXT NOT NULL,
+	first_seen             TEXT NOT NULL,
+	last_seen              TEXT NOT NULL,
+	last_modified          TEXT NOT NULL,
+	resource_links         TEXT NOT NULL DEFAULT '[]',
+	resource_links_tracked INTEGER NOT NULL DEFAULT 0,
+	documents              TEXT NOT NULL DEFAULT '[]',
+	queries                TEXT NOT NULL DEFAULT '[]',
+	reminders_sent         TEXT NOT NULL DEFAULT '[]',
+	stage                  TEXT NOT NULL DEFAULT '',
+	owner                  TEXT NOT NULL DEFAULT '',
+	notes                  TEXT NOT NULL DEFAULT '',
+	stage_updated          TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_opportunities_last_seen ON opportunities(last_seen);
+
+CREATE TABLE IF NOT EXISTS opportunity_history (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	notice_id   TEXT NOT NULL,
+	recorded_at TEXT NOT NULL,
+	title       TEXT NOT NULL,
+	deadline    TEXT,
+	hash        TEXT NOT NULL,
+	snapshot    TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_opportunity_history_notice ON opportunity_history(notice_id);
+
+CREATE TABLE IF NOT EXISTS query_metrics (
+	query_name TEXT PRIMARY KEY,
+	metrics    TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS digest_queue (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	query_name   TEXT NOT NULL,
+	priority     TEXT NOT NULL,
+	created_at   TEXT NOT NULL,
+	notification TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS outbox (
+	id         TEXT PRIMARY KEY,
+	channel    TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	created_at TEXT NOT NULL,
+	delivery   TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS github_issues (
+	repository TEXT NOT NULL,
+	notice_id  TEXT NOT NULL,
+	number     INTEGER NOT NULL,
+	closed     INTEGER NOT NULL DEFAULT 0,
+	updated_at TEXT NOT NULL,
+	PRIMARY KEY (repository, notice_id)
+);
+
+CREATE TABLE IF NOT EXISTS awards (
+	notice_id        TEXT PRIMARY KEY,
+	linked_notice_id TEXT NOT NULL DEFAULT '',
+	recorded_at      TEXT NOT NULL,
+	record           TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// Keys in the meta table
+const (
+	metaLastRun             = "last_run"
+	metaLastSuccessfulQuery = "last_successful_query_time"
+	metaDailyRequestCount   = "daily_request_count"
+	metaDailyRequestDate    = "daily_request_date"
+	metaRateLimitedUntil    = "rate_limited_until"
+	metaLastDigest          = "last_digest"
+)
+
+// SQLiteStore keeps state in a SQLite database. Opportunity and metric
+// changes are buffered until Save, which writes them in one transaction.
+// Request counters are written through immediately so concurrent runs
+// share a single daily budget. Pipeline changes are too, and saving an
+// opportunity never overwrites them, so a stage set during a run is kept.
+type SQLiteStore struct {
+	mu                sync.Mutex
+	db                *sql.DB
+	pendingOpps       map[string]samgov.OpportunityState
+	pendingHistory    []samgov.OpportunityState
+	pendingMetrics    map[string]QueryMetrics
+	pendingMeta       map[string]string
+	pendingDigests    []notify.PendingNotification
+	digestsDirty      bool
+	pendingDeliveries []notify.Delivery
+	deliveriesDirty   bool
+	pendingIssues     map[string]notify.IssueRecord
+	pendingAwards     map[string]samgov.AwardRecord
+}
+
+// OpenSQLiteStore opens or creates the state database at path
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("creating state directory: %w", err)
+		}
+	}
+
+	// WAL lets readers proceed during a save; the busy timeout and immediate
+	// transactions make concurrent runs wait for each other instead of failing
+	dsn := "file:" + path + "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening state database %s: %w", path, err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating state schema: %w", err)
+	}
+	if err := migrateSQLiteSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{
+		db:             db,
+		pendingOpps:    make(map[string]samgov.OpportunityState),
+		pendingMetrics: make(map[string]QueryMetrics),
+		pendingMeta:    make(map[string]string),
+		pendingIssues:  make(map[string]notify.IssueRecord),
+		pendingAwards:  make(map[string]samgov.AwardRecord),
+	}, nil
+}
+
+// Close releases the database. Unsaved changes are discarded.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Save writes all pending changes in a single transaction
+func (s *SQLiteStore) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pendingOpps) == 0 && len(s.pendingMetrics) == 0 && len(s.pendingMeta) == 0 && !s.digestsDirty && !s.deliveriesDirty && len(s.pendingIssues) == 0 && len(s.pendingAwards) == 0 {
+		return nil // No changes to save
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning state transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, opp := range s.pendingOpps {
+		if err := upsertOpportunity(tx, opp); err != nil {
+			return err
+		}
+	}
+
+	for _, opp := range s.pendingHistory {
+		version, _ := opp.LatestVersion()
+		if err := insertVersion(tx, opp, version); err != nil {
+			return err
+		}
+	}
+
+	for name, metrics := range s.pendingMetrics {
+		data, err := json.Marshal(metrics)
+		if err != nil {
+			return fmt.Errorf("marshaling metrics for %s: %w", name, err)
+		}
+		_, err = tx.Exec(`INSERT INTO query_metrics (query_name, metrics) VALUES (?, ?)
+			ON CONFLICT(query_name) DO UPDATE SET metrics = excluded.metrics`, name, string(data))
+		if err != nil {
+			return fmt.Errorf("saving metrics for %s: %w", name, err)
+		}
+	}
+
+	for key, value := range s.pendingMeta {
+		if err := setMeta(tx, key, value); err != nil {
+			return err
+		}
+	}
+
+	if s.digestsDirty {
+		if err := replaceDigestQueue(tx, s.pendingDigests); err != nil {
+			return err
+		}
+	}
+
+	if s.deliveriesDirty {
+		if err := replaceOutbox(tx, s.pendingDeliveries); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range s.pendingIssues {
+		if err := upsertIssue(tx, record); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range s.pendingAwards {
+		if err := upsertAward(tx, record); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing state: %w", err)
+	}
+
+	s.pendingOpps = make(map[string]samgov.OpportunityState)
+	s.pendingHistory = nil
+	s.pendingMetrics = make(map[string]QueryMetrics)
+	s.pendingMeta = make(map[string]string)
+	s.pendingDigests = nil
+	s.digestsDirty = false
+	s.pendingDeliveries = nil
+	s.deliveriesDirty = false
+	s.pendingIssues = make(map[string]notify.IssueRecord)
+	s.pendingAwards = make(map[string]samgov.AwardRecord)
+	return nil
+}
+
+// AddOpportunity adds or updates an opportunity, pending the next Save
+func (s *SQLiteStore) AddOpportunity(opp samgov.Opportunity) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var previous *samgov.OpportunityState
+	if existing, exists := s.lookupOpportunity(opp.NoticeID); exists {
+		previous = &existing
+	}
+
+	merged := mergeOpportunity(previous, opp, time.Now())
+	s.pendingOpps[opp.NoticeID] = merged
+	if previous == nil || len(merged.Versions) > len(previous.Versions) {
+		s.pendingHistory = append(s.pendingHistory, merged)
+	}
+	return previous == nil
+}
+
+// AddDocuments records downloaded files against a stored opportunity
+func (s *SQLiteStore) AddDocuments(noticeID string, docs []samgov.Document) {
+	if len(docs) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.lookupOpportunity(noticeID)
+	if !exists {
+		return
+	}
+	existing.Documents = append(existing.Documents, docs...)
+	s.pendingOpps[noticeID] = existing
+}
+
+// AddQueryMatch records that a query matched a stored opportunity
+func (s *SQLiteStore) AddQueryMatch(noticeID, queryName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.lookupOpportunity(noticeID)
+	if !exists || containsString(existing.Queries, queryName) {
+		return
+	}
+	existing.Queries = append(existing.Queries, queryName)
+	s.pendingOpps[noticeID] = existing
+}
+
+// MarkRemindersSent records deadline reminders sent for a stored opportunity
+func (s *SQLiteStore) MarkRemindersSent(noticeID string, reminders []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.lookupOpportunity(noticeID)
+	if !exists {
+		return
+	}
+	for _, reminder := range reminders {
+		existing.RemindersSent = appendUnique(existing.RemindersSent, reminder)
+	}
+	s.pendingOpps[noticeID] = existing
+}
+
+// GetOpportunity retrieves an opportunity, including unsaved changes
+func (s *SQLiteStore) GetOpportunity(noticeID string) (samgov.OpportunityState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lookupOpportunity(noticeID)
+}
+
+// ListOpportunities returns every stored opportunity, including unsaved
+// changes. Versions are not loaded; use GetOpportunity for the history.
+func (s *SQLiteStore) ListOpportunities() []samgov.OpportunityState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	opportunities := make([]samgov.OpportunityState, 0)
+	rows, err := s.db.Query(`SELECT ` + opportunityColumns + ` FROM opportunities`)
+	if err != nil {
+		log.Printf("Failed to list opportunities: %v", err)
+	} else {
+		defer rows.Close()
+		for rows.Next() {
+			opp, err := scanOpportunity(rows)
+			if err != nil {
+				log.Printf("Failed to read opportunity: %v", err)
+				continue
+			}
+			if _, pending := s.pendingOpps[opp.NoticeID]; !pending {
+				opportunities = append(opportunities, opp)
+			}
+		}
+	}
+
+	for _, opp := range s.pendingOpps {
+		opportunities = append(opportunities, opp)
+	}
+	return opportunities
+}
+
+// CleanupOldOpportunities removes opportunities not seen within maxAge.
+// The deletion is applied immediately.
+func (s *SQLiteStore) CleanupOldOpportunities(maxAge time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for noticeID, opp := range s.pendingOpps {
+		if opp.LastSeen.Before(cutoff) && opp.PipelineStage() == samgov.StageNew {
+			delete(s.pendingOpps, noticeID)
+			removed++
+		}
+	}
+
+	result, err := s.db.Exec(`DELETE FROM opportunities WHERE last_seen < ? AND stage IN ('', ?)`,
+		formatTime(cutoff), string(samgov.StageNew))
+	if err != nil {
+		log.Printf("Failed to clean up old opportunities: %v", err)
+		return removed
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		removed += int(n)
+	}
+	return removed
+}
+
+// SetLastRun updates the last run timestamp
+func (s *SQLiteStore) SetLastRun(t time.Time) {
+	s.setPendingMeta(metaLastRun, formatTime(t))
+}
+
+// GetLastRun returns the last run timestamp
+func (s *SQLiteStore) GetLastRun() time.Time {
+	return s.getTimeMeta(metaLastRun)
+}
+
+// SetLastSuccessfulQuery updates the last successful query timestamp
+func (s *SQLiteStore) SetLastSuccessfulQuery(t time.Time) {
+	s.setPendingMeta(metaLastSuccessfulQuery, formatTime(t))
+}
+
+// GetLastSuccessfulQuery returns the last successful query timestamp
+func (s *SQLiteStore) GetLastSuccessfulQuery() time.Time {
+	return s.getTimeMeta(metaLastSuccessfulQuery)
+}
+
+// GetPendingDigests returns the queued digest notifications, including
+// unsaved changes
+func (s *SQLiteStore) GetPendingDigests() []notify.PendingNotification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.digestsDirty {
+		return append([]notify.PendingNotification(nil), s.pendingDigests...)
+	}
+
+	pending := make([]notify.PendingNotification, 0)
+	rows, err := s.db.Query(`SELECT notification FROM digest_queue ORDER BY id`)
+	if err != nil {
+		log.Printf("Failed to read digest queue: %v", err)
+		return pending
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		var item notify.PendingNotification
+		if err := rows.Scan(&data); err != nil || json.Unmarshal([]byte(data), &item) != nil {
+			log.Printf("Failed to read queued digest notification: %v", err)
+			continue
+		}
+		pending = append(pending, item)
+	}
+	return pending
+}
+
+// SetPendingDigests replaces the queued digest notifications, pending the
+// next Save
+func (s *SQLiteStore) SetPendingDigests(pending []notify.PendingNotification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pendingDigests = append([]notify.PendingNotification(nil), pending...)
+	s.digestsDirty = true
+}
+
+// GetLastDigest returns when the last digest was sent
+func (s *SQLiteStore) GetLastDigest() time.Time {
+	return s.getTimeMeta(metaLastDigest)
+}
+
+// SetLastDigest records when a digest was sent
+func (s *SQLiteStore) SetLastDigest(t time.Time) {
+	s.setPendingMeta(metaLastDigest, formatTime(t))
+}
+
+// GetDeliveries returns the notification outbox, including unsaved changes
+func (s *SQLiteStore) GetDeliveries() []notify.Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.deliveriesDirty {
+		return append([]notify.Delivery(nil), s.pendingDeliveries...)
+	}
+
+	deliveries := make([]notify.Delivery, 0)
+	rows, err := s.db.Query(`SELECT delivery FROM outbox ORDER BY created_at, id`)
+	if err != nil {
+		log.Printf("Failed to read notification outbox: %v", err)
+		return deliveries
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		var delivery notify.Delivery
+		if err := rows.Scan(&data); err != nil || json.Unmarshal([]byte(data), &delivery) != nil {
+			log.Printf("Failed to read notification delivery: %v", err)
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+// SetDeliveries replaces the notification outbox, pending the next Save
+func (s *SQLiteStore) SetDeliveries(deliveries []notify.Delivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pendingDeliveries = append([]notify.Delivery(nil), deliveries...)
+	s.deliveriesDirty = true
+}
+
+// GetIssue returns the issue opened for a notice in repository, including
+// unsaved changes
+func (s *SQLiteStore) GetIssue(repository, noticeID string) (notify.IssueRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.pendingIssues[notify.IssueKey(repository, noticeID)]; ok {
+		return record, true
+	}
+
+	record := notify.IssueRecord{Repository: repository, NoticeID: noticeID}
+	var closed int
+	var updatedAt string
+	err := s.db.QueryRow(`SELECT number, closed, updated_at FROM github_issues WHERE repository = ? AND notice_id = ?`,
+		repository, noticeID).Scan(&record.Number, &closed, &updatedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to read GitHub issue for %s: %v", noticeID, err)
+		}
+		return notify.IssueRecord{}, false
+	}
+	record.Closed = closed != 0
+	record.UpdatedAt = parseTime(updatedAt)
+	return record, true
+}
+
+// SetIssue records the issue opened for a notice, pending the next Save
+func (s *SQLiteStore) SetIssue(record notify.IssueRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pendingIssues[notify.IssueKey(record.Repository, record.NoticeID)] = record
+}
+
+// UpdatePipeline changes the capture stage, owner or notes of a stored
+// opportunity. The change is written immediately.
+func (s *SQLiteStore) UpdatePipeline(noticeID string, change PipelineChange) (samgov.OpportunityState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.lookupOpportunity(noticeID)
+	if !exists {
+		return samgov.OpportunityState{}, fmt.Errorf("opportunity %s is not tracked", noticeID)
+	}
+	updated, err := applyPipelineChange(existing, change, time.Now())
+	if err != nil {
+		return existing, err
+	}
+
+	if err := updatePipeline(s.db, updated); err != nil {
+		return existing, err
+	}
+	// Opportunities first seen this run are inserted with their stage on Save
+	if _, pending := s.pendingOpps[noticeID]; pending {
+		s.pendingOpps[noticeID] = updated
+	}
+	return updated, nil
+}
+
+// RecordAward stores an award notice, pending the next Save, reporting
+// whether it is new
+func (s *SQLiteStore) RecordAward(record samgov.AwardRecord) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, exists := s.pendingAwards[record.NoticeID]
+	if !exists {
+		previous, exists = s.lookupAward(record.NoticeID)
+	}
+	if exists {
+		record = mergeAward(&previous, record)
+	}
+	s.pendingAwards[record.NoticeID] = record
+	return !exists
+}
+
+// ListAwards returns every recorded award, including unsaved changes
+func (s *SQLiteStore) ListAwards() []samgov.AwardRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	awards := make([]samgov.AwardRecord, 0)
+	rows, err := s.db.Query(`SELECT record FROM awards ORDER BY recorded_at, notice_id`)
+	if err != nil {
+		log.Printf("Failed to list awards: %v", err)
+	} else {
+		defer rows.Close()
+		for rows.Next() {
+			var data string
+			var record samgov.AwardRecord
+			if err := rows.Scan(&data); err != nil || json.Unmarshal([]byte(data), &record) != nil {
+				log.Printf("Failed to read award: %v", err)
+				continue
+			}
+			if _, pending := s.pendingAwards[record.NoticeID]; !pending {
+				awards = append(awards, record)
+			}
+		}
+	}
+
+	for _, record := range s.pendingAwards {
+		awards = append(awards, record)
+	}
+	return awards
+}
+
+// UpdateQueryMetrics updates metrics for a query
+func (s *SQLiteStore) UpdateQueryMetrics(queryName string, executionTime time.Duration, opportunityCount int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metrics, _ := s.lookupQueryMetrics(queryName)
+	s.pendingMetrics[queryName] = recordQueryExecution(metrics, executionTime, opportunityCount, err)
+}
+
+// GetQueryMetrics returns metrics for a query
+func (s *SQLiteStore) GetQueryMetrics(queryName string) (QueryMetrics, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lookupQueryMetrics(queryName)
+}
+
+// GetStats returns statistics about the current state
+func (s *SQLiteStore) GetStats() StateStats {
+	opportunities := s.ListOpportunities()
+
+	s.mu.Lock()
+	metricsByName := make(map[string]QueryMetrics)
+	rows, err := s.db.Query(`SELECT query_name, metrics FROM query_metrics`)
+	if err != nil {
+		log.Printf("Failed to list query metrics: %v", err)
+	} else {
+		for rows.Next() {
+			var name, data string
+			var metrics QueryMetrics
+			if err := rows.Scan(&name, &data); err == nil && json.Unmarshal([]byte(data), &metrics) == nil {
+				metricsByName[name] = metrics
+			}
+		}
+		rows.Close()
+	}
+	for name, metrics := range s.pendingMetrics {
+		metricsByName[name] = metrics
+	}
+	s.mu.Unlock()
+
+	metrics := make([]QueryMetrics, 0, len(metricsByName))
+	for _, m := range metricsByName {
+		metrics = append(metrics, m)
+	}
+	return computeStateStats(opportunities, metrics, s.GetLastRun())
+}
+
+// TryIncrementDailyRequests charges one request for the current UTC day
+// unless limit has been reached. The count is committed immediately.
+func (s *SQLiteStore) TryIncrementDailyRequests(limit int) (int, bool) {
+	count, allowed := 0, false
+	err := s.updateDailyCount(func(current int) int {
+		count = current
+		if limit > 0 && current >= limit {
+			return current
+		}
+		count = current + 1
+		allowed = true
+		return count
+	})
+	if err != nil {
+		log.Printf("Failed to update daily request count: %v", err)
+		return count, false
+	}
+	return count, allowed
+}
+
+// IncrementDailyRequests increments the daily request counter
+func (s *SQLiteStore) IncrementDailyRequests() int {
+	count := 0
+	err := s.updateDailyCount(func(current int) int {
+		count = current + 1
+		return count
+	})
+	if err != nil {
+		log.Printf("Failed to update daily request count: %v", err)
+	}
+	return count
+}
+
+// ExhaustDailyRequests marks today's budget as fully used
+func (s *SQLiteStore) ExhaustDailyRequests(limit int) {
+	err := s.updateDailyCount(func(current int) int {
+		if current < limit {
+			return limit
+		}
+		return current
+	})
+	if err != nil {
+		log.Printf("Failed to update daily request count: %v", err)
+	}
+}
+
+// GetDailyRequestCount returns the current daily request count
+func (s *SQLiteStore) GetDailyRequestCount() (int, string) {
+	today := time.Now().UTC().Format("2006-01-02")
+
+	date, _ := getMeta(s.db, metaDailyRequestDate)
+	if date != today {
+		return 0, today
+	}
+	value, _ := getMeta(s.db, metaDailyRequestCount)
+	count := 0
+	fmt.Sscanf(value, "%d", &count)
+	return count, date
+}
+
+// GetRateLimitedUntil returns when the server-requested back-off ends
+func (s *SQLiteStore) GetRateLimitedUntil() time.Time {
+	value, _ := getMeta(s.db, metaRateLimitedUntil)
+	return parseTime(value)
+}
+
+// SetRateLimitedUntil records a server-requested back-off immediately
+func (s *SQLiteStore) SetRateLimitedUntil(t time.Time) {
+	if err := setMeta(s.db, metaRateLimitedUntil, formatTime(t)); err != nil {
+		log.Printf("Failed to record rate limit back-off: %v", err)
+	}
+}
+
+// ImportJSONIfEmpty seeds an empty database from a JSON state file,
+// returning the number of opportunities imported
+func (s *SQLiteStore) ImportJSONIfEmpty(jsonPath string, verbose bool) (int, error) {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM opportunities`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("checking state database: %w", err)
+	}
+	if count > 0 {
+		return 0, nil
+	}
+	if _, err := os.Stat(jsonPath); err != nil {
+		return 0, nil // Nothing to import
+	}
+
+	imported, err := s.ImportJSON(jsonPath)
+	if err != nil {
+		return 0, err
+	}
+	if verbose || imported > 0 {
+		log.Printf("Imported %d opportunities from %s into the SQLite state", imported, jsonPath)
+	}
+	return imported, nil
+}
+
+// ImportJSON copies opportunities, metrics and counters from a JSON state
+// file into the database in one transaction
+func (s *SQLiteStore) ImportJSON(jsonPath string) (int, error) {
+	state, err := LoadState(jsonPath)
+	if err != nil {
+		return 0, fmt.Errorf("loading JSON state: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("beginning import transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, opp := range state.Opportunities {
+		if err := upsertOpportunity(tx, opp); err != nil {
+			return 0, err
+		}
+		if err := updatePipeline(tx, opp); err != nil {
+			return 0, err
+		}
+		for _, version := range opp.Versions {
+			if err := insertVersion(tx, opp, version); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for name, metrics := range state.QueryMetrics {
+		data, err := json.Marshal(metrics)
+		if err != nil {
+			return 0, fmt.Errorf("marshaling metrics for %s: %w", name, err)
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO query_metrics (query_name, metrics) VALUES (?, ?)`, name, string(data)); err != nil {
+			return 0, fmt.Errorf("importing metrics for %s: %w", name, err)
+		}
+	}
+
+	if err := replaceDigestQueue(tx, state.PendingDigests); err != nil {
+		return 0, err
+	}
+
+	if err := replaceOutbox(tx, state.Deliveries); err != nil {
+		return 0, err
+	}
+
+	for _, record := range state.GitHubIssues {
+		if err := upsertIssue(tx, record); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, record := range state.Awards {
+		if err := upsertAward(tx, record); err != nil {
+			return 0, err
+		}
+	}
+
+	meta := map[string]string{
+		metaLastRun:             formatTime(state.LastRun),
+		metaLastDigest:          formatTime(state.LastDigest),
+		metaLastSuccessfulQuery: formatTime(state.LastSuccessfulQueryTime),
+		metaRateLimitedUntil:    formatTime(state.RateLimitedUntil),
+	}
+	if state.DailyRequestDate != "" {
+		meta[metaDailyRequestDate] = state.DailyRequestDate
+		meta[metaDailyRequestCount] = fmt.Sprintf("%d", state.DailyRequestCount)
+	}
+	for key, value := range meta {
+		if err := setMeta(tx, key, value); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing import: %w", err)
+	}
+	return len(state.Opportunities), nil
+}
+
+// updateDailyCount applies update to today's request count in its own transaction
+func (s *SQLiteStore) updateDailyCount(update func(current int) int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	today := time.Now().UTC().Format("2006-01-02")
+	current := 0
+	date, err := getMeta(tx, metaDailyRequestDate)
+	if err != nil {
+		return err
+	}
+	if date == today {
+		value, err := getMeta(tx, metaDailyRequestCount)
+		if err != nil {
+			return err
+		}
+		fmt.Sscanf(value, "%d", &current)
+	}
+
+	next := update(current)
+	if date == today && next == current {
+		return nil
+	}
+	if err := setMeta(tx, metaDailyRequestDate, today); err != nil {
+		return err
+	}
+	if err := setMeta(tx, metaDailyRequestCount, fmt.Sprintf("%d", next)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lookupOpportunity checks pending changes before the database; callers hold mu
+func (s *SQLiteStore) lookupOpportunity(noticeID string) (samgov.OpportunityState, bool) {
+	if opp, ok := s.pendingOpps[noticeID]; ok {
+		return opp, true
+	}
+
+	row := s.db.QueryRow(`SELECT `+opportunityColumns+` FROM opportunities WHERE notice_id = ?`, noticeID)
+	opp, err := scanOpportunity(row)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to read opportunity %s: %v", noticeID, err)
+		}
+		return samgov.OpportunityState{}, false
+	}
+
+	versions, err := s.loadVersions(noticeID)
+	if err != nil {
+		log.Printf("Failed to read versions of %s: %v", noticeID, err)
+	}
+	opp.Versions = versions
+	return opp, true
+}
+
+// loadVersions reads the recorded versions of an opportunity, oldest first
+func (s *SQLiteStore) loadVersions(noticeID string) ([]samgov.OpportunityVersion, error) {
+	rows, err := s.db.Query(`SELECT recorded_at, snapshot FROM opportunity_history
+		WHERE notice_id = ? AND snapshot IS NOT NULL ORDER BY id`, noticeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]samgov.OpportunityVersion, 0)
+	for rows.Next() {
+		var recordedAt, data string
+		if err := rows.Scan(&recordedAt, &data); err != nil {
+			return versions, err
+		}
+		version := samgov.OpportunityVersion{ObservedAt: parseTime(recordedAt)}
+		if err := json.Unmarshal([]byte(data), &version.Snapshot); err != nil {
+			return versions, fmt.Errorf("parsing snapshot: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// lookupQueryMetrics checks pending changes before the database; callers hold mu
+func (s *SQLiteStore) lookupAward(noticeID string) (samgov.AwardRecord, bool) {
+	var data string
+	err := s.db.QueryRow(`SELECT record FROM awards WHERE notice_id = ?`, noticeID).Scan(&data)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to read award %s: %v", noticeID, err)
+		}
+		return samgov.AwardRecord{}, false
+	}
+
+	var record samgov.AwardRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		log.Printf("Failed to parse award %s: %v", noticeID, err)
+		return samgov.AwardRecord{}, false
+	}
+	return record, true
+}
+
+func (s *SQLiteStore) lookupQueryMetrics(queryName string) (QueryMetrics, bool) {
+	if metrics, ok := s.pendingMetrics[queryName]; ok {
+		return metrics, true
+	}
+
+	var data string
+	err := s.db.QueryRow(`SELECT metrics FROM query_metrics WHERE query_name = ?`, queryName).Scan(&data)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to read metrics for %s: %v", queryName, err)
+		}
+		return QueryMetrics{}, false
+	}
+
+	var metrics QueryMetrics
+	if err := json.Unmarshal([]byte(data), &metrics); err != nil {
+		log.Printf("Failed to parse metrics for %s: %v", queryName, err)
+		return QueryMetrics{}, false
+	}
+	return metrics, true
+}
+
+func (s *SQLiteStore) setPendingMeta(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingMeta[key] = value
+}
+
+func (s *SQLiteStore) getTimeMeta(key string) time.Time {
+	s.mu.Lock()
+	value, pending := s.pendingMeta[key]
+	s.mu.Unlock()
+
+	if !pending {
+		value, _ = getMeta(s.db, key)
+	}
+	return parseTime(value)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func getMeta(db execer, key string) (string, error) {
+	var value string
+	err := db.QueryRow(`SELECT value FROM meta WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return value, err
+}
+
+func setMeta(db execer, key, value string) error {
+	_, err := db.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value)
+	if err != nil {
+		return fmt.Errorf("saving %s: %w", key, err)
+	}
+	return nil
+}
+
+const opportunityColumns = `notice_id, title, deadline, hash, first_seen, last_seen, last_modified,
+	resource_links, resource_links_tracked, documents, queries, reminders_sent,
+	stage, owner, notes, stage_updated`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOpportunity(row rowScanner) (samgov.OpportunityState, error) {
+	var opp samgov.OpportunityState
+	var deadline sql.NullString
+	var firstSeen, lastSeen, lastModified, links, docs, queries, reminders, stage, stageUpdated string
+	var tracked int
+
+	err := row.Scan(&opp.NoticeID, &opp.Title, &deadline, &opp.Hash,
+		&firstSeen, &lastSeen, &lastModified, &links, &tracked, &docs, &queries, &reminders,
+		&stage, &opp.Owner, &opp.Notes, &stageUpdated)
+	if err != nil {
+		return opp, err
+	}
+
+	if deadline.Valid {
+		opp.Deadline = &deadline.String
+	}
+	opp.FirstSeen = parseTime(firstSeen)
+	opp.LastSeen = parseTime(lastSeen)
+	opp.LastModified = parseTime(lastModified)
+	opp.Stage = samgov.Stage(stage)
+	opp.StageUpdated = parseTime(stageUpdated)
+	opp.ResourceLinksTracked = tracked != 0
+	if err := json.Unmarshal([]byte(links), &opp.ResourceLinks); err != nil {
+		return opp, fmt.Errorf("parsing resource links: %w", err)
+	}
+	if err := json.Unmarshal([]byte(docs), &opp.Documents); err != nil {
+		return opp, fmt.Errorf("parsing documents: %w", err)
+	}
+	if err := json.Unmarshal([]byte(queries), &opp.Queries); err != nil {
+		return opp, fmt.Errorf("parsing queries: %w", err)
+	}
+	if err := json.Unmarshal([]byte(reminders), &opp.RemindersSent); err != nil {
+		return opp, fmt.Errorf("parsing sent reminders: %w", err)
+	}
+	return opp, nil
+}
+
+// migrateSQLiteSchema adds columns introduced after a database was created
+func migrateSQLiteSchema(db *sql.DB) error {
+	migrations := []struct {
+		table, column, definition string
+	}{
+		{"opportunity_history", "snapshot", "TEXT"},
+		{"opportunities", "queries", "TEXT NOT NULL DEFAULT '[]'"},
+		{"opportunities", "reminders_sent", "TEXT NOT NULL DEFAULT '[]'"},
+		{"opportunities", "stage", "TEXT NOT NULL DEFAULT ''"},
+		{"opportunities", "owner", "TEXT NOT NULL DEFAULT ''"},
+		{"opportunities", "notes", "TEXT NOT NULL DEFAULT ''"},
+		{"opportunities", "stage_updated", "TEXT NOT NULL DEFAULT ''"},
+	}
+
+	for _, m := range migrations {
+		exists, err := hasColumn(db, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + m.table + ` ADD COLUMN ` + m.column + ` ` + m.definition); err != nil {
+			return fmt.Errorf("migrating state schema: %w", err)
+		}
+	}
+	return nil
+}
+
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(`PRAGMA table_info(` + table + `)`)
+	if err != nil {
+		return false, fmt.Errorf("reading state schema: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, fmt.Errorf("reading state schema: %w", err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func insertVersion(tx *sql.Tx, opp samgov.OpportunityState, version samgov.OpportunityVersion) error {
+	snapshot, err := json.Marshal(version.Snapshot)
+	if err != nil {
+		return fmt.Errorf("marshaling snapshot for %s: %w", opp.NoticeID, err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO opportunity_history (notice_id, recorded_at, title, deadline, hash, snapshot)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		opp.NoticeID, formatTime(version.ObservedAt), version.Snapshot.Title,
+		nullableString(&version.Snapshot.ResponseDeadline), opp.Hash, string(snapshot))
+	if err != nil {
+		return fmt.Errorf("recording history for %s: %w", opp.NoticeID, err)
+	}
+	return nil
+}
+
+// replaceDigestQueue stores pending as the whole digest queue
+func replaceDigestQueue(tx *sql.Tx, pending []notify.PendingNotification) error {
+	if _, err := tx.Exec(`DELETE FROM digest_queue`); err != nil {
+		return fmt.Errorf("clearing digest queue: %w", err)
+	}
+	for _, item := range pending {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("marshaling digest notification for %s: %w", item.QueryName, err)
+		}
+		_, err = tx.Exec(`INSERT INTO digest_queue (query_name, priority, created_at, notification) VALUES (?, ?, ?, ?)`,
+			item.QueryName, string(item.Priority), formatTime(item.CreatedAt), string(data))
+		if err != nil {
+			return fmt.Errorf("queueing digest notification for %s: %w", item.QueryName, err)
+		}
+	}
+	return nil
+}
+
+// replaceOutbox stores deliveries as the whole notification outbox
+func replaceOutbox(tx *sql.Tx, deliveries []notify.Delivery) error {
+	if _, err := tx.Exec(`DELETE FROM outbox`); err != nil {
+		return fmt.Errorf("clearing notification outbox: %w", err)
+	}
+	for _, delivery := range deliveries {
+		data, err := json.Marshal(delivery)
+		if err != nil {
+			return fmt.Errorf("marshaling delivery %s: %w", delivery.ID, err)
+		}
+		_, err = tx.Exec(`INSERT INTO outbox (id, channel, status, created_at, delivery) VALUES (?, ?, ?, ?, ?)`,
+			delivery.ID, delivery.Channel, string(delivery.Status), formatTime(delivery.CreatedAt), string(data))
+		if err != nil {
+			return fmt.Errorf("saving delivery %s: %w", delivery.ID, err)
+		}
+	}
+	return nil
+}
+
+// upsertIssue stores the issue opened for a notice
+func upsertIssue(tx *sql.Tx, record notify.IssueRecord) error {
+	closed := 0
+	if record.Closed {
+		closed = 1
+	}
+	_, err := tx.Exec(`INSERT INTO github_issues (repository, notice_id, number, closed, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(repository, notice_id) DO UPDATE SET number = excluded.number, closed = excluded.closed, updated_at = excluded.updated_at`,
+		record.Repository, record.NoticeID, record.Number, closed, formatTime(record.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("saving GitHub issue for %s: %w", record.NoticeID, err)
+	}
+	return nil
+}
+
+// upsertAward stores a recorded award
+func upsertAward(tx *sql.Tx, record samgov.AwardRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling award %s: %w", record.NoticeID, err)
+	}
+	_, err = tx.Exec(`INSERT INTO awards (notice_id, linked_notice_id, recorded_at, record) VALUES (?, ?, ?, ?)
+		ON CONFLICT(notice_id) DO UPDATE SET linked_notice_id = excluded.linked_notice_id, record = excluded.record`,
+		record.NoticeID, record.LinkedNoticeID, formatTime(record.RecordedAt), string(data))
+	if err != nil {
+		return fmt.Errorf("saving award %s: %w", record.NoticeID, err)
+	}
+	return nil
+}
+
+func upsertOpportunity(tx *sql.Tx, opp samgov.OpportunityState) error {
+	links, err := json.Marshal(nonNil(opp.ResourceLinks))
+	if err != nil {
+		return fmt.Errorf("marshaling resource links for %s: %w", opp.NoticeID, err)
+	}
+	docs, err := json.Marshal(opp.Documents)
+	if err != nil {
+		return fmt.Errorf("marshaling documents for %s: %w", opp.NoticeID, err)
+	}
+	if opp.Documents == nil {
+		docs = []byte("[]")
+	}
+
+	queries, err := json.Marshal(nonNil(opp.Queries))
+	if err != nil {
+		return fmt.Errorf("marshaling queries for %s: %w", opp.NoticeID, err)
+	}
+	reminders, err := json.Marshal(nonNil(opp.RemindersSent))
+	if err != nil {
+		return fmt.Errorf("marshaling sent reminders for %s: %w", opp.NoticeID, err)
+	}
+
+	tracked := 0
+	if opp.ResourceLinksTracked {
+		tracked = 1
+	}
+
+	// The pipeline columns are only set on insert; see updatePipeline
+	_, err = tx.Exec(`INSERT INTO opportunities (`+opportunityColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(notice_id) DO UPDATE SET
+			title = excluded.title,
+			deadline = excluded.deadline,
+			hash = excluded.hash,
+			last_seen = excluded.last_seen,
+			last_modified = excluded.last_modified,
+			resource_links = excluded.resource_links,
+			resource_links_tracked = excluded.resource_links_tracked,
+			documents = excluded.documents,
+			queries = excluded.queries,
+			reminders_sent = excluded.reminders_sent`,
+		opp.NoticeID, opp.Title, nullableString(opp.Deadline), opp.Hash,
+		formatTime(opp.FirstSeen), formatTime(opp.LastSeen), formatTime(opp.LastModified),
+		string(links), tracked, string(docs), string(queries), string(reminders),
+		string(opp.Stage), opp.Owner, opp.Notes, formatTime(opp.StageUpdated))
+	if err != nil {
+		return fmt.Errorf("saving opportunity %s: %w", opp.NoticeID, err)
+	}
+	return nil
+}
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// updatePipeline writes an opportunity's capture stage, owner and notes
+func updatePipeline(db sqlExecer, opp samgov.OpportunityState) error {
+	_, err := db.Exec(`UPDATE opportunities SET stage = ?, owner = ?, notes = ?, stage_updated = ? WHERE notice_id = ?`,
+		string(opp.Stage), opp.Owner, opp.Notes, formatTime(opp.StageUpdated), opp.NoticeID)
+	if err != nil {
+		return fmt.Errorf("saving pipeline stage of %s: %w", opp.NoticeID, err)
+	}
+	return nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nullableString(s *string) interface{} {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return *s
+}
+
+// sqliteTimeFormat is fixed width so stored times sort as text
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// formatTime stores times as UTC text; the zero time is stored empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+func parseTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(sqliteTimeFormat, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,312 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func openTestStore(t *testing.T, path string) *SQLiteStore {
+	t.Helper()
+	store, err := OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("opening store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func testOpportunity(noticeID, title string) samgov.Opportunity {
+	return samgov.Opportunity{
+		NoticeID:   noticeID,
+		Title:      title,
+		PostedDate: "2024-01-15",
+		Type:       "Solicitation",
+		Active:     "Yes",
+	}
+}
+
+func TestSQLiteStoreImportJSONIfEmpty(t *testing.T) {
+	tests := []struct {
+		name         string
+		writeJSON    bool
+		seedDatabase bool
+		wantImported int
+		wantTitle    string // Title of A-1 afterwards; empty means A-1 is absent
+	}{
+		{name: "empty database imports the JSON state", writeJSON: true, wantImported: 2, wantTitle: "Imported"},
+		{name: "missing JSON file imports nothing", writeJSON: false, wantImported: 0},
+		{name: "existing opportunities skip the import", writeJSON: true, seedDatabase: true, wantImported: 0, wantTitle: "Already in SQLite"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			jsonPath := filepath.Join(dir, "state.json")
+
+			if tt.writeJSON {
+				state, err := LoadState(jsonPath)
+				if err != nil {
+					t.Fatalf("loading JSON state: %v", err)
+				}
+				state.AddOpportunity(testOpportunity("A-1", "Imported"))
+				state.AddOpportunity(testOpportunity("A-2", "Also imported"))
+				if _, err := state.UpdatePipeline("A-1", PipelineChange{Stage: samgov.StageReviewing}); err != nil {
+					t.Fatalf("setting stage: %v", err)
+				}
+				state.IncrementDailyRequests()
+				if err := state.Save(); err != nil {
+					t.Fatalf("saving JSON state: %v", err)
+				}
+			}
+
+			store := openTestStore(t, filepath.Join(dir, "state.db"))
+			if tt.seedDatabase {
+				store.AddOpportunity(testOpportunity("A-1", "Already in SQLite"))
+				if err := store.Save(); err != nil {
+					t.Fatalf("saving store: %v", err)
+				}
+			}
+
+			imported, err := store.ImportJSONIfEmpty(jsonPath, false)
+			if err != nil {
+				t.Fatalf("ImportJSONIfEmpty: %v", err)
+			}
+			if imported != tt.wantImported {
+				t.Errorf("imported = %d, want %d", imported, tt.wantImported)
+			}
+
+			opp, ok := store.GetOpportunity("A-1")
+			if tt.wantTitle == "" {
+				if ok {
+					t.Errorf("A-1 exists with title %q, want none", opp.Title)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("A-1 not found")
+			}
+			if opp.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", opp.Title, tt.wantTitle)
+			}
+			if len(opp.Versions) != 1 {
+				t.Errorf("versions = %d, want 1", len(opp.Versions))
+			}
+
+			if tt.wantImported > 0 {
+				if opp.Stage != samgov.StageReviewing {
+					t.Errorf("stage = %q, want %q", opp.Stage, samgov.StageReviewing)
+				}
+				if count, _ := store.GetDailyRequestCount(); count != 1 {
+					t.Errorf("daily request count = %d, want 1", count)
+				}
+
+				// A second call sees a populated database and does nothing
+				again, err := store.ImportJSONIfEmpty(jsonPath, false)
+				if err != nil || again != 0 {
+					t.Errorf("second import = %d, %v; want 0, nil", again, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteStorePendingChanges(t *testing.T) {
+	reviewing := PipelineChange{Stage: samgov.StageReviewing}
+
+	tests := []struct {
+		name string
+		// run changes the store after A-1 was saved with title "Saved".
+		// other is a second store on the same database, like a concurrent
+		// "track" command.
+		run       func(t *testing.T, store, other *SQLiteStore)
+		save      bool
+		wantTitle string
+		wantStage samgov.Stage
+		wantQuery []string
+		wantCount int // Opportunities listed after reopening
+	}{
+		{
+			name: "unsaved update is discarded on close",
+			run: func(t *testing.T, store, other *SQLiteStore) {
+				store.AddOpportunity(testOpportunity("A-1", "Amended"))
+				store.AddOpportunity(testOpportunity("A-2", "New"))
+			},
+			save:      false,
+			wantTitle: "Saved",
+			wantCount: 1,
+		},
+		{
+			name: "saved update replaces the persisted row",
+			run: func(t *testing.T, store, other *SQLiteStore) {
+				store.AddOpportunity(testOpportunity("A-1", "Amended"))
+				store.AddOpportunity(testOpportunity("A-2", "New"))
+			},
+			save:      true,
+			wantTitle: "Amended",
+			wantCount: 2,
+		},
+		{
+			name: "opportunity saved by another process is kept",
+			run: func(t *testing.T, store, other *SQLiteStore) {
+				store.AddOpportunity(testOpportunity("A-1", "Amended"))
+				other.AddOpportunity(testOpportunity("B-1", "Other"))
+				if err := other.Save(); err != nil {
+					t.Fatalf("saving other store: %v", err)
+				}
+			},
+			save:      true,
+			wantTitle: "Amended",
+			wantCount: 2,
+		},
+		{
+			name: "query match on a persisted opportunity is saved",
+			run: func(t *testing.T, store, other *SQLiteStore) {
+				store.AddQueryMatch("A-1", "Query B")
+			},
+			save:      true,
+			wantTitle: "Saved",
+			wantQuery: []string{"Query A", "Query B"},
+			wantCount: 1,
+		},
+		{
+			name: "stage set by another process survives saving a pending update",
+			run: func(t *testing.T, store, other *SQLiteStore) {
+				store.AddOpportunity(testOpportunity("A-1", "Amended"))
+				if _, err := other.UpdatePipeline("A-1", reviewing); err != nil {
+					t.Fatalf("UpdatePipeline: %v", err)
+				}
+			},
+			save:      true,
+			wantTitle: "Amended",
+			wantStage: samgov.StageReviewing,
+			wantCount: 1,
+		},
+		{
+			name: "stage set on a pending opportunity is written immediately",
+			run: func(t *testing.T, store, other *SQLiteStore) {
+				store.AddOpportunity(testOpportunity("A-1", "Amended"))
+				if _, err := store.UpdatePipeline("A-1", reviewing); err != nil {
+					t.Fatalf("UpdatePipeline: %v", err)
+				}
+			},
+			save:      false,
+			wantTitle: "Saved",
+			wantStage: samgov.StageReviewing,
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.db")
+
+			seed := openTestStore(t, path)
+			seed.AddOpportunity(testOpportunity("A-1", "Saved"))
+			seed.AddQueryMatch("A-1", "Query A")
+			if err := seed.Save(); err != nil {
+				t.Fatalf("saving seed: %v", err)
+			}
+			seed.Close()
+
+			store := openTestStore(t, path)
+			other := openTestStore(t, path)
+			tt.run(t, store, other)
+			if tt.save {
+				if err := store.Save(); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+			}
+			store.Close()
+
+			reopened := openTestStore(t, path)
+			opp, ok := reopened.GetOpportunity("A-1")
+			if !ok {
+				t.Fatalf("A-1 not found after reopening")
+			}
+			if opp.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", opp.Title, tt.wantTitle)
+			}
+			if opp.Stage != tt.wantStage {
+				t.Errorf("stage = %q, want %q", opp.Stage, tt.wantStage)
+			}
+			if tt.wantQuery != nil && !equalStrings(opp.Queries, tt.wantQuery) {
+				t.Errorf("queries = %v, want %v", opp.Queries, tt.wantQuery)
+			}
+			if got := len(reopened.ListOpportunities()); got != tt.wantCount {
+				t.Errorf("listed %d opportunities, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSQLiteStoreListIncludesPendingChanges(t *testing.T) {
+	store := openTestStore(t, filepath.Join(t.TempDir(), "state.db"))
+	store.AddOpportunity(testOpportunity("A-1", "Saved"))
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	store.AddOpportunity(testOpportunity("A-1", "Amended"))
+	store.AddOpportunity(testOpportunity("A-2", "New"))
+
+	titles := make(map[string]string)
+	for _, opp := range store.ListOpportunities() {
+		if _, dup := titles[opp.NoticeID]; dup {
+			t.Errorf("%s listed twice", opp.NoticeID)
+		}
+		titles[opp.NoticeID] = opp.Title
+	}
+	want := map[string]string{"A-1": "Amended", "A-2": "New"}
+	for id, title := range want {
+		if titles[id] != title {
+			t.Errorf("%s title = %q, want %q", id, titles[id], title)
+		}
+	}
+
+	// The amendment adds a version to the one already saved
+	opp, _ := store.GetOpportunity("A-1")
+	if len(opp.Versions) != 2 {
+		t.Errorf("versions = %d, want 2", len(opp.Versions))
+	}
+}
+
+func TestSQLiteStoreRecordAwardMerges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	recorded := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	store := openTestStore(t, path)
+	if !store.RecordAward(samgov.AwardRecord{NoticeID: "W-1", Awardee: "Acme", LinkedNoticeID: "A-1", RecordedAt: recorded}) {
+		t.Errorf("first RecordAward reported an existing award")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// An amended award keeps the first recording time and solicitation link
+	if store.RecordAward(samgov.AwardRecord{NoticeID: "W-1", Awardee: "Acme Corp", RecordedAt: recorded.Add(time.Hour)}) {
+		t.Errorf("second RecordAward reported a new award")
+	}
+	awards := store.ListAwards()
+	if len(awards) != 1 {
+		t.Fatalf("listed %d awards, want 1", len(awards))
+	}
+	got := awards[0]
+	if got.Awardee != "Acme Corp" || got.LinkedNoticeID != "A-1" || !got.RecordedAt.Equal(recorded) {
+		t.Errorf("merged award = %+v", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,6 @@
 package monitor
 
 import (
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,23 +8,29 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
 	"github.com/yourusername/sam-gov-monitor/internal/samgov"
 )
 
 // State manages the persistent state of monitored opportunities
 type State struct {
-	mu            sync.RWMutex
-	Opportunities map[string]samgov.OpportunityState `json:"opportunities"`
-	LastRun       time.Time                          `json:"last_run"`
-	LastSuccessfulQueryTime time.Time                   `json:"last_successful_query_time"`
-	DailyRequestCount       int                        `json:"daily_request_count"`
-	DailyRequestDate        string                     `json:"daily_request_date"`
-	QueryMetrics  map[string]QueryMetrics            `json:"query_metrics"`
-	filepath      string
-	modified      bool
+	mu                      sync.RWMutex
+	Opportunities           map[string]samgov.OpportunityState `json:"opportunities"`
+	LastRun                 time.Time                          `json:"last_run"`
+	LastSuccessfulQueryTime time.Time                          `json:"last_successful_query_time"`
+	DailyRequestCount       int                                `json:"daily_request_count"`
+	DailyRequestDate        string                             `json:"daily_request_date"`
+	RateLimitedUntil        time.Time                          `json:"rate_limited_until,omitempty"`
+	QueryMetrics            map[string]QueryMetrics            `json:"query_metrics"`
+	PendingDigests          []notify.PendingNotification       `json:"pending_digests,omitempty"`
+	LastDigest              time.Time                          `json:"last_digest,omitempty"`
+	Deliveries              []notify.Delivery                  `json:"deliveries,omitempty"`
+	GitHubIssues            map[string]notify.IssueRecord      `json:"github_issues,omitempty"` // Keyed by notify.IssueKey
+	Awards                  map[string]samgov.AwardRecord      `json:"awards,omitempty"`        // Keyed by award notice ID
+	filepath                string
+	modified                bool
 }
 
-
 // LoadState loads the state from a file, or creates a new empty state
 func LoadState(filePath string) (*State, error) {
 	state := &State{
@@ -77,19 +82,23 @@ func LoadState(filePath string) (*State, error) {
 	return state, nil
 }
 
-// Save persists the state to disk
+// Save persists the state to disk. Pipeline changes saved to the file by
+// another process since this state was loaded, such as "track set-stage"
+// during a daemon run, are kept.
 func (s *State) Save() error {
 	if s.filepath == "" {
 		return nil // In-memory only
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if !s.modified {
 		return nil // No changes to save
 	}
 
+	s.mergePipelineFromDisk()
+
 	// Create directory if needed
 	if err := os.MkdirAll(filepath.Dir(s.filepath), 0755); err != nil {
 		return fmt.Errorf("creating state directory: %w", err)
@@ -116,40 +125,96 @@ func (s *State) Save() error {
 	return nil
 }
 
+// mergePipelineFromDisk takes the stage, owner and notes of each
+// opportunity from the state file when they changed there more recently
+// than in memory
+func (s *State) mergePipelineFromDisk() {
+	data, err := os.ReadFile(s.filepath)
+	if err != nil {
+		return
+	}
+	var onDisk struct {
+		Opportunities map[string]samgov.OpportunityState `json:"opportunities"`
+	}
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		return
+	}
+
+	for noticeID, saved := range onDisk.Opportunities {
+		current, exists := s.Opportunities[noticeID]
+		if !exists || !saved.StageUpdated.After(current.StageUpdated) {
+			continue
+		}
+		current.Stage = saved.Stage
+		current.Owner = saved.Owner
+		current.Notes = saved.Notes
+		current.StageUpdated = saved.StageUpdated
+		s.Opportunities[noticeID] = current
+	}
+}
+
 // AddOpportunity adds or updates an opportunity in the state
 func (s *State) AddOpportunity(opp samgov.Opportunity) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now()
-	hash := s.calculateOpportunityHash(opp)
-
-	if existing, exists := s.Opportunities[opp.NoticeID]; exists {
-		// Update existing opportunity
-		existing.LastSeen = now
-		if existing.Hash != hash {
-			existing.LastModified = now
-			existing.Hash = hash
-			existing.Title = opp.Title
-			existing.Deadline = opp.ResponseDeadline
-		}
-		s.Opportunities[opp.NoticeID] = existing
-		s.modified = true
-		return false // Not new
+	existing, exists := s.Opportunities[opp.NoticeID]
+	var previous *samgov.OpportunityState
+	if exists {
+		previous = &existing
 	}
 
-	// Add new opportunity
-	s.Opportunities[opp.NoticeID] = samgov.OpportunityState{
-		FirstSeen:    now,
-		LastSeen:     now,
-		LastModified: now,
-		NoticeID:     opp.NoticeID,
-		Title:        opp.Title,
-		Deadline:     opp.ResponseDeadline,
-		Hash:         hash,
+	s.Opportunities[opp.NoticeID] = mergeOpportunity(previous, opp, time.Now())
+	s.modified = true
+	return !exists
+}
+
+// AddDocuments records downloaded files against a stored opportunity
+func (s *State) AddDocuments(noticeID string, docs []samgov.Document) {
+	if len(docs) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.Opportunities[noticeID]
+	if !exists {
+		return
+	}
+	existing.Documents = append(existing.Documents, docs...)
+	s.Opportunities[noticeID] = existing
+	s.modified = true
+}
+
+// AddQueryMatch records that a query matched a stored opportunity
+func (s *State) AddQueryMatch(noticeID, queryName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.Opportunities[noticeID]
+	if !exists || containsString(existing.Queries, queryName) {
+		return
+	}
+	existing.Queries = append(existing.Queries, queryName)
+	s.Opportunities[noticeID] = existing
+	s.modified = true
+}
+
+// MarkRemindersSent records deadline reminders sent for a stored opportunity
+func (s *State) MarkRemindersSent(noticeID string, reminders []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.Opportunities[noticeID]
+	if !exists {
+		return
+	}
+	for _, reminder := range reminders {
+		existing.RemindersSent = appendUnique(existing.RemindersSent, reminder)
 	}
+	s.Opportunities[noticeID] = existing
 	s.modified = true
-	return true // New opportunity
 }
 
 // GetOpportunity retrieves an opportunity from state
@@ -187,6 +252,171 @@ func (s *State) IncrementDailyRequests() int {
 	return s.DailyRequestCount
 }
 
+// TryIncrementDailyRequests increments the daily request counter only if the
+// limit has not been reached, reporting the new count and whether it was allowed
+func (s *State) TryIncrementDailyRequests(limit int) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := time.Now().UTC().Format("2006-01-02")
+	if s.DailyRequestDate != today {
+		s.DailyRequestDate = today
+		s.DailyRequestCount = 0
+	}
+
+	if limit > 0 && s.DailyRequestCount >= limit {
+		return s.DailyRequestCount, false
+	}
+
+	s.DailyRequestCount++
+	s.modified = true
+	return s.DailyRequestCount, true
+}
+
+// ExhaustDailyRequests marks today's budget as fully used, e.g. when the API
+// reports no requests remaining
+func (s *State) ExhaustDailyRequests(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := time.Now().UTC().Format("2006-01-02")
+	if s.DailyRequestDate != today {
+		s.DailyRequestDate = today
+		s.DailyRequestCount = 0
+	}
+
+	if s.DailyRequestCount < limit {
+		s.DailyRequestCount = limit
+		s.modified = true
+	}
+}
+
+// GetRateLimitedUntil returns when the server-requested back-off ends
+func (s *State) GetRateLimitedUntil() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.RateLimitedUntil
+}
+
+// SetRateLimitedUntil records a server-requested back-off
+func (s *State) SetRateLimitedUntil(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.RateLimitedUntil = t
+	s.modified = true
+}
+
+// GetPendingDigests returns the queued digest notifications
+func (s *State) GetPendingDigests() []notify.PendingNotification {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]notify.PendingNotification(nil), s.PendingDigests...)
+}
+
+// SetPendingDigests replaces the queued digest notifications
+func (s *State) SetPendingDigests(pending []notify.PendingNotification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.PendingDigests = pending
+	s.modified = true
+}
+
+// GetLastDigest returns when the last digest was sent
+func (s *State) GetLastDigest() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.LastDigest
+}
+
+// SetLastDigest records when a digest was sent
+func (s *State) SetLastDigest(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.LastDigest = t
+	s.modified = true
+}
+
+// GetDeliveries returns the notification outbox
+func (s *State) GetDeliveries() []notify.Delivery {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]notify.Delivery(nil), s.Deliveries...)
+}
+
+// SetDeliveries replaces the notification outbox
+func (s *State) SetDeliveries(deliveries []notify.Delivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Deliveries = deliveries
+	s.modified = true
+}
+
+// GetIssue returns the issue opened for a notice in repository
+func (s *State) GetIssue(repository, noticeID string) (notify.IssueRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.GitHubIssues[notify.IssueKey(repository, noticeID)]
+	return record, ok
+}
+
+// SetIssue records the issue opened for a notice
+func (s *State) SetIssue(record notify.IssueRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.GitHubIssues == nil {
+		s.GitHubIssues = make(map[string]notify.IssueRecord)
+	}
+	s.GitHubIssues[notify.IssueKey(record.Repository, record.NoticeID)] = record
+	s.modified = true
+}
+
+// UpdatePipeline changes the capture stage, owner or notes of a stored
+// opportunity
+func (s *State) UpdatePipeline(noticeID string, change PipelineChange) (samgov.OpportunityState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.Opportunities[noticeID]
+	if !exists {
+		return samgov.OpportunityState{}, fmt.Errorf("opportunity %s is not tracked", noticeID)
+	}
+	updated, err := applyPipelineChange(existing, change, time.Now())
+	if err != nil {
+		return existing, err
+	}
+	s.Opportunities[noticeID] = updated
+	s.modified = true
+	return updated, nil
+}
+
+// RecordAward stores an award notice, reporting whether it is new
+func (s *State) RecordAward(record samgov.AwardRecord) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Awards == nil {
+		s.Awards = make(map[string]samgov.AwardRecord)
+	}
+	previous, exists := s.Awards[record.NoticeID]
+	if exists {
+		s.Awards[record.NoticeID] = mergeAward(&previous, record)
+	} else {
+		s.Awards[record.NoticeID] = record
+	}
+	s.modified = true
+	return !exists
+}
+
+// ListAwards returns every recorded award
+func (s *State) ListAwards() []samgov.AwardRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	awards := make([]samgov.AwardRecord, 0, len(s.Awards))
+	for _, record := range s.Awards {
+		awards = append(awards, record)
+	}
+	return awards
+}
+
 // GetDailyRequestCount returns the current daily request count
 func (s *State) GetDailyRequestCount() (int, string) {
 	s.mu.RLock()
@@ -229,33 +459,7 @@ func (s *State) UpdateQueryMetrics(queryName string, executionTime time.Duration
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	metrics, exists := s.QueryMetrics[queryName]
-	if !exists {
-		metrics = QueryMetrics{}
-	}
-
-	metrics.LastExecuted = time.Now()
-	metrics.ExecutionCount++
-	metrics.LastOpportunityCount = opportunityCount
-	metrics.TotalOpportunitiesFound += opportunityCount
-
-	// Update average execution time
-	if metrics.ExecutionCount == 1 {
-		metrics.AverageTime = executionTime
-	} else {
-		// Rolling average
-		total := time.Duration(metrics.ExecutionCount-1) * metrics.AverageTime + executionTime
-		metrics.AverageTime = total / time.Duration(metrics.ExecutionCount)
-	}
-
-	if err != nil {
-		metrics.ErrorCount++
-		metrics.LastError = err.Error()
-	} else {
-		metrics.LastError = ""
-	}
-
-	s.QueryMetrics[queryName] = metrics
+	s.QueryMetrics[queryName] = recordQueryExecution(s.QueryMetrics[queryName], executionTime, opportunityCount, err)
 	s.modified = true
 }
 
@@ -277,7 +481,7 @@ func (s *State) CleanupOldOpportunities(maxAge time.Duration) int {
 	removed := 0
 
 	for noticeID, opp := range s.Opportunities {
-		if opp.LastSeen.Before(cutoff) {
+		if opp.LastSeen.Before(cutoff) && opp.PipelineStage() == samgov.StageNew {
 			delete(s.Opportunities, noticeID)
 			removed++
 		}
@@ -295,37 +499,33 @@ func (s *State) GetStats() StateStats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	stats := StateStats{
-		TotalOpportunities: len(s.Opportunities),
-		LastRun:           s.LastRun,
-		TotalQueries:      len(s.QueryMetrics),
-	}
-
-	// Calculate age distribution
-	now := time.Now()
+	opportunities := make([]samgov.OpportunityState, 0, len(s.Opportunities))
 	for _, opp := range s.Opportunities {
-		daysSince := int(now.Sub(opp.FirstSeen).Hours() / 24)
-		if daysSince <= 7 {
-			stats.OpportunitiesLastWeek++
-		}
-		if daysSince <= 30 {
-			stats.OpportunitiesLastMonth++
-		}
+		opportunities = append(opportunities, opp)
 	}
-
-	// Calculate query success rate
-	totalExecutions := 0
-	successfulExecutions := 0
-	for _, metrics := range s.QueryMetrics {
-		totalExecutions += metrics.ExecutionCount
-		successfulExecutions += metrics.ExecutionCount - metrics.ErrorCount
+	metrics := make([]QueryMetrics, 0, len(s.QueryMetrics))
+	for _, m := range s.QueryMetrics {
+		metrics = append(metrics, m)
 	}
 
-	if totalExecutions > 0 {
-		stats.QuerySuccessRate = float64(successfulExecutions) / float64(totalExecutions)
+	return computeStateStats(opportunities, metrics, s.LastRun)
+}
+
+// ListOpportunities returns a snapshot of every stored opportunity
+func (s *State) ListOpportunities() []samgov.OpportunityState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	opportunities := make([]samgov.OpportunityState, 0, len(s.Opportunities))
+	for _, opp := range s.Opportunities {
+		opportunities = append(opportunities, opp)
 	}
+	return opportunities
+}
 
-	return stats
+// Close is a no-op; the JSON state holds no open resources
+func (s *State) Close() error {
+	return nil
 }
 
 // StateStats provides statistics about the state
@@ -338,26 +538,6 @@ type StateStats struct {
 	QuerySuccessRate        float64   `json:"query_success_rate"`
 }
 
-// calculateOpportunityHash creates a hash of the opportunity content for change detection
-func (s *State) calculateOpportunityHash(opp samgov.Opportunity) string {
-	// Create a string representation of key fields
-	content := fmt.Sprintf("%s|%s|%s|%s|%s", 
-		opp.NoticeID, 
-		opp.Title, 
-		opp.PostedDate,
-		opp.Type,
-		func() string {
-			if opp.ResponseDeadline != nil {
-				return *opp.ResponseDeadline
-			}
-			return ""
-		}(),
-	)
-
-	hash := sha256.Sum256([]byte(content))
-	return fmt.Sprintf("%x", hash)
-}
-
 // ExportToJSON exports the state to a JSON string for backup/debugging
 func (s *State) ExportToJSON() (string, error) {
 	s.mu.RLock()
@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestStateSaveKeepsPipelineChangesOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	seed, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	seed.AddOpportunity(testOpportunity("A-1", "Saved"))
+	seed.AddOpportunity(testOpportunity("A-2", "Saved"))
+	if err := seed.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A daemon loads the state, then "track" changes it on disk
+	daemon, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	track, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	owner := "alice"
+	if _, err := track.UpdatePipeline("A-1", PipelineChange{Stage: samgov.StagePursuing, Owner: &owner}); err != nil {
+		t.Fatalf("UpdatePipeline: %v", err)
+	}
+	if err := track.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// The daemon's own later change to A-2 wins over the older disk copy
+	if _, err := daemon.UpdatePipeline("A-2", PipelineChange{Stage: samgov.StageReviewing}); err != nil {
+		t.Fatalf("UpdatePipeline: %v", err)
+	}
+	daemon.AddOpportunity(testOpportunity("A-1", "Amended"))
+	if err := daemon.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	saved, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	tests := []struct {
+		noticeID  string
+		wantTitle string
+		wantStage samgov.Stage
+		wantOwner string
+	}{
+		{"A-1", "Amended", samgov.StagePursuing, "alice"},
+		{"A-2", "Saved", samgov.StageReviewing, ""},
+	}
+	for _, tt := range tests {
+		opp, ok := saved.GetOpportunity(tt.noticeID)
+		if !ok {
+			t.Fatalf("%s not found", tt.noticeID)
+		}
+		if opp.Title != tt.wantTitle || opp.Stage != tt.wantStage || opp.Owner != tt.wantOwner {
+			t.Errorf("%s = title %q, stage %q, owner %q; want %q, %q, %q",
+				tt.noticeID, opp.Title, opp.Stage, opp.Owner, tt.wantTitle, tt.wantStage, tt.wantOwner)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// Supported state backends
+const (
+	StateBackendJSON   = "json"
+	StateBackendSQLite = "sqlite"
+)
+
+// StateStore persists opportunities, query metrics, the digest queue, the
+// notification outbox, GitHub issue numbers, recorded awards and API request
+// counters between runs. Everything but the request counters becomes durable
+// on Save; request counters are shared with the rate limiter.
+type StateStore interface {
+	samgov.RateLimitStore
+	notify.DigestStore
+	notify.OutboxStore
+	notify.IssueStore
+
+	// AddOpportunity records a sighting of opp, reporting whether it is new
+	AddOpportunity(opp samgov.Opportunity) bool
+	// AddDocuments records downloaded files against a stored opportunity
+	AddDocuments(noticeID string, docs []samgov.Document)
+	// AddQueryMatch records that a query matched a stored opportunity
+	AddQueryMatch(noticeID, queryName string)
+	// MarkRemindersSent records deadline reminders sent for a stored opportunity
+	MarkRemindersSent(noticeID string, reminders []string)
+	// UpdatePipeline changes the capture stage, owner or notes of a stored
+	// opportunity and returns it as updated
+	UpdatePipeline(noticeID string, change PipelineChange) (samgov.OpportunityState, error)
+	// RecordAward stores an award notice, reporting whether it is new
+	RecordAward(record samgov.AwardRecord) bool
+	ListAwards() []samgov.AwardRecord
+	GetOpportunity(noticeID string) (samgov.OpportunityState, bool)
+	ListOpportunities() []samgov.OpportunityState
+	// CleanupOldOpportunities removes opportunities not seen within maxAge,
+	// keeping those that have moved into the capture pipeline
+	CleanupOldOpportunities(maxAge time.Duration) int
+
+	SetLastRun(t time.Time)
+	GetLastRun() time.Time
+	SetLastSuccessfulQuery(t time.Time)
+	GetLastSuccessfulQuery() time.Time
+
+	UpdateQueryMetrics(queryName string, executionTime time.Duration, opportunityCount int, err error)
+	GetQueryMetrics(queryName string) (QueryMetrics, bool)
+	GetStats() StateStats
+
+	// Save writes all pending changes in a single transaction
+	Save() error
+	Close() error
+}
+
+var (
+	_ StateStore = (*State)(nil)
+	_ StateStore = (*SQLiteStore)(nil)
+)
+
+// OpenStateStore opens the state for backend. statePath is the JSON state
+// file; the SQLite database lives beside it with a .db extension and is
+// seeded from the JSON file the first time it is opened.
+func OpenStateStore(backend, statePath string, verbose bool) (StateStore, error) {
+	switch backend {
+	case "", StateBackendJSON:
+		return LoadState(statePath)
+	case StateBackendSQLite:
+		if statePath == "" {
+			return nil, fmt.Errorf("sqlite state backend requires a state file path")
+		}
+		store, err := OpenSQLiteStore(SQLitePath(statePath))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := store.ImportJSONIfEmpty(statePath, verbose); err != nil {
+			store.Close()
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown state backend %q (want %s or %s)", backend, StateBackendJSON, StateBackendSQLite)
+	}
+}
+
+// SQLitePath returns the database path used for a JSON state file path
+func SQLitePath(statePath string) string {
+	return strings.TrimSuffix(statePath, filepath.Ext(statePath)) + ".db"
+}
+
+// mergeOpportunity applies a sighting of opp to its stored state, or creates
+// the state when previous is nil. A version is appended whenever a compared
+// field differs from the latest recorded version.
+func mergeOpportunity(previous *samgov.OpportunityState, opp samgov.Opportunity, now time.Time) samgov.OpportunityState {
+	hash := calculateOpportunityHash(opp)
+	snapshot := samgov.Snapshot(opp)
+
+	if previous == nil {
+		return samgov.OpportunityState{
+			FirstSeen:            now,
+			LastSeen:             now,
+			LastModified:         now,
+			NoticeID:             opp.NoticeID,
+			Title:                opp.Title,
+			Deadline:             opp.ResponseDeadline,
+			Hash:                 hash,
+			ResourceLinks:        samgov.NewResourceLinks(nil, opp.ResourceLinks),
+			ResourceLinksTracked: true,
+			Versions:             []samgov.OpportunityVersion{{ObservedAt: now, Snapshot: snapshot}},
+		}
+	}
+
+	existing := *previous
+	existing.LastSeen = now
+	if existing.Hash != hash {
+		existing.LastModified = now
+		existing.Hash = hash
+		existing.Title = opp.Title
+		existing.Deadline = opp.ResponseDeadline
+	}
+	if added := samgov.NewResourceLinks(existing.ResourceLinks, opp.ResourceLinks); len(added) > 0 {
+		if existing.ResourceLinksTracked {
+			existing.LastModified = now
+		}
+		existing.ResourceLinks = append(existing.ResourceLinks, added...)
+	}
+	existing.ResourceLinksTracked = true
+
+	// Entries saved before versions were recorded start their history now
+	latest, hasVersions := existing.LatestVersion()
+	if hasVersions && snapshot.DescriptionHash == "" {
+		// The description is not fetched on every run; keep the last known text
+		snapshot.DescriptionHash = latest.Snapshot.DescriptionHash
+	}
+	if !hasVersions || len(latest.Snapshot.Diff(snapshot)) > 0 {
+		if hasVersions {
+			existing.LastModified = now
+		}
+		existing.Versions = append(existing.Versions, samgov.OpportunityVersion{ObservedAt: now, Snapshot: snapshot})
+	}
+	return existing
+}
+
+// opportunityChanges lists the fields of current that differ from the latest
+// stored version. Entries saved before versions were recorded fall back to
+// comparing the title, deadline and resource links.
+func opportunityChanges(previous samgov.OpportunityState, current samgov.Opportunity) []samgov.FieldChange {
+	snapshot := samgov.Snapshot(current)
+	if latest, ok := previous.LatestVersion(); ok {
+		return latest.Snapshot.Diff(snapshot)
+	}
+
+	legacy := samgov.OpportunitySnapshot{Title: previous.Title, ResourceLinks: snapshot.ResourceLinks}
+	if previous.Deadline != nil {
+		legacy.ResponseDeadline = *previous.Deadline
+	}
+	if previous.ResourceLinksTracked {
+		legacy.ResourceLinks = previous.ResourceLinks
+	}
+
+	return legacy.Diff(samgov.OpportunitySnapshot{
+		Title:            snapshot.Title,
+		ResponseDeadline: snapshot.ResponseDeadline,
+		ResourceLinks:    snapshot.ResourceLinks,
+	})
+}
+
+// PipelineChange updates a tracked opportunity's place in the capture
+// pipeline. Nil fields are left as they are.
+type PipelineChange struct {
+	Stage samgov.Stage // Empty keeps the current stage
+	Owner *string
+	Notes *string
+	Force bool // Allow a move the pipeline does not, such as reopening a lost bid
+}
+
+// applyPipelineChange checks a stage move against the pipeline and applies
+// the change
+func applyPipelineChange(existing samgov.OpportunityState, change PipelineChange, now time.Time) (samgov.OpportunityState, error) {
+	if change.Stage != "" {
+		current := existing.PipelineStage()
+		if !change.Force && !current.CanMoveTo(change.Stage) {
+			next := make([]string, 0)
+			for _, stage := range current.NextStages() {
+				next = append(next, string(stage))
+			}
+			allowed := "none, it is final"
+			if len(next) > 0 {
+				allowed = strings.Join(next, ", ")
+			}
+			return existing, fmt.Errorf("cannot move %s from %s to %s (allowed: %s)", existing.NoticeID, current, change.Stage, allowed)
+		}
+		existing.Stage = change.Stage
+	}
+	if change.Owner != nil {
+		existing.Owner = strings.TrimSpace(*change.Owner)
+	}
+	if change.Notes != nil {
+		existing.Notes = *change.Notes
+	}
+	existing.StageUpdated = now
+	return existing, nil
+}
+
+// mergeAward applies a later sighting of an award notice to its record. The
+// first recording time is kept, and so is the link to a tracked solicitation
+// that has since been forgotten.
+func mergeAward(previous *samgov.AwardRecord, record samgov.AwardRecord) samgov.AwardRecord {
+	if previous == nil {
+		return record
+	}
+	record.RecordedAt = previous.RecordedAt
+	if record.LinkedNoticeID == "" {
+		record.LinkedNoticeID = previous.LinkedNoticeID
+	}
+	return record
+}
+
+// recordQueryExecution folds one query execution into its metrics
+func recordQueryExecution(metrics QueryMetrics, executionTime time.Duration, opportunityCount int, err error) QueryMetrics {
+	metrics.LastExecuted = time.Now()
+	metrics.ExecutionCount++
+	metrics.LastOpportunityCount = opportunityCount
+	metrics.TotalOpportunitiesFound += opportunityCount
+
+	// Update average execution time
+	if metrics.ExecutionCount == 1 {
+		metrics.AverageTime = executionTime
+	} else {
+		// Rolling average
+		total := time.Duration(metrics.ExecutionCount-1)*metrics.AverageTime + executionTime
+		metrics.AverageTime = total / time.Duration(metrics.ExecutionCount)
+	}
+
+	if err != nil {
+		metrics.ErrorCount++
+		metrics.LastError = err.Error()
+	} else {
+		metrics.LastError = ""
+	}
+
+	return metrics
+}
+
+// computeStateStats summarizes stored opportunities and query metrics
+func computeStateStats(opportunities []samgov.OpportunityState, metrics []QueryMetrics, lastRun time.Time) StateStats {
+	stats := StateStats{
+		TotalOpportunities: len(opportunities),
+		LastRun:            lastRun,
+		TotalQueries:       len(metrics),
+	}
+
+	// Calculate age distribution
+	now := time.Now()
+	for _, opp := range opportunities {
+		daysSince := int(now.Sub(opp.FirstSeen).Hours() / 24)
+		if daysSince <= 7 {
+			stats.OpportunitiesLastWeek++
+		}
+		if daysSince <= 30 {
+			stats.OpportunitiesLastMonth++
+		}
+	}
+
+	// Calculate query success rate
+	totalExecutions := 0
+	successfulExecutions := 0
+	for _, m := range metrics {
+		totalExecutions += m.ExecutionCount
+		successfulExecutions += m.ExecutionCount - m.ErrorCount
+	}
+
+	if totalExecutions > 0 {
+		stats.QuerySuccessRate = float64(successfulExecutions) / float64(totalExecutions)
+	}
+
+	return stats
+}
+
+// calculateOpportunityHash creates a hash of the opportunity content for change detection
+func calculateOpportunityHash(opp samgov.Opportunity) string {
+	// Create a string representation of key fields
+	content := fmt.Sprintf("%s|%s|%s|%s|%s",
+		opp.NoticeID,
+		opp.Title,
+		opp.PostedDate,
+		opp.Type,
+		func() string {
+			if opp.ResponseDeadline != nil {
+				return *opp.ResponseDeadline
+			}
+			return ""
+		}(),
+	)
+
+	hash := sha256.Sum256([]byte(content))
+	return fmt.Sprintf("%x", hash)
+}
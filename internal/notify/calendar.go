@@ -46,8 +46,8 @@ func (cg *CalendarGenerator) GenerateICS(opportunities []samgov.Opportunity, que
 		}
 
 		// Parse deadline
-		deadline, err := time.Parse("2006-01-02", *opp.ResponseDeadline)
-		if err != nil {
+		deadline, ok := samgov.ParseDate(*opp.ResponseDeadline)
+		if !ok {
 			continue
 		}
 
@@ -189,13 +189,17 @@ func (cg *CalendarGenerator) addDeadlineAlarms(ics *strings.Builder) {
 // GenerateDeadlineOnlyICS creates a simplified ICS with only deadline events
 func (cg *CalendarGenerator) GenerateDeadlineOnlyICS(opportunities []samgov.Opportunity, queryName string) string {
 	var ics strings.Builder
-	
+
 	// iCalendar header
 	ics.WriteString("BEGIN:VCALENDAR\r\n")
 	ics.WriteString("VERSION:2.0\r\n")
 	ics.WriteString("PRODID:-//SAM.gov Monitor//Deadlines Only//EN\r\n")
 	ics.WriteString("CALSCALE:GREGORIAN\r\n")
+	ics.WriteString("METHOD:PUBLISH\r\n")
 	ics.WriteString(fmt.Sprintf("X-WR-CALNAME:%s Deadlines\r\n", queryName))
+	// Subscribed calendars poll for changes at this interval
+	ics.WriteString("REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n")
+	ics.WriteString("X-PUBLISHED-TTL:PT1H\r\n")
 
 	now := time.Now()
 	
@@ -204,8 +208,8 @@ func (cg *CalendarGenerator) GenerateDeadlineOnlyICS(opportunities []samgov.Oppo
 			continue
 		}
 
-		deadline, err := time.Parse("2006-01-02", *opp.ResponseDeadline)
-		if err != nil || deadline.Before(now) {
+		deadline, ok := samgov.ParseDate(*opp.ResponseDeadline)
+		if !ok || deadline.Before(now) {
 			continue
 		}
 
@@ -254,12 +258,12 @@ func (cg *CalendarGenerator) GetUpcomingDeadlines(opportunities []samgov.Opportu
 		if opp.ResponseDeadline == nil {
 			continue
 		}
-		
-		deadline, err := time.Parse("2006-01-02", *opp.ResponseDeadline)
-		if err != nil {
+
+		deadline, ok := samgov.ParseDate(*opp.ResponseDeadline)
+		if !ok {
 			continue
 		}
-		
+
 		if deadline.After(now) && deadline.Before(cutoff) {
 			upcoming = append(upcoming, opp)
 		}
@@ -287,9 +291,8 @@ func (cg *CalendarGenerator) escapeText(text string) string {
 
 // ValidateDeadlineFormat checks if deadline string is in correct format
 func (cg *CalendarGenerator) ValidateDeadlineFormat(deadline string) error {
-	_, err := time.Parse("2006-01-02", deadline)
-	if err != nil {
-		return fmt.Errorf("invalid deadline format '%s', expected YYYY-MM-DD", deadline)
+	if _, ok := samgov.ParseDate(deadline); !ok {
+		return fmt.Errorf("invalid deadline format '%s', expected YYYY-MM-DD or RFC 3339", deadline)
 	}
 	return nil
 }
@@ -315,12 +318,12 @@ func (cg *CalendarGenerator) GetDeadlineStats(opportunities []samgov.Opportunity
 		if opp.ResponseDeadline == nil {
 			continue
 		}
-		
-		deadline, err := time.Parse("2006-01-02", *opp.ResponseDeadline)
-		if err != nil {
+
+		deadline, ok := samgov.ParseDate(*opp.ResponseDeadline)
+		if !ok {
 			continue
 		}
-		
+
 		stats.Total++
 		daysUntil := int(deadline.Sub(now).Hours() / 24)
 		
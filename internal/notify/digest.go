@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/yourusername/sam-gov-monitor/internal/samgov"
@@ -13,9 +14,18 @@ import (
 // DigestManager handles notification grouping and batching
 type DigestManager struct {
 	notifications []PendingNotification
+	store         DigestStore
 	verbose       bool
 }
 
+// DigestStore persists the digest queue between runs
+type DigestStore interface {
+	GetPendingDigests() []PendingNotification
+	SetPendingDigests(pending []PendingNotification)
+	GetLastDigest() time.Time
+	SetLastDigest(t time.Time)
+}
+
 // PendingNotification represents a notification waiting to be sent
 type PendingNotification struct {
 	Notification Notification  `json:"notification"`
@@ -32,17 +42,33 @@ func NewDigestManager(verbose bool) *DigestManager {
 	}
 }
 
-// AddNotification adds a notification to the digest queue
+// SetStore loads the queue saved in store and keeps it up to date from now on
+func (dm *DigestManager) SetStore(store DigestStore) {
+	dm.store = store
+	dm.notifications = append(make([]PendingNotification, 0), store.GetPendingDigests()...)
+}
+
+// persist writes the queue back to the store, if there is one
+func (dm *DigestManager) persist() {
+	if dm.store != nil {
+		dm.store.SetPendingDigests(append([]PendingNotification(nil), dm.notifications...))
+	}
+}
+
+// AddNotification adds a notification to the digest queue. Attachments are
+// not queued; the digest carries its own calendar.
 func (dm *DigestManager) AddNotification(notification Notification) {
+	notification.Attachments = nil
 	pending := PendingNotification{
 		Notification: notification,
 		QueryName:    notification.QueryName,
 		Priority:     notification.Priority,
 		CreatedAt:    time.Now(),
 	}
-	
+
 	dm.notifications = append(dm.notifications, pending)
-	
+	dm.persist()
+
 	if dm.verbose {
 		log.Printf("Added notification to digest queue: %s (priority: %s)", 
 			notification.QueryName, notification.Priority)
@@ -69,118 +95,201 @@ func (dm *DigestManager) ShouldSendImmediately(notification Notification) bool {
 	return false
 }
 
-// ProcessDigest creates digest notifications and clears the queue
+// ProcessDigest sends one digest per group and removes what was sent from
+// the queue. A group that fails to send stays queued for the next attempt.
 func (dm *DigestManager) ProcessDigest(ctx context.Context, notifyMgr *NotificationManager) error {
 	if len(dm.notifications) == 0 {
 		return nil
 	}
-	
+
 	if dm.verbose {
 		log.Printf("Processing digest with %d pending notifications", len(dm.notifications))
 	}
-	
-	// Group notifications by priority and query
-	groups := dm.groupNotifications()
-	
+
+	// Group notifications by query and priority
+	groups, keys := dm.groupNotifications()
+
 	// Send digest for each group
-	for groupKey, notifications := range groups {
+	remaining := make([]PendingNotification, 0)
+	var firstErr error
+	for _, groupKey := range keys {
+		notifications := groups[groupKey]
+		if firstErr != nil {
+			remaining = append(remaining, notifications...)
+			continue
+		}
+
 		digest, err := dm.createDigestNotification(groupKey, notifications)
-		if err != nil {
-			return fmt.Errorf("creating digest for %s: %w", groupKey, err)
+		if err == nil {
+			err = notifyMgr.SendNotification(ctx, digest)
 		}
-		
-		if err := notifyMgr.SendNotification(ctx, digest); err != nil {
-			return fmt.Errorf("sending digest for %s: %w", groupKey, err)
+		if IsQueuedForRetry(err) {
+			// The outbox resends the digest to the channels that failed
+			log.Printf("Digest for %s failed on some channels and will be retried: %v", groupKey, err)
+			continue
 		}
-		
+		if err != nil {
+			firstErr = fmt.Errorf("sending digest for %s: %w", groupKey, err)
+			remaining = append(remaining, notifications...)
+			continue
+		}
+
 		if dm.verbose {
 			log.Printf("Sent digest notification for %s with %d items", groupKey, len(notifications))
 		}
 	}
-	
-	// Clear the queue
-	dm.notifications = dm.notifications[:0]
-	
-	return nil
+
+	dm.notifications = remaining
+	dm.persist()
+	if firstErr == nil && dm.store != nil {
+		dm.store.SetLastDigest(time.Now())
+	}
+
+	return firstErr
 }
 
-// groupNotifications groups pending notifications by priority and query type
-func (dm *DigestManager) groupNotifications() map[string][]PendingNotification {
+// groupNotifications groups pending notifications by query, then by
+// priority, whether they report new or updated opportunities, and the
+// channels they go to. Each group is ordered by creation time. Keys are
+// returned with high priority first, then by query name.
+func (dm *DigestManager) groupNotifications() (map[string][]PendingNotification, []string) {
 	groups := make(map[string][]PendingNotification)
-	
+
 	for _, pending := range dm.notifications {
-		// Group by priority level
-		groupKey := fmt.Sprintf("%s-priority", string(pending.Priority))
-		
-		if groups[groupKey] == nil {
-			groups[groupKey] = make([]PendingNotification, 0)
+		groupKey := fmt.Sprintf("%s: %s-priority", pending.QueryName, string(pending.Priority))
+		if pending.Notification.Summary.UpdatedOpportunities > 0 {
+			groupKey += "-updated"
 		}
-		
+		if len(pending.Notification.Channels) > 0 {
+			channels := append([]string(nil), pending.Notification.Channels...)
+			sort.Strings(channels)
+			groupKey += "-" + strings.Join(channels, ",")
+		}
+
 		groups[groupKey] = append(groups[groupKey], pending)
 	}
-	
-	// Sort each group by creation time (oldest first)
+
 	for _, group := range groups {
-		sort.Slice(group, func(i, j int) bool {
+		sort.SliceStable(group, func(i, j int) bool {
 			return group[i].CreatedAt.Before(group[j].CreatedAt)
 		})
 	}
-	
-	return groups
+
+	rank := map[Priority]int{PriorityHigh: 0, PriorityMedium: 1, PriorityLow: 2}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		gi, gj := groups[keys[i]][0], groups[keys[j]][0]
+		if rank[gi.Priority] != rank[gj.Priority] {
+			return rank[gi.Priority] < rank[gj.Priority]
+		}
+		if gi.QueryName != gj.QueryName {
+			return gi.QueryName < gj.QueryName
+		}
+		return keys[i] < keys[j]
+	})
+
+	return groups, keys
 }
 
-// createDigestNotification creates a digest notification from grouped notifications
+// createDigestNotification combines a group into one notification. A notice
+// queued more than once appears once, in its latest version, listing every
+// query that matched it.
 func (dm *DigestManager) createDigestNotification(groupKey string, notifications []PendingNotification) (Notification, error) {
 	if len(notifications) == 0 {
 		return Notification{}, fmt.Errorf("empty notification group")
 	}
-	
-	// Extract priority from group key
-	priority := PriorityMedium
-	if len(notifications) > 0 {
-		priority = notifications[0].Priority
-	}
-	
+
+	priority := notifications[0].Priority
+	updated := notifications[0].Notification.Summary.UpdatedOpportunities > 0
+
 	// Collect all opportunities
 	allOpportunities := make([]samgov.Opportunity, 0)
+	position := make(map[string]int)
+	matchedBy := make(map[string][]string)
 	queryNames := make(map[string]bool)
-	totalNew := 0
-	totalUpdated := 0
-	
+	recipients := make([]string, 0)
+
 	for _, pending := range notifications {
-		allOpportunities = append(allOpportunities, pending.Notification.Opportunities...)
+		for _, opp := range pending.Notification.Opportunities {
+			if i, seen := position[opp.NoticeID]; seen {
+				allOpportunities[i] = opp
+			} else {
+				position[opp.NoticeID] = len(allOpportunities)
+				allOpportunities = append(allOpportunities, opp)
+			}
+
+			names := pending.Notification.MatchedBy[opp.NoticeID]
+			if len(names) == 0 {
+				names = []string{pending.QueryName}
+			}
+			for _, name := range names {
+				matchedBy[opp.NoticeID] = appendIfMissing(matchedBy[opp.NoticeID], name)
+			}
+		}
 		queryNames[pending.QueryName] = true
-		totalNew += pending.Notification.Summary.NewOpportunities
-		totalUpdated += pending.Notification.Summary.UpdatedOpportunities
+		for _, recipient := range pending.Notification.Recipients {
+			recipients = appendIfMissing(recipients, recipient)
+		}
 	}
-	
+
 	// Create query names list
 	queries := make([]string, 0, len(queryNames))
 	for name := range queryNames {
 		queries = append(queries, name)
 	}
 	sort.Strings(queries)
-	
+
+	newCount, updatedCount := len(allOpportunities), 0
+	if updated {
+		newCount, updatedCount = 0, len(allOpportunities)
+	}
+
+	// The digest keeps the queries' template only when they all name the same
+	// one; otherwise the channel's template applies
+	template := notifications[0].Notification.Template
+	for _, pending := range notifications {
+		if pending.Notification.Template != template {
+			template = ""
+		}
+	}
+
 	// Build digest subject
-	subject := dm.buildDigestSubject(priority, totalNew, totalUpdated, queries)
-	
+	subject := dm.buildDigestSubject(priority, newCount, updatedCount, queries)
+
 	// Build digest notification
-	digestNotification := NewNotificationBuilder().
-		WithQuery(fmt.Sprintf("Digest (%s)", groupKey), priority).
-		WithOpportunities(allOpportunities).
+	builder := NewNotificationBuilder().
+		WithQuery(fmt.Sprintf("Digest (%s)", joinQueries(queries)), priority).
+		WithRecipients(recipients).
+		WithChannels(notifications[0].Notification.Channels).
+		WithTemplate(template).
+		WithMatchedBy(matchedBy).
 		WithSubject(subject).
 		WithMetadata("digest", true).
+		WithMetadata("digest_group", groupKey).
 		WithMetadata("query_count", len(queries)).
-		WithMetadata("notification_count", len(notifications)).
-		Build()
-	
+		WithMetadata("notification_count", len(notifications))
+	if updated {
+		builder = builder.WithUpdatedOpportunities(allOpportunities)
+	} else {
+		builder = builder.WithOpportunities(allOpportunities)
+	}
+	digestNotification := builder.Build()
+
 	// Update summary with correct counts
 	digestNotification.Summary = NotificationSummary{
-		NewOpportunities:     totalNew,
-		UpdatedOpportunities: totalUpdated,
+		NewOpportunities:     newCount,
+		UpdatedOpportunities: updatedCount,
 		UpcomingDeadlines:    dm.countUpcomingDeadlines(allOpportunities),
 	}
-	
+
+	calGen := NewCalendarGenerator(dm.verbose)
+	if len(calGen.GetUpcomingDeadlines(allOpportunities, 365)) > 0 {
+		digestNotification.Attachments = []Attachment{calGen.CreateCalendarAttachment(allOpportunities, "Digest")}
+	}
+
 	return digestNotification, nil
 }
 
@@ -194,9 +303,9 @@ func (dm *DigestManager) buildDigestSubject(priority Priority, newCount, updated
 	}
 	
 	totalCount := newCount + updatedCount
-	
-	subject := fmt.Sprintf("%s Daily Digest: %d SAM.gov Opportunities", emoji, totalCount)
-	
+
+	subject := fmt.Sprintf("%s Digest: %d SAM.gov Opportunities", emoji, totalCount)
+
 	if len(queries) == 1 {
 		subject += fmt.Sprintf(" - %s", queries[0])
 	} else if len(queries) <= 3 {
@@ -272,6 +381,7 @@ func (dm *DigestManager) GetPendingByPriority() map[Priority]int {
 // ClearPending removes all pending notifications (useful for testing)
 func (dm *DigestManager) ClearPending() {
 	dm.notifications = dm.notifications[:0]
+	dm.persist()
 }
 
 // GetOldestPending returns the creation time of the oldest pending notification
@@ -304,12 +414,18 @@ func (dm *DigestManager) ShouldProcessDigest(maxAge time.Duration) bool {
 	return time.Since(*oldest) >= maxAge
 }
 
+// DigestSchedule decides when the next digest is due
+type DigestSchedule interface {
+	Next(t time.Time) time.Time
+}
+
 // Enhanced notification manager with digest support
 type DigestNotificationManager struct {
 	*NotificationManager
 	digest       *DigestManager
 	digestMode   bool
 	digestMaxAge time.Duration
+	schedule     DigestSchedule
 }
 
 // NewDigestNotificationManager creates a notification manager with digest support
@@ -322,21 +438,65 @@ func NewDigestNotificationManager(config NotificationConfig, verbose bool, diges
 	}
 }
 
+// SetDigestStore persists the digest queue in store, loading anything
+// queued by an earlier run
+func (dnm *DigestNotificationManager) SetDigestStore(store DigestStore) {
+	dnm.digest.SetStore(store)
+}
+
+// SetDigestSchedule sends digests on a schedule instead of once the oldest
+// queued notification reaches the maximum age
+func (dnm *DigestNotificationManager) SetDigestSchedule(schedule DigestSchedule) {
+	dnm.schedule = schedule
+}
+
+// QueueNotification adds a notification to the digest queue unless it
+// should go out immediately, in which case it is sent now
+func (dnm *DigestNotificationManager) QueueNotification(ctx context.Context, notification Notification) error {
+	if dnm.digest.ShouldSendImmediately(notification) {
+		return dnm.NotificationManager.SendNotification(ctx, notification)
+	}
+
+	dnm.digest.AddNotification(notification)
+	return nil
+}
+
+// DigestDue reports whether queued notifications should be sent at now.
+// With a schedule, a digest is due once the first scheduled time after the
+// previous digest has passed.
+func (dnm *DigestNotificationManager) DigestDue(now time.Time) bool {
+	if dnm.digest.GetPendingCount() == 0 {
+		return false
+	}
+	if dnm.schedule == nil || dnm.digest.store == nil {
+		return dnm.digest.ShouldProcessDigest(dnm.digestMaxAge)
+	}
+
+	last := dnm.digest.store.GetLastDigest()
+	if last.IsZero() {
+		// Never sent: count from the oldest queued notification
+		last = *dnm.digest.GetOldestPending()
+	}
+	next := dnm.schedule.Next(last)
+	return !next.IsZero() && !now.Before(next)
+}
+
 // SendNotificationWithDigest sends notification immediately or adds to digest queue
 func (dnm *DigestNotificationManager) SendNotificationWithDigest(ctx context.Context, notification Notification) error {
-	if !dnm.digestMode || dnm.digest.ShouldSendImmediately(notification) {
+	if !dnm.digestMode {
 		// Send immediately
 		return dnm.NotificationManager.SendNotification(ctx, notification)
 	}
-	
-	// Add to digest queue
-	dnm.digest.AddNotification(notification)
-	
+
+	if err := dnm.QueueNotification(ctx, notification); err != nil {
+		return err
+	}
+
 	// Check if we should process digest now
-	if dnm.digest.ShouldProcessDigest(dnm.digestMaxAge) {
+	if dnm.DigestDue(time.Now()) {
 		return dnm.digest.ProcessDigest(ctx, dnm.NotificationManager)
 	}
-	
+
 	return nil
 }
 
@@ -385,6 +545,15 @@ func joinQueries(queries []string) string {
 			result += query
 		}
 	}
-	
+
 	return result
-}
\ No newline at end of file
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
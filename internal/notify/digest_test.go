@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+// memoryDigestStore keeps the digest queue in memory
+type memoryDigestStore struct {
+	pending    []PendingNotification
+	lastDigest time.Time
+}
+
+func (s *memoryDigestStore) GetPendingDigests() []PendingNotification { return s.pending }
+
+func (s *memoryDigestStore) SetPendingDigests(pending []PendingNotification) { s.pending = pending }
+
+func (s *memoryDigestStore) GetLastDigest() time.Time { return s.lastDigest }
+
+func (s *memoryDigestStore) SetLastDigest(t time.Time) { s.lastDigest = t }
+
+// weeklySchedule is due at hour:00 UTC on the given weekday, or every day
+// when daily is set
+type weeklySchedule struct {
+	weekday time.Weekday
+	hour    int
+	daily   bool
+}
+
+func (s weeklySchedule) Next(t time.Time) time.Time {
+	t = t.UTC()
+	next := time.Date(t.Year(), t.Month(), t.Day(), s.hour, 0, 0, 0, time.UTC)
+	for !next.After(t) || (!s.daily && next.Weekday() != s.weekday) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// neverSchedule has no next run
+type neverSchedule struct{}
+
+func (neverSchedule) Next(t time.Time) time.Time { return time.Time{} }
+
+func TestDigestDue(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC) // 2024-03-04 is a Monday
+	}
+	daily := weeklySchedule{hour: 8, daily: true}
+	weekly := weeklySchedule{weekday: time.Monday, hour: 8}
+
+	tests := []struct {
+		name       string
+		schedule   DigestSchedule
+		queued     time.Time // Zero leaves the queue empty
+		lastDigest time.Time
+		now        time.Time
+		want       bool
+	}{
+		{"empty queue", daily, time.Time{}, at(4, 8, 0), at(6, 8, 0), false},
+		{"before the next daily time", daily, at(4, 12, 0), at(4, 8, 0), at(5, 7, 59), false},
+		{"at the next daily time", daily, at(4, 12, 0), at(4, 8, 0), at(5, 8, 0), true},
+		{"after the next daily time", daily, at(4, 12, 0), at(4, 8, 0), at(5, 19, 0), true},
+		{"several daily times missed", daily, at(4, 12, 0), at(1, 8, 0), at(5, 6, 0), true},
+		{"already sent today", daily, at(5, 9, 0), at(5, 8, 0), at(5, 23, 0), false},
+		{"never sent, before the first time after queueing", daily, at(4, 10, 0), time.Time{}, at(5, 7, 0), false},
+		{"never sent, at the first time after queueing", daily, at(4, 10, 0), time.Time{}, at(5, 8, 0), true},
+		{"never sent, queued before that day's time", daily, at(5, 6, 0), time.Time{}, at(5, 8, 0), true},
+		{"weekly, later in the week", weekly, at(5, 12, 0), at(4, 8, 0), at(10, 23, 0), false},
+		{"weekly, on the next Monday", weekly, at(5, 12, 0), at(4, 8, 0), at(11, 8, 0), true},
+		{"weekly, Monday before the time", weekly, at(5, 12, 0), at(4, 8, 0), at(11, 7, 0), false},
+		{"no next time", neverSchedule{}, at(4, 12, 0), at(4, 8, 0), at(30, 8, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryDigestStore{lastDigest: tt.lastDigest}
+			if !tt.queued.IsZero() {
+				store.pending = []PendingNotification{{QueryName: "Cloud", Priority: PriorityLow, CreatedAt: tt.queued}}
+			}
+			manager := NewDigestNotificationManager(NotificationConfig{}, false, true)
+			manager.SetDigestStore(store)
+			manager.SetDigestSchedule(tt.schedule)
+
+			if got := manager.DigestDue(tt.now); got != tt.want {
+				t.Errorf("DigestDue(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupNotifications(t *testing.T) {
+	base := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	pending := func(query string, priority Priority, minute int, updated bool, channels ...string) PendingNotification {
+		notification := Notification{QueryName: query, Priority: priority, Channels: channels}
+		if updated {
+			notification.Summary.UpdatedOpportunities = 1
+		}
+		return PendingNotification{
+			Notification: notification,
+			QueryName:    query,
+			Priority:     priority,
+			CreatedAt:    base.Add(time.Duration(minute) * time.Minute),
+		}
+	}
+
+	dm := NewDigestManager(false)
+	dm.notifications = []PendingNotification{
+		pending("Zero Trust", PriorityLow, 3, false),
+		pending("Cloud", PriorityLow, 2, false),
+		pending("Zero Trust", PriorityLow, 1, false),
+		pending("Cloud", PriorityMedium, 4, false),
+		pending("Cloud", PriorityLow, 5, true),
+		pending("Cloud", PriorityLow, 6, false, "slack", "email"),
+		pending("Cloud", PriorityLow, 0, false, "email", "slack"),
+	}
+
+	groups, keys := dm.groupNotifications()
+
+	want := []struct {
+		query   string
+		minutes []int // Creation times of the group's entries, in order
+	}{
+		{"Cloud", []int{4}},    // Medium priority first
+		{"Cloud", []int{2}},    // Low, new, default channels
+		{"Cloud", []int{0, 6}}, // Low, new, email and slack in either order
+		{"Cloud", []int{5}},    // Low, updated
+		{"Zero Trust", []int{1, 3}},
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("got %d groups %v, want %d", len(keys), keys, len(want))
+	}
+	for i, w := range want {
+		group := groups[keys[i]]
+		if len(group) != len(w.minutes) {
+			t.Errorf("group %s has %d entries, want %d", keys[i], len(group), len(w.minutes))
+			continue
+		}
+		for j, entry := range group {
+			if entry.QueryName != w.query {
+				t.Errorf("group %s mixes in query %q, want only %q", keys[i], entry.QueryName, w.query)
+			}
+			if minute := int(entry.CreatedAt.Sub(base).Minutes()); minute != w.minutes[j] {
+				t.Errorf("group %s entry %d created at minute %d, want %d", keys[i], j, minute, w.minutes[j])
+			}
+		}
+	}
+}
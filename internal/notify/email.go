@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"html/template"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 
@@ -16,18 +20,20 @@ import (
 
 // EmailNotifier implements email notifications via SMTP
 type EmailNotifier struct {
-	config   EmailConfig
-	verbose  bool
+	templateSelector
+	config    EmailConfig
+	verbose   bool
 	templates *template.Template
 }
 
 // NewEmailNotifier creates a new email notifier
 func NewEmailNotifier(config EmailConfig, verbose bool) *EmailNotifier {
 	notifier := &EmailNotifier{
-		config:  config,
-		verbose: verbose,
+		templateSelector: templateSelector{template: config.Template},
+		config:           config,
+		verbose:          verbose,
 	}
-	
+
 	notifier.loadTemplates()
 	return notifier
 }
@@ -78,8 +84,13 @@ func (en *EmailNotifier) IsEnabled() bool {
 	return en.config.Enabled
 }
 
-// buildEmailBody generates the email body using templates
+// buildEmailBody generates the email body using the query's or channel's
+// email template, or the built-in templates
 func (en *EmailNotifier) buildEmailBody(notification Notification) (string, error) {
+	if body, ok, err := en.render(TemplateEmail, notification, newTemplateData(notification, TemplateEmail)); ok || err != nil {
+		return body, err
+	}
+
 	// Prepare template data
 	data := EmailTemplateData{
 		QueryName:     notification.QueryName,
@@ -90,6 +101,8 @@ func (en *EmailNotifier) buildEmailBody(notification Notification) (string, erro
 		Priority:      string(notification.Priority),
 		Timestamp:     notification.Timestamp,
 		PriorityClass: en.getPriorityClass(notification.Priority),
+		MatchedBy:     notification.MatchedBy,
+		Reminder:      notification.Reminder,
 	}
 
 	// Choose template based on priority and content
@@ -116,7 +129,6 @@ func (en *EmailNotifier) buildMessage(notification Notification, body string, re
 	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(recipients, ", ")))
 	message.WriteString(fmt.Sprintf("Subject: %s\r\n", notification.Subject))
 	message.WriteString("MIME-Version: 1.0\r\n")
-	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
 	message.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
 	
 	// Add priority headers for high-priority notifications
@@ -126,14 +138,77 @@ func (en *EmailNotifier) buildMessage(notification Notification, body string, re
 		message.WriteString("Importance: High\r\n")
 	}
 
+	if len(notification.Attachments) == 0 {
+		message.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		message.WriteString("\r\n")
+
+		// Body
+		message.WriteString(body)
+
+		return message.Bytes(), nil
+	}
+
+	// Attachments go in a multipart/mixed message after the HTML body
+	writer := multipart.NewWriter(&message)
+	message.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n", writer.Boundary()))
 	message.WriteString("\r\n")
 
-	// Body
-	message.WriteString(body)
+	bodyPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/html; charset=UTF-8"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating body part: %w", err)
+	}
+	bodyPart.Write([]byte(body))
+
+	for _, attachment := range notification.Attachments {
+		if err := writeAttachmentPart(writer, attachment); err != nil {
+			return nil, fmt.Errorf("attaching %s: %w", attachment.Name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("closing multipart message: %w", err)
+	}
 
 	return message.Bytes(), nil
 }
 
+// writeAttachmentPart adds a base64-encoded file part, wrapped at 76 columns
+func writeAttachmentPart(writer *multipart.Writer, attachment Attachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType(contentType), map[string]string{"name": attachment.Name})},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = part.Write([]byte(encoded + "\r\n"))
+	return err
+}
+
+// mediaType strips parameters such as charset from a Content-Type value
+func mediaType(contentType string) string {
+	if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+		return parsed
+	}
+	return "application/octet-stream"
+}
+
 // sendSMTP sends the email via SMTP
 func (en *EmailNotifier) sendSMTP(recipients []string, message []byte) error {
 	// Connect to SMTP server
@@ -230,6 +305,11 @@ func (en *EmailNotifier) sendSMTP(recipients []string, message []byte) error {
 
 // getPriorityClass returns CSS class for priority styling
 func (en *EmailNotifier) getPriorityClass(priority Priority) string {
+	return priorityClass(priority)
+}
+
+// priorityClass returns the CSS class for a priority
+func priorityClass(priority Priority) string {
 	switch priority {
 	case PriorityHigh:
 		return "high-priority"
@@ -244,24 +324,27 @@ func (en *EmailNotifier) getPriorityClass(priority Priority) string {
 
 // loadTemplates loads email templates
 func (en *EmailNotifier) loadTemplates() {
-	funcMap := template.FuncMap{
-		"title": strings.Title,
+	funcMap := TemplateFuncs()
+	funcMap["priorityClass"] = func(priority string) string {
+		return en.getPriorityClass(Priority(priority))
 	}
-	
+
 	en.templates = template.Must(template.New("opportunity").Funcs(funcMap).Parse(opportunityTemplate))
 	template.Must(en.templates.New("opportunity-updated").Funcs(funcMap).Parse(opportunityUpdatedTemplate))
 }
 
 // EmailTemplateData holds data for email templates
 type EmailTemplateData struct {
-	QueryName     string              `json:"query_name"`
-	Subject       string              `json:"subject"`
+	QueryName     string               `json:"query_name"`
+	Subject       string               `json:"subject"`
 	Opportunities []samgov.Opportunity `json:"opportunities"`
 	FilteredOut   []samgov.Opportunity `json:"filtered_out,omitempty"`
-	Summary       NotificationSummary `json:"summary"`
-	Priority      string              `json:"priority"`
-	PriorityClass string              `json:"priority_class"`
-	Timestamp     time.Time           `json:"timestamp"`
+	Summary       NotificationSummary  `json:"summary"`
+	Priority      string               `json:"priority"`
+	PriorityClass string               `json:"priority_class"`
+	MatchedBy     map[string][]string  `json:"matched_by,omitempty"`
+	Reminder      string               `json:"reminder,omitempty"`
+	Timestamp     time.Time            `json:"timestamp"`
 }
 
 // Email templates
@@ -378,16 +461,30 @@ const opportunityTemplate = `
 </head>
 <body>
     <div class="header">
-        <h1>🚨 SAM.gov Opportunities Found</h1>
-        <p><strong>{{.QueryName}}</strong> - {{.Summary.NewOpportunities}} New Opportunities</p>
+        {{if .Reminder}}<h1>⏰ SAM.gov Deadline Reminder</h1>
+        <p><strong>{{.QueryName}}</strong> - {{len .Opportunities}} Deadlines Within {{.Reminder}}</p>{{else if .Summary.AwardedOpportunities}}<h1>🏆 Tracked SAM.gov Opportunities Awarded</h1>
+        <p><strong>{{.QueryName}}</strong> - {{.Summary.AwardedOpportunities}} Awarded Opportunities</p>{{else}}<h1>🚨 SAM.gov Opportunities Found</h1>
+        <p><strong>{{.QueryName}}</strong> - {{.Summary.NewOpportunities}} New Opportunities</p>{{end}}
     </div>
 
     <div class="summary">
         <div class="stats">
+            {{if .Reminder}}
+            <div class="stat-item">
+                <div class="stat-number">{{.Reminder}}</div>
+                <div>Until Deadline</div>
+            </div>
+            {{else if .Summary.AwardedOpportunities}}
+            <div class="stat-item">
+                <div class="stat-number">{{.Summary.AwardedOpportunities}}</div>
+                <div>Awarded Opportunities</div>
+            </div>
+            {{else}}
             <div class="stat-item">
                 <div class="stat-number">{{.Summary.NewOpportunities}}</div>
                 <div>New Opportunities</div>
             </div>
+            {{end}}
             {{if .Summary.UpcomingDeadlines}}
             <div class="stat-item">
                 <div class="stat-number">{{.Summary.UpcomingDeadlines}}</div>
@@ -402,22 +499,29 @@ const opportunityTemplate = `
     </div>
 
     {{range .Opportunities}}
-    <div class="opportunity {{$.PriorityClass}}">
+    <div class="opportunity {{with .Relevance}}{{priorityClass .Priority}}{{else}}{{$.PriorityClass}}{{end}}">
         <div class="opportunity-header">
             <h3 style="margin: 0 0 10px 0;">{{.Title}}</h3>
             <div class="metadata">
                 <span class="notice-id">{{.NoticeID}}</span>
                 <span style="margin-left: 15px;"><strong>Type:</strong> {{.Type}}</span>
                 <span style="margin-left: 15px;"><strong>Posted:</strong> {{.PostedDate}}</span>
+                {{with .Relevance}}<span style="margin-left: 15px;"><strong>Score:</strong> {{printf "%g" .Score}} ({{.Priority}})</span>{{end}}
             </div>
+            {{with .Relevance}}{{if .Reasons}}<div class="metadata">{{join .Reasons "; "}}</div>{{end}}{{end}}
+            {{with shared $.MatchedBy .NoticeID}}<div class="metadata"><strong>Matched by:</strong> {{join . ", "}}</div>{{end}}
         </div>
         
         <div class="opportunity-content">
-            {{if .ResponseDeadline}}
+            {{with award .Award}}
+            <div class="deadline">
+                🏆 <strong>Awarded:</strong> {{.}}
+            </div>
+            {{else}}{{if .ResponseDeadline}}
             <div class="deadline">
                 ⏰ <strong>Response Deadline:</strong> {{.ResponseDeadline}}
             </div>
-            {{end}}
+            {{end}}{{end}}
             
             <div class="metadata" style="margin: 10px 0;">
                 {{if .FullParentPath}}<div><strong>Organization:</strong> {{.FullParentPath}}</div>{{end}}
@@ -425,14 +529,14 @@ const opportunityTemplate = `
                 {{if .NAICSCode}}<div><strong>NAICS Code:</strong> {{.NAICSCode}}</div>{{end}}
             </div>
 
-            {{if .Description}}
+            {{with .DescriptionBody}}
             <div style="margin: 15px 0;">
                 <strong>Description:</strong><br>
                 <div style="background: #f8f9fa; padding: 10px; border-radius: 4px; margin-top: 5px;">
-                    {{if gt (len .Description) 300}}
-                        {{slice .Description 0 300}}...
+                    {{if gt (len .) 300}}
+                        {{slice . 0 300}}...
                     {{else}}
-                        {{.Description}}
+                        {{.}}
                     {{end}}
                 </div>
             </div>
@@ -548,6 +652,29 @@ const opportunityUpdatedTemplate = `
             color: #666;
             margin: 5px 0;
         }
+        .changes {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 0.9em;
+            margin: 10px 0;
+        }
+        .changes th, .changes td {
+            border: 1px solid #ddd;
+            padding: 6px 8px;
+            text-align: left;
+            vertical-align: top;
+        }
+        .changes th {
+            background: #f8f9fa;
+        }
+        .changes .old {
+            color: #999;
+            text-decoration: line-through;
+        }
+        .changes .new {
+            color: #155724;
+            font-weight: bold;
+        }
         .footer {
             margin-top: 30px;
             padding-top: 20px;
@@ -574,7 +701,10 @@ const opportunityUpdatedTemplate = `
             <div class="metadata">
                 <span class="notice-id">{{.NoticeID}}</span>
                 <span style="margin-left: 15px;"><strong>Posted:</strong> {{.PostedDate}}</span>
+                {{with .Relevance}}<span style="margin-left: 15px;"><strong>Score:</strong> {{printf "%g" .Score}} ({{.Priority}})</span>{{end}}
             </div>
+            {{with .Relevance}}{{if .Reasons}}<div class="metadata">{{join .Reasons "; "}}</div>{{end}}{{end}}
+            {{with shared $.MatchedBy .NoticeID}}<div class="metadata"><strong>Matched by:</strong> {{join . ", "}}</div>{{end}}
         </div>
         
         <div class="opportunity-content">
@@ -583,6 +713,23 @@ const opportunityUpdatedTemplate = `
                 ⏰ <strong>Response Deadline:</strong> {{.ResponseDeadline}}
             </div>
             {{end}}
+
+            {{if .Changes}}
+            <table class="changes">
+                <tr><th>Field</th><th>Was</th><th>Now</th></tr>
+                {{range .Changes}}
+                <tr>
+                    <td><strong>{{.Field}}</strong></td>
+                    {{if or .Old .New}}
+                    <td class="old">{{or .Old "(none)"}}</td>
+                    <td class="new">{{or .New "(none)"}}</td>
+                    {{else}}
+                    <td colspan="2"><em>Changed</em></td>
+                    {{end}}
+                </tr>
+                {{end}}
+            </table>
+            {{end}}
             
             <div style="margin-top: 15px;">
                 <a href="{{.UILink}}" class="btn">View Changes on SAM.gov</a>
@@ -5,32 +5,61 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"regexp"
+	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/yourusername/sam-gov-monitor/internal/samgov"
 )
 
-// GitHubNotifier implements GitHub issue notifications
+// DefaultGitHubAPIURL is the GitHub REST API used unless the config names
+// another, such as a GitHub Enterprise server or a local stub
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// maxMarkerPages limits how many pages of issues are scanned for notice
+// markers when the store has no record of an issue
+const maxMarkerPages = 10
+
+// issueMarker is hidden in the body of every opportunity issue so that the
+// issue can be found again without the state
+var issueMarker = regexp.MustCompile(`<!-- sam-gov-monitor:notice=(\S+) -->`)
+
+// GitHubNotifier implements GitHub issue notifications. Each high-priority
+// opportunity gets one issue: amendments are added to it as comments, and it
+// is closed when the notice expires or is awarded.
 type GitHubNotifier struct {
+	templateSelector
 	config    GitHubConfig
 	verbose   bool
 	client    *http.Client
 	templates *template.Template
+
+	mu      sync.Mutex
+	store   IssueStore
+	markers map[string]IssueRecord // Issues found by marker, loaded on first use
 }
 
 // NewGitHubNotifier creates a new GitHub notifier
 func NewGitHubNotifier(config GitHubConfig, verbose bool) *GitHubNotifier {
+	if config.APIURL == "" {
+		config.APIURL = DefaultGitHubAPIURL
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+
 	notifier := &GitHubNotifier{
-		config:  config,
-		verbose: verbose,
+		templateSelector: templateSelector{template: config.Template},
+		config:           config,
+		verbose:          verbose,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
-	
+
 	notifier.loadTemplates()
 	return notifier
 }
@@ -50,6 +79,52 @@ func (gn *GitHubNotifier) Send(ctx context.Context, notification Notification) e
 		log.Printf("Creating GitHub issues for notification: %s", notification.Subject)
 	}
 
+	// Reminders are about opportunities already reported, so they never
+	// open an issue per opportunity
+	if notification.Reminder != "" {
+		return gn.createSummaryIssue(ctx, notification)
+	}
+
+	// Awarded solicitations get their issues closed rather than new ones
+	if notification.Summary.AwardedOpportunities > 0 {
+		return nil
+	}
+
+	// Notices that already have an issue are updated there instead
+	untracked, err := gn.updateTrackedIssues(ctx, notification)
+	if err != nil {
+		return err
+	}
+	if len(untracked) == 0 {
+		return nil
+	}
+	notification.Opportunities = untracked
+
+	// Scored opportunities carry their own priority: high ones get their
+	// own issue and the rest share a summary issue
+	if individual, rest, scored := splitByRelevance(notification.Opportunities); scored {
+		if len(individual) > 0 {
+			highOnly := notification
+			highOnly.Opportunities = individual
+			if err := gn.createIndividualIssues(ctx, highOnly); err != nil {
+				return err
+			}
+		}
+		if len(rest) > 0 {
+			summary := notification
+			summary.Opportunities = rest
+			summary.Priority = PriorityLow
+			for _, opp := range rest {
+				if opp.Relevance == nil || Priority(opp.Relevance.Priority) != PriorityLow {
+					summary.Priority = PriorityMedium
+					break
+				}
+			}
+			return gn.createSummaryIssue(ctx, summary)
+		}
+		return nil
+	}
+
 	// Create issues for high-priority opportunities individually
 	// For lower priority, create one summary issue
 	if notification.Priority == PriorityHigh {
@@ -59,6 +134,24 @@ func (gn *GitHubNotifier) Send(ctx context.Context, notification Notification) e
 	}
 }
 
+// splitByRelevance separates high-priority scored opportunities from the
+// rest. scored is false when no opportunity has a relevance score.
+func splitByRelevance(opportunities []samgov.Opportunity) (high, rest []samgov.Opportunity, scored bool) {
+	for _, opp := range opportunities {
+		if opp.Relevance == nil {
+			rest = append(rest, opp)
+			continue
+		}
+		scored = true
+		if Priority(opp.Relevance.Priority) == PriorityHigh {
+			high = append(high, opp)
+		} else {
+			rest = append(rest, opp)
+		}
+	}
+	return high, rest, scored
+}
+
 // GetType returns the notifier type
 func (gn *GitHubNotifier) GetType() string {
 	return "github"
@@ -69,6 +162,199 @@ func (gn *GitHubNotifier) IsEnabled() bool {
 	return gn.config.Enabled
 }
 
+// SetIssueStore records the issue opened for each notice in store
+func (gn *GitHubNotifier) SetIssueStore(store IssueStore) {
+	gn.mu.Lock()
+	defer gn.mu.Unlock()
+	gn.store = store
+}
+
+// repository returns the owner/name of the target repository
+func (gn *GitHubNotifier) repository() string {
+	return gn.config.Owner + "/" + gn.config.Repository
+}
+
+// updateTrackedIssues comments the changes of amended notices on their
+// existing issues and returns the opportunities that have no issue yet.
+// Notices reported again without changes, e.g. by a retried delivery, are
+// skipped.
+func (gn *GitHubNotifier) updateTrackedIssues(ctx context.Context, notification Notification) ([]samgov.Opportunity, error) {
+	untracked := make([]samgov.Opportunity, 0, len(notification.Opportunities))
+	for _, opp := range notification.Opportunities {
+		record, found, err := gn.findIssue(ctx, opp.NoticeID)
+		if err != nil {
+			return nil, fmt.Errorf("looking up issue for %s: %w", opp.NoticeID, err)
+		}
+		if !found {
+			untracked = append(untracked, opp)
+			continue
+		}
+
+		if len(opp.Changes) == 0 {
+			if gn.verbose {
+				log.Printf("Notice %s already has issue #%d, not opening another", opp.NoticeID, record.Number)
+			}
+			continue
+		}
+
+		if err := gn.commentAmendment(ctx, record, opp, notification); err != nil {
+			return nil, fmt.Errorf("commenting on issue #%d for %s: %w", record.Number, opp.NoticeID, err)
+		}
+	}
+	return untracked, nil
+}
+
+// commentAmendment adds the changes of an amended notice to its issue
+func (gn *GitHubNotifier) commentAmendment(ctx context.Context, record IssueRecord, opp samgov.Opportunity, notification Notification) error {
+	data := GitHubTemplateData{
+		Opportunity: opp,
+		QueryName:   notification.QueryName,
+		Priority:    string(notification.Priority),
+		Timestamp:   notification.Timestamp,
+	}
+
+	var buf bytes.Buffer
+	if err := gn.templates.ExecuteTemplate(&buf, "amendment-comment", data); err != nil {
+		return fmt.Errorf("executing amendment comment template: %w", err)
+	}
+
+	if err := gn.commentOnIssue(ctx, record.Number, buf.String()); err != nil {
+		return err
+	}
+	gn.recordIssue(record)
+
+	if gn.verbose {
+		log.Printf("Commented amendment of %s on GitHub issue #%d", opp.NoticeID, record.Number)
+	}
+	return nil
+}
+
+// CloseIssue closes the issue of an expired or awarded notice with a comment
+// and a label naming the reason
+func (gn *GitHubNotifier) CloseIssue(ctx context.Context, closure IssueClosure) (bool, error) {
+	record, found, err := gn.findIssue(ctx, closure.NoticeID)
+	if err != nil {
+		return false, fmt.Errorf("looking up issue: %w", err)
+	}
+	if !found || record.Closed {
+		return false, nil
+	}
+
+	comment := fmt.Sprintf("🔒 Closing: this opportunity has %s.", closure.Reason)
+	if closure.Detail != "" {
+		comment += "\n\n" + closure.Detail
+	}
+	comment += "\n\n🤖 _Automated comment by SAM.gov Monitor_"
+	if err := gn.commentOnIssue(ctx, record.Number, comment); err != nil {
+		return false, err
+	}
+
+	// GitHub distinguishes finished work from abandoned work
+	stateReason := "not_planned"
+	if closure.Reason == ClosedAwarded {
+		stateReason = "completed"
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d", gn.repository(), record.Number)
+	if err := gn.apiRequest(ctx, "POST", path+"/labels", map[string][]string{"labels": {closure.Reason}}, nil); err != nil {
+		return false, fmt.Errorf("labeling issue #%d: %w", record.Number, err)
+	}
+	if err := gn.apiRequest(ctx, "PATCH", path, map[string]string{"state": "closed", "state_reason": stateReason}, nil); err != nil {
+		return false, fmt.Errorf("closing issue #%d: %w", record.Number, err)
+	}
+
+	record.Closed = true
+	gn.recordIssue(record)
+
+	if gn.verbose {
+		log.Printf("Closed GitHub issue #%d for %s (%s)", record.Number, closure.NoticeID, closure.Reason)
+	}
+	return true, nil
+}
+
+// findIssue returns the issue of a notice from the store, or from the notice
+// markers in existing issues when the store has no record of it
+func (gn *GitHubNotifier) findIssue(ctx context.Context, noticeID string) (IssueRecord, bool, error) {
+	gn.mu.Lock()
+	defer gn.mu.Unlock()
+
+	if gn.store != nil {
+		if record, ok := gn.store.GetIssue(gn.repository(), noticeID); ok {
+			return record, true, nil
+		}
+	}
+
+	if gn.markers == nil {
+		markers, err := gn.loadMarkers(ctx)
+		if err != nil {
+			return IssueRecord{}, false, err
+		}
+		gn.markers = markers
+	}
+
+	record, ok := gn.markers[noticeID]
+	if ok && gn.store != nil {
+		gn.store.SetIssue(record)
+	}
+	return record, ok, nil
+}
+
+// recordIssue saves record in the store and the marker cache
+func (gn *GitHubNotifier) recordIssue(record IssueRecord) {
+	gn.mu.Lock()
+	defer gn.mu.Unlock()
+
+	record.UpdatedAt = time.Now()
+	if gn.store != nil {
+		gn.store.SetIssue(record)
+	}
+	if gn.markers != nil {
+		gn.markers[record.NoticeID] = record
+	}
+}
+
+// loadMarkers scans the repository's opportunity issues, open and closed,
+// for notice markers
+func (gn *GitHubNotifier) loadMarkers(ctx context.Context) (map[string]IssueRecord, error) {
+	markers := make(map[string]IssueRecord)
+	for page := 1; page <= maxMarkerPages; page++ {
+		path := fmt.Sprintf("/repos/%s/issues?labels=individual-opportunity&state=all&per_page=100&page=%d", gn.repository(), page)
+		var issues []struct {
+			Number int    `json:"number"`
+			State  string `json:"state"`
+			Body   string `json:"body"`
+		}
+		if err := gn.apiRequest(ctx, "GET", path, nil, &issues); err != nil {
+			return nil, fmt.Errorf("listing issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			match := issueMarker.FindStringSubmatch(issue.Body)
+			if match == nil {
+				continue
+			}
+			// Issues are listed newest first; keep the newest per notice
+			if _, seen := markers[match[1]]; seen {
+				continue
+			}
+			markers[match[1]] = IssueRecord{
+				Repository: gn.repository(),
+				NoticeID:   match[1],
+				Number:     issue.Number,
+				Closed:     issue.State == "closed",
+			}
+		}
+
+		if len(issues) < 100 {
+			break
+		}
+	}
+
+	if gn.verbose {
+		log.Printf("Found %d opportunity issues in %s", len(markers), gn.repository())
+	}
+	return markers, nil
+}
+
 // createIndividualIssues creates separate issues for each opportunity
 func (gn *GitHubNotifier) createIndividualIssues(ctx context.Context, notification Notification) error {
 	for _, opp := range notification.Opportunities {
@@ -81,9 +367,17 @@ func (gn *GitHubNotifier) createIndividualIssues(ctx context.Context, notificati
 
 // createSummaryIssue creates one issue summarizing all opportunities
 func (gn *GitHubNotifier) createSummaryIssue(ctx context.Context, notification Notification) error {
-	title := fmt.Sprintf("%s - %d New Opportunities", 
-		notification.QueryName, len(notification.Opportunities))
-	
+	kind := "New"
+	if notification.Summary.UpdatedOpportunities > 0 {
+		kind = "Updated"
+	}
+	title := fmt.Sprintf("%s - %d %s Opportunities",
+		notification.QueryName, len(notification.Opportunities), kind)
+	if notification.Reminder != "" {
+		title = fmt.Sprintf("⏰ %s - %d Deadlines in %s",
+			notification.QueryName, len(notification.Opportunities), notification.Reminder)
+	}
+
 	body, err := gn.buildSummaryIssueBody(notification)
 	if err != nil {
 		return fmt.Errorf("building summary issue body: %w", err)
@@ -104,7 +398,10 @@ func (gn *GitHubNotifier) createSummaryIssue(ctx context.Context, notification N
 // createOpportunityIssue creates an issue for a single opportunity
 func (gn *GitHubNotifier) createOpportunityIssue(ctx context.Context, opp samgov.Opportunity, notification Notification) error {
 	title := fmt.Sprintf("🚨 %s - %s", opp.NoticeID, opp.Title)
-	
+	if len(opp.Changes) > 0 {
+		title = fmt.Sprintf("🔄 %s - %s (amended)", opp.NoticeID, opp.Title)
+	}
+
 	body, err := gn.buildOpportunityIssueBody(opp, notification)
 	if err != nil {
 		return fmt.Errorf("building opportunity issue body: %w", err)
@@ -124,17 +421,38 @@ func (gn *GitHubNotifier) createOpportunityIssue(ctx context.Context, opp samgov
 		Assignees: gn.config.AssignUsers,
 	}
 
-	return gn.createIssue(ctx, issue)
+	if err := gn.createIssue(ctx, issue); err != nil {
+		return err
+	}
+	gn.recordIssue(IssueRecord{
+		Repository: gn.repository(),
+		NoticeID:   opp.NoticeID,
+		Number:     issue.Number,
+	})
+	return nil
 }
 
 // buildOpportunityIssueBody creates issue body for a single opportunity
 func (gn *GitHubNotifier) buildOpportunityIssueBody(opp samgov.Opportunity, notification Notification) (string, error) {
+	custom := newTemplateData(notification, TemplateGitHub)
+	custom.Opportunities = []samgov.Opportunity{opp}
+	custom.Opportunity = opp
+	custom.IsIndividual = true
+	if body, ok, err := gn.render(TemplateGitHub, notification, custom); ok || err != nil {
+		// The marker is what finds the issue again, so custom bodies keep it
+		if err == nil && !issueMarker.MatchString(body) {
+			body += fmt.Sprintf("\n\n<!-- sam-gov-monitor:notice=%s -->\n", opp.NoticeID)
+		}
+		return body, err
+	}
+
 	data := GitHubTemplateData{
-		Opportunity:   opp,
-		QueryName:     notification.QueryName,
-		Priority:      string(notification.Priority),
-		Timestamp:     notification.Timestamp,
-		IsIndividual:  true,
+		Opportunity:  opp,
+		QueryName:    notification.QueryName,
+		Priority:     string(notification.Priority),
+		Timestamp:    notification.Timestamp,
+		IsIndividual: true,
+		MatchedBy:    notification.MatchedBy,
 	}
 
 	var buf bytes.Buffer
@@ -147,6 +465,10 @@ func (gn *GitHubNotifier) buildOpportunityIssueBody(opp samgov.Opportunity, noti
 
 // buildSummaryIssueBody creates issue body for summary of opportunities
 func (gn *GitHubNotifier) buildSummaryIssueBody(notification Notification) (string, error) {
+	if body, ok, err := gn.render(TemplateGitHub, notification, newTemplateData(notification, TemplateGitHub)); ok || err != nil {
+		return body, err
+	}
+
 	data := GitHubTemplateData{
 		Opportunities: notification.Opportunities,
 		QueryName:     notification.QueryName,
@@ -154,6 +476,8 @@ func (gn *GitHubNotifier) buildSummaryIssueBody(notification Notification) (stri
 		Timestamp:     notification.Timestamp,
 		Summary:       notification.Summary,
 		IsIndividual:  false,
+		MatchedBy:     notification.MatchedBy,
+		Reminder:      notification.Reminder,
 	}
 
 	var buf bytes.Buffer
@@ -164,26 +488,52 @@ func (gn *GitHubNotifier) buildSummaryIssueBody(notification Notification) (stri
 	return buf.String(), nil
 }
 
-// createIssue sends issue creation request to GitHub API
+// createIssue sends issue creation request to GitHub API and sets the
+// number of the new issue
 func (gn *GitHubNotifier) createIssue(ctx context.Context, issue *GitHubIssue) error {
-	// Build API URL
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", 
-		gn.config.Owner, gn.config.Repository)
+	var created struct {
+		Number int `json:"number"`
+	}
+	path := fmt.Sprintf("/repos/%s/issues", gn.repository())
+	if err := gn.apiRequest(ctx, "POST", path, issue, &created); err != nil {
+		return err
+	}
+	issue.Number = created.Number
 
-	// Marshal issue to JSON
-	jsonData, err := json.Marshal(issue)
-	if err != nil {
-		return fmt.Errorf("marshaling issue: %w", err)
+	if gn.verbose {
+		log.Printf("GitHub issue #%d created successfully: %s", issue.Number, issue.Title)
+	}
+
+	return nil
+}
+
+// commentOnIssue adds a comment to an issue
+func (gn *GitHubNotifier) commentOnIssue(ctx context.Context, number int, body string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", gn.repository(), number)
+	return gn.apiRequest(ctx, "POST", path, map[string]string{"body": body}, nil)
+}
+
+// apiRequest calls the GitHub API at path, sending payload as JSON when it is
+// not nil and decoding the response into result when it is not nil
+func (gn *GitHubNotifier) apiRequest(ctx context.Context, method, path string, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+		body = bytes.NewReader(jsonData)
 	}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, method, gn.config.APIURL+path, body)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Authorization", fmt.Sprintf("token %s", gn.config.Token))
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 	req.Header.Set("User-Agent", "SAM.gov-Monitor/1.0")
@@ -196,14 +546,15 @@ func (gn *GitHubNotifier) createIssue(ctx context.Context, issue *GitHubIssue) e
 	defer resp.Body.Close()
 
 	// Check response
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GitHub API returned status %d for %s %s", resp.StatusCode, method, path)
 	}
 
-	if gn.verbose {
-		log.Printf("GitHub issue created successfully: %s", issue.Title)
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("decoding GitHub response: %w", err)
+		}
 	}
-
 	return nil
 }
 
@@ -242,12 +593,20 @@ func (gn *GitHubNotifier) isUrgentDeadline(deadlineStr string) bool {
 
 // loadTemplates loads GitHub issue templates
 func (gn *GitHubNotifier) loadTemplates() {
-	funcMap := template.FuncMap{
-		"add": func(a, b int) int { return a + b },
-	}
-	
-	gn.templates = template.Must(template.New("github").Funcs(funcMap).Parse(individualIssueTemplate))
+	funcMap := TemplateFuncs()
+
+	gn.templates = template.Must(template.New("individual-issue").Funcs(funcMap).Parse(individualIssueTemplate))
 	template.Must(gn.templates.New("summary-issue").Funcs(funcMap).Parse(summaryIssueTemplate))
+	template.Must(gn.templates.New("amendment-comment").Funcs(funcMap).Parse(amendmentCommentTemplate))
+}
+
+// markdownCell makes a value safe inside a Markdown table cell
+func markdownCell(value string) string {
+	if value == "" {
+		return "_(none)_"
+	}
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
 }
 
 // GitHubIssue represents a GitHub issue
@@ -256,27 +615,38 @@ type GitHubIssue struct {
 	Body      string   `json:"body"`
 	Labels    []string `json:"labels,omitempty"`
 	Assignees []string `json:"assignees,omitempty"`
+	Number    int      `json:"-"` // Set once the issue is created
 }
 
 // GitHubTemplateData holds data for GitHub templates
 type GitHubTemplateData struct {
-	Opportunity   samgov.Opportunity      `json:"opportunity,omitempty"`
-	Opportunities []samgov.Opportunity    `json:"opportunities,omitempty"`
-	QueryName     string                  `json:"query_name"`
-	Priority      string                  `json:"priority"`
-	Timestamp     time.Time               `json:"timestamp"`
-	Summary       NotificationSummary     `json:"summary"`
-	IsIndividual  bool                    `json:"is_individual"`
+	Opportunity   samgov.Opportunity   `json:"opportunity,omitempty"`
+	Opportunities []samgov.Opportunity `json:"opportunities,omitempty"`
+	QueryName     string               `json:"query_name"`
+	Priority      string               `json:"priority"`
+	Timestamp     time.Time            `json:"timestamp"`
+	Summary       NotificationSummary  `json:"summary"`
+	IsIndividual  bool                 `json:"is_individual"`
+	MatchedBy     map[string][]string  `json:"matched_by,omitempty"`
+	Reminder      string               `json:"reminder,omitempty"`
 }
 
 // GitHub issue templates
-const individualIssueTemplate = `## 🚨 New SAM.gov Opportunity: {{.Opportunity.Title}}
+const individualIssueTemplate = `{{if .Opportunity.Changes}}## 🔄 Updated SAM.gov Opportunity: {{.Opportunity.Title}}{{else}}## 🚨 New SAM.gov Opportunity: {{.Opportunity.Title}}{{end}}
 
 **Notice ID:** {{.Opportunity.NoticeID}}  
 **Organization:** {{.Opportunity.FullParentPath}}  
 **Posted:** {{.Opportunity.PostedDate}}  
-{{if .Opportunity.ResponseDeadline}}**Deadline:** ⏰ {{.Opportunity.ResponseDeadline}}{{end}}
-
+{{with shared .MatchedBy .Opportunity.NoticeID}}**Matched by:** {{join . ", "}}  
+{{end}}{{if .Opportunity.ResponseDeadline}}**Deadline:** ⏰ {{.Opportunity.ResponseDeadline}}{{end}}
+{{with .Opportunity.Relevance}}**Relevance Score:** {{printf "%g" .Score}} ({{.Priority}})
+{{range .Reasons}}- {{.}}
+{{end}}{{end}}{{if .Opportunity.Changes}}
+### What Changed
+| Field | Was | Now |
+|-------|-----|-----|
+{{range .Opportunity.Changes}}{{if or .Old .New}}| **{{.Field}}** | {{mdcell .Old}} | {{mdcell .New}} |{{else}}| **{{.Field}}** | _changed_ | |{{end}}
+{{end}}{{end}}
 ### Quick Actions
 - [View on SAM.gov]({{.Opportunity.UILink}})
 - [Download Solicitation]({{.Opportunity.UILink}}?tab=documents)
@@ -286,9 +656,9 @@ const individualIssueTemplate = `## 🚨 New SAM.gov Opportunity: {{.Opportunity
 {{if .Opportunity.TypeOfSetAside}}**Set-Aside:** {{.Opportunity.TypeOfSetAside}}{{end}}
 {{if .Opportunity.NAICSCode}}**NAICS Code:** {{.Opportunity.NAICSCode}}{{end}}
 
-{{if .Opportunity.Description}}
+{{with .Opportunity.DescriptionBody}}
 ### Description
-{{.Opportunity.Description}}
+{{.}}
 {{end}}
 
 ### Next Steps
@@ -301,11 +671,26 @@ const individualIssueTemplate = `## 🚨 New SAM.gov Opportunity: {{.Opportunity
 ---
 **Query:** {{.QueryName}} | **Priority:** {{.Priority}} | **Generated:** {{.Timestamp.Format "Jan 2, 2006 at 3:04 PM MST"}}
 
-🤖 _Automated issue created by SAM.gov Monitor_`
+🤖 _Automated issue created by SAM.gov Monitor_
+<!-- sam-gov-monitor:notice={{.Opportunity.NoticeID}} -->`
+
+const amendmentCommentTemplate = `### 🔄 Opportunity Amended
+{{if .Opportunity.ResponseDeadline}}**Deadline:** ⏰ {{.Opportunity.ResponseDeadline}}
+{{end}}
+| Field | Was | Now |
+|-------|-----|-----|
+{{range .Opportunity.Changes}}{{if or .Old .New}}| **{{.Field}}** | {{mdcell .Old}} | {{mdcell .New}} |{{else}}| **{{.Field}}** | _changed_ | |{{end}}
+{{end}}
+[View on SAM.gov]({{.Opportunity.UILink}})
+
+---
+**Query:** {{.QueryName}} | **Detected:** {{.Timestamp.Format "Jan 2, 2006 at 3:04 PM MST"}}
+
+🤖 _Automated comment by SAM.gov Monitor_`
 
 const summaryIssueTemplate = `## 📋 SAM.gov Opportunities Summary - {{.QueryName}}
 
-Found **{{len .Opportunities}} new opportunities** matching your search criteria.
+{{if .Reminder}}⏰ **{{len .Opportunities}} tracked opportunities** are due in {{.Reminder}} or less.{{else if .Summary.UpdatedOpportunities}}**{{len .Opportunities}} opportunities** matching your search criteria were amended.{{else}}Found **{{len .Opportunities}} new opportunities** matching your search criteria.{{end}}
 
 {{if .Summary.UpcomingDeadlines}}
 ### ⚠️ Urgent: {{.Summary.UpcomingDeadlines}} opportunities with deadlines in the next 30 days
@@ -318,10 +703,14 @@ Found **{{len .Opportunities}} new opportunities** matching your search criteria
 - **Notice ID:** {{$opp.NoticeID}}
 - **Organization:** {{$opp.FullParentPath}}
 - **Posted:** {{$opp.PostedDate}}
-{{if $opp.ResponseDeadline}}  - **Deadline:** ⏰ {{$opp.ResponseDeadline}}{{end}}
+{{with shared $.MatchedBy $opp.NoticeID}}- **Matched by:** {{join . ", "}}
+{{end}}{{with $opp.Relevance}}- **Score:** {{printf "%g" .Score}} ({{.Priority}})
+{{end}}{{if $opp.ResponseDeadline}}  - **Deadline:** ⏰ {{$opp.ResponseDeadline}}{{end}}
 {{if $opp.TypeOfSetAside}}  - **Set-Aside:** {{$opp.TypeOfSetAside}}{{end}}
 {{if $opp.NAICSCode}}  - **NAICS:** {{$opp.NAICSCode}}{{end}}
-
+{{if $opp.Changes}}- **Changes:**
+{{range $opp.Changes}}  - {{.String}}
+{{end}}{{end}}
 {{end}}
 
 ### Batch Actions
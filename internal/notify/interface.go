@@ -2,7 +2,10 @@ package notify
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/yourusername/sam-gov-monitor/internal/samgov"
@@ -17,17 +20,53 @@ type Notifier interface {
 
 // Notification represents a notification to be sent
 type Notification struct {
-	QueryName     string                `json:"query_name"`
-	Priority      Priority              `json:"priority"`
-	Recipients    []string              `json:"recipients"`
-	Subject       string                `json:"subject"`
-	Body          Body                  `json:"body"`
-	Opportunities []samgov.Opportunity  `json:"opportunities"`
-	FilteredOut   []samgov.Opportunity  `json:"filtered_out,omitempty"`
-	Summary       NotificationSummary   `json:"summary"`
+	QueryName     string                 `json:"query_name"`
+	Priority      Priority               `json:"priority"`
+	Recipients    []string               `json:"recipients"`
+	Subject       string                 `json:"subject"`
+	Body          Body                   `json:"body"`
+	Opportunities []samgov.Opportunity   `json:"opportunities"`
+	FilteredOut   []samgov.Opportunity   `json:"filtered_out,omitempty"`
+	Summary       NotificationSummary    `json:"summary"`
 	Metadata      map[string]interface{} `json:"metadata"`
-	Timestamp     time.Time             `json:"timestamp"`
-	Attachments   []Attachment          `json:"attachments,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Attachments   []Attachment           `json:"attachments,omitempty"`
+	Channels      []string               `json:"channels,omitempty"`   // empty sends to every default channel
+	MatchedBy     map[string][]string    `json:"matched_by,omitempty"` // Notice ID → every query that matched it this run
+	Reminder      string                 `json:"reminder,omitempty"`   // Set on deadline reminders, e.g. "3 days"
+	Template      string                 `json:"template,omitempty"`   // The query's user-supplied template, see TemplateLibrary
+}
+
+// SharedMatch returns every query that matched the notice when more than one
+// did, and nil otherwise
+func (n Notification) SharedMatch(noticeID string) []string {
+	return sharedMatch(n.MatchedBy, noticeID)
+}
+
+func sharedMatch(matchedBy map[string][]string, noticeID string) []string {
+	if queries := matchedBy[noticeID]; len(queries) > 1 {
+		return queries
+	}
+	return nil
+}
+
+// describeAward renders an award as "Awardee • $amount • date", leaving out
+// what the notice does not give, and "" when there is no award
+func describeAward(award *samgov.Award) string {
+	if award == nil {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	if award.Awardee != nil && award.Awardee.Name != "" {
+		parts = append(parts, award.Awardee.Name)
+	}
+	if amount, ok := samgov.ParseAmount(award.Amount); ok {
+		parts = append(parts, samgov.FormatDollars(amount))
+	}
+	if award.Date != "" {
+		parts = append(parts, award.Date)
+	}
+	return strings.Join(parts, " • ")
 }
 
 // Body contains the notification content in different formats
@@ -48,11 +87,12 @@ const (
 
 // NotificationSummary provides quick stats about the notification
 type NotificationSummary struct {
-	NewOpportunities     int `json:"new_opportunities"`
-	FilteredOpportunities int `json:"filtered_opportunities,omitempty"`
-	UpdatedOpportunities int `json:"updated_opportunities"`
-	TotalValue           float64 `json:"total_value,omitempty"`
-	UpcomingDeadlines    int `json:"upcoming_deadlines"`
+	NewOpportunities      int     `json:"new_opportunities"`
+	FilteredOpportunities int     `json:"filtered_opportunities,omitempty"`
+	UpdatedOpportunities  int     `json:"updated_opportunities"`
+	AwardedOpportunities  int     `json:"awarded_opportunities,omitempty"`
+	TotalValue            float64 `json:"total_value,omitempty"`
+	UpcomingDeadlines     int     `json:"upcoming_deadlines"`
 }
 
 // Attachment represents a file attachment
@@ -64,9 +104,11 @@ type Attachment struct {
 
 // NotificationConfig holds configuration for notifications
 type NotificationConfig struct {
-	Email  EmailConfig  `json:"email"`
-	Slack  SlackConfig  `json:"slack"`
-	GitHub GitHubConfig `json:"github"`
+	Email   EmailConfig   `json:"email"`
+	Slack   SlackConfig   `json:"slack"`
+	Teams   TeamsConfig   `json:"teams"`
+	GitHub  GitHubConfig  `json:"github"`
+	Webhook WebhookConfig `json:"webhook"`
 }
 
 // EmailConfig configures email notifications
@@ -79,6 +121,7 @@ type EmailConfig struct {
 	FromAddress string   `json:"from_address"`
 	ToAddresses []string `json:"to_addresses"`
 	UseTLS      bool     `json:"use_tls"`
+	Template    string   `json:"template,omitempty"` // User-supplied template for this channel
 }
 
 // SlackConfig configures Slack notifications
@@ -88,21 +131,43 @@ type SlackConfig struct {
 	Channel    string `json:"channel,omitempty"`
 	Username   string `json:"username,omitempty"`
 	IconEmoji  string `json:"icon_emoji,omitempty"`
+	Template   string `json:"template,omitempty"` // User-supplied template for this channel
+}
+
+// TeamsConfig configures Microsoft Teams notifications
+type TeamsConfig struct {
+	Enabled    bool   `json:"enabled"`
+	WebhookURL string `json:"webhook_url"`
+}
+
+// WebhookConfig configures signed JSON webhook notifications
+type WebhookConfig struct {
+	Enabled    bool              `json:"enabled"`
+	URL        string            `json:"url"`
+	Secret     string            `json:"secret,omitempty"`      // HMAC-SHA256 signing key; unsigned when empty
+	Headers    map[string]string `json:"headers,omitempty"`     // Extra request headers, e.g. Authorization
+	MaxRetries int               `json:"max_retries,omitempty"` // 0 uses the default, negative disables retries
+	RetryDelay time.Duration     `json:"retry_delay,omitempty"` // First retry delay, doubled on each attempt
 }
 
 // GitHubConfig configures GitHub issue notifications
 type GitHubConfig struct {
-	Enabled     bool   `json:"enabled"`
-	Token       string `json:"token"`
-	Owner       string `json:"owner"`
-	Repository  string `json:"repository"`
+	Enabled     bool     `json:"enabled"`
+	Token       string   `json:"token"`
+	Owner       string   `json:"owner"`
+	Repository  string   `json:"repository"`
 	Labels      []string `json:"labels"`
 	AssignUsers []string `json:"assign_users,omitempty"`
+	APIURL      string   `json:"api_url,omitempty"`  // Defaults to DefaultGitHubAPIURL
+	Template    string   `json:"template,omitempty"` // User-supplied template for this channel
 }
 
 // NotificationManager orchestrates multiple notification channels
 type NotificationManager struct {
 	notifiers []Notifier
+	channels  map[string]Notifier
+	outbox    *Outbox
+	observer  DeliveryObserver
 	config    NotificationConfig
 	verbose   bool
 }
@@ -111,6 +176,7 @@ type NotificationManager struct {
 func NewNotificationManager(config NotificationConfig, verbose bool) *NotificationManager {
 	manager := &NotificationManager{
 		notifiers: make([]Notifier, 0),
+		channels:  make(map[string]Notifier),
 		config:    config,
 		verbose:   verbose,
 	}
@@ -126,6 +192,11 @@ func NewNotificationManager(config NotificationConfig, verbose bool) *Notificati
 		manager.notifiers = append(manager.notifiers, slackNotifier)
 	}
 
+	if config.Teams.Enabled {
+		teamsNotifier := NewTeamsNotifier(config.Teams, verbose)
+		manager.notifiers = append(manager.notifiers, teamsNotifier)
+	}
+
 	if config.GitHub.Enabled {
 		githubNotifier := NewGitHubNotifier(config.GitHub, verbose)
 		manager.notifiers = append(manager.notifiers, githubNotifier)
@@ -136,59 +207,217 @@ func NewNotificationManager(config NotificationConfig, verbose bool) *Notificati
 		log.Printf("GitHub notifier DISABLED - not added to notification manager")
 	}
 
+	if config.Webhook.Enabled {
+		webhookNotifier := NewWebhookNotifier(config.Webhook, verbose)
+		manager.notifiers = append(manager.notifiers, webhookNotifier)
+	}
+
 	return manager
 }
 
-// SendNotification sends a notification through all enabled channels
+// RegisterChannel adds a named channel that notifications can select by name
+func (nm *NotificationManager) RegisterChannel(name string, notifier Notifier) {
+	nm.channels[name] = notifier
+	if nm.verbose {
+		log.Printf("Added %s channel '%s' to notification manager", notifier.GetType(), name)
+	}
+}
+
+// SetOutbox records every delivery from now on in outbox, so that failed
+// deliveries can be retried later
+func (nm *NotificationManager) SetOutbox(outbox *Outbox) {
+	nm.outbox = outbox
+}
+
+// DeliveryObserver is told the result of every attempt to deliver a
+// notification through a channel
+type DeliveryObserver func(channel string, err error)
+
+// SetDeliveryObserver reports every delivery attempt from now on to observer
+func (nm *NotificationManager) SetDeliveryObserver(observer DeliveryObserver) {
+	nm.observer = observer
+}
+
+// Outbox returns the delivery outbox, or nil when deliveries are not tracked
+func (nm *NotificationManager) Outbox() *Outbox {
+	return nm.outbox
+}
+
+// SendNotification sends a notification through its selected channels, or
+// through all default channels when none are selected
 func (nm *NotificationManager) SendNotification(ctx context.Context, notification Notification) error {
-	if len(nm.notifiers) == 0 {
+	targets := nm.resolveChannels(notification.Channels)
+	if len(targets) == 0 {
 		return nil // No notifiers configured
 	}
 
 	// Send through all channels concurrently
-	errChan := make(chan error, len(nm.notifiers))
-	
-	for _, notifier := range nm.notifiers {
-		go func(n Notifier) {
-			err := n.Send(ctx, notification)
+	errChan := make(chan error, len(targets))
+
+	for _, target := range targets {
+		go func(t channelTarget) {
+			deliveryID := ""
+			if nm.outbox != nil {
+				deliveryID = nm.outbox.Record(t.name, notification)
+			}
+			err := t.notifier.Send(ctx, notification)
 			if err != nil {
-				errChan <- err
-			} else {
-				errChan <- nil
+				err = fmt.Errorf("%s: %w", t.name, err)
 			}
-		}(notifier)
+			if nm.outbox != nil {
+				nm.outbox.Complete(deliveryID, err)
+			}
+			if nm.observer != nil {
+				nm.observer(t.name, err)
+			}
+			errChan <- err
+		}(target)
 	}
 
 	// Collect results
-	var errors []error
-	for i := 0; i < len(nm.notifiers); i++ {
+	var failures []error
+	for i := 0; i < len(targets); i++ {
 		if err := <-errChan; err != nil {
-			errors = append(errors, err)
+			failures = append(failures, err)
 		}
 	}
 
 	// Return combined error if any failed
-	if len(errors) > 0 {
-		return &MultiNotificationError{Errors: errors}
+	if len(failures) > 0 {
+		return &MultiNotificationError{Errors: failures, Queued: nm.outbox != nil}
 	}
 
 	return nil
 }
 
+// RetryDeliveries resends deliveries from the outbox one at a time through
+// the channel each was first sent to, recording the results. It returns how
+// many were sent.
+func (nm *NotificationManager) RetryDeliveries(ctx context.Context, deliveries []Delivery) (int, error) {
+	if nm.outbox == nil {
+		return 0, nil
+	}
+
+	sent := 0
+	var failures []error
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			failures = append(failures, ctx.Err())
+			break
+		}
+		if delivery.Notification == nil {
+			continue
+		}
+
+		var err error
+		targets := nm.resolveChannels([]string{delivery.Channel})
+		if len(targets) == 0 {
+			err = fmt.Errorf("%s: channel is not configured", delivery.Channel)
+		} else if sendErr := targets[0].notifier.Send(ctx, *delivery.Notification); sendErr != nil {
+			err = fmt.Errorf("%s: %w", delivery.Channel, sendErr)
+		}
+
+		nm.outbox.Complete(delivery.ID, err)
+		if nm.observer != nil {
+			nm.observer(delivery.Channel, err)
+		}
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		sent++
+		if nm.verbose {
+			log.Printf("Redelivered '%s' to %s", delivery.Subject, delivery.Channel)
+		}
+	}
+
+	if len(failures) > 0 {
+		return sent, &MultiNotificationError{Errors: failures, Queued: true}
+	}
+	return sent, nil
+}
+
+// channelTarget is a notifier and the channel name it was selected by
+type channelTarget struct {
+	name     string
+	notifier Notifier
+}
+
+// resolveChannels maps channel names to notifiers. A name is either a
+// registered channel or the type of a default notifier.
+func (nm *NotificationManager) resolveChannels(names []string) []channelTarget {
+	if len(names) == 0 {
+		targets := make([]channelTarget, 0, len(nm.notifiers))
+		for _, notifier := range nm.notifiers {
+			targets = append(targets, channelTarget{name: notifier.GetType(), notifier: notifier})
+		}
+		return targets
+	}
+
+	selected := make([]channelTarget, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if notifier, ok := nm.channels[name]; ok {
+			selected = append(selected, channelTarget{name: name, notifier: notifier})
+			continue
+		}
+
+		found := false
+		for _, notifier := range nm.notifiers {
+			if notifier.GetType() == name {
+				selected = append(selected, channelTarget{name: name, notifier: notifier})
+				found = true
+			}
+		}
+		if !found {
+			log.Printf("Notification channel '%s' is not configured, skipping", name)
+		}
+	}
+	return selected
+}
+
+// DefaultChannels returns the types of the default notifiers, which is what
+// a notification with no channels is sent to
+func (nm *NotificationManager) DefaultChannels() []string {
+	types := make([]string, 0, len(nm.notifiers))
+	for _, notifier := range nm.notifiers {
+		types = append(types, notifier.GetType())
+	}
+	return types
+}
+
 // GetEnabledNotifiers returns list of enabled notification types
 func (nm *NotificationManager) GetEnabledNotifiers() []string {
-	types := make([]string, 0, len(nm.notifiers))
+	types := make([]string, 0, len(nm.notifiers)+len(nm.channels))
 	for _, notifier := range nm.notifiers {
 		if notifier.IsEnabled() {
 			types = append(types, notifier.GetType())
 		}
 	}
+	for name, notifier := range nm.channels {
+		if notifier.IsEnabled() {
+			types = append(types, notifier.GetType()+":"+name)
+		}
+	}
 	return types
 }
 
 // MultiNotificationError represents errors from multiple notification channels
 type MultiNotificationError struct {
 	Errors []error `json:"errors"`
+	Queued bool    `json:"queued"` // The failed deliveries are in the outbox and will be retried
+}
+
+// IsQueuedForRetry reports whether every delivery that failed in err is in
+// the outbox, so the caller need not resend the notification itself
+func IsQueuedForRetry(err error) bool {
+	var multi *MultiNotificationError
+	return errors.As(err, &multi) && multi.Queued
 }
 
 func (e *MultiNotificationError) Error() string {
@@ -228,6 +457,24 @@ func (nb *NotificationBuilder) WithQuery(queryName string, priority Priority) *N
 	return nb
 }
 
+// WithChannels selects the channels to send through
+func (nb *NotificationBuilder) WithChannels(channels []string) *NotificationBuilder {
+	nb.notification.Channels = channels
+	return nb
+}
+
+// WithMatchedBy records which queries matched each notice
+func (nb *NotificationBuilder) WithMatchedBy(matchedBy map[string][]string) *NotificationBuilder {
+	nb.notification.MatchedBy = matchedBy
+	return nb
+}
+
+// WithTemplate selects the query's user-supplied template
+func (nb *NotificationBuilder) WithTemplate(name string) *NotificationBuilder {
+	nb.notification.Template = name
+	return nb
+}
+
 // WithRecipients sets the notification recipients
 func (nb *NotificationBuilder) WithRecipients(recipients []string) *NotificationBuilder {
 	nb.notification.Recipients = recipients
@@ -271,6 +518,23 @@ func (nb *NotificationBuilder) WithUpdatedOpportunities(opportunities []samgov.O
 	return nb
 }
 
+// WithAwards makes the notification report award notices for tracked
+// opportunities
+func (nb *NotificationBuilder) WithAwards(opportunities []samgov.Opportunity) *NotificationBuilder {
+	nb.notification.Opportunities = opportunities
+	nb.notification.Summary = NotificationSummary{AwardedOpportunities: len(opportunities)}
+	return nb
+}
+
+// WithReminder makes the notification a deadline reminder for opportunities
+// due within dueIn, e.g. "3 days"
+func (nb *NotificationBuilder) WithReminder(dueIn string, opportunities []samgov.Opportunity) *NotificationBuilder {
+	nb.notification.Opportunities = opportunities
+	nb.notification.Reminder = dueIn
+	nb.notification.Summary = NotificationSummary{UpcomingDeadlines: len(opportunities)}
+	return nb
+}
+
 // WithSubject sets the notification subject
 func (nb *NotificationBuilder) WithSubject(subject string) *NotificationBuilder {
 	nb.notification.Subject = subject
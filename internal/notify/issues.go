@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Reasons an opportunity's issue is closed. Each is also added as a label.
+const (
+	ClosedExpired = "expired"
+	ClosedAwarded = "awarded"
+)
+
+// IssueRecord is the issue tracking one notice in one repository
+type IssueRecord struct {
+	Repository string    `json:"repository"` // owner/name
+	NoticeID   string    `json:"notice_id"`
+	Number     int       `json:"number"`
+	Closed     bool      `json:"closed,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// IssueStore remembers which issue was opened for each notice, so that later
+// notifications update it instead of opening another
+type IssueStore interface {
+	GetIssue(repository, noticeID string) (IssueRecord, bool)
+	SetIssue(record IssueRecord)
+}
+
+// IssueKey identifies an issue record in a store
+func IssueKey(repository, noticeID string) string {
+	return repository + "#" + noticeID
+}
+
+// IssueClosure asks for the issue of a notice to be closed
+type IssueClosure struct {
+	NoticeID string
+	Title    string
+	Reason   string // ClosedExpired or ClosedAwarded
+	Detail   string // Explanation added to the closing comment
+}
+
+// IssueTracker is implemented by notifiers that keep one issue per notice
+type IssueTracker interface {
+	SetIssueStore(store IssueStore)
+	// CloseIssue closes the notice's issue, reporting false when it has no
+	// open issue
+	CloseIssue(ctx context.Context, closure IssueClosure) (bool, error)
+}
+
+// SetIssueStore gives every issue tracking notifier, including named
+// channels registered so far, the store for issue numbers
+func (nm *NotificationManager) SetIssueStore(store IssueStore) {
+	for _, tracker := range nm.issueTrackers() {
+		tracker.SetIssueStore(store)
+	}
+}
+
+// CloseIssues closes the issues of the given notices on every issue tracking
+// notifier and returns how many were closed
+func (nm *NotificationManager) CloseIssues(ctx context.Context, closures []IssueClosure) (int, error) {
+	closed := 0
+	failures := make([]string, 0)
+	for _, tracker := range nm.issueTrackers() {
+		for _, closure := range closures {
+			ok, err := tracker.CloseIssue(ctx, closure)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", closure.NoticeID, err))
+				continue
+			}
+			if ok {
+				closed++
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return closed, fmt.Errorf("closing %d issues failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	if nm.verbose && closed > 0 {
+		log.Printf("Closed %d opportunity issues", closed)
+	}
+	return closed, nil
+}
+
+// HasIssueTrackers reports whether any enabled notifier keeps issues
+func (nm *NotificationManager) HasIssueTrackers() bool {
+	return len(nm.issueTrackers()) > 0
+}
+
+// issueTrackers returns the enabled issue tracking notifiers, each once
+func (nm *NotificationManager) issueTrackers() []IssueTracker {
+	seen := make(map[Notifier]bool)
+	trackers := make([]IssueTracker, 0)
+	add := func(notifier Notifier) {
+		tracker, ok := notifier.(IssueTracker)
+		if !ok || seen[notifier] || !notifier.IsEnabled() {
+			return
+		}
+		seen[notifier] = true
+		trackers = append(trackers, tracker)
+	}
+
+	for _, notifier := range nm.notifiers {
+		add(notifier)
+	}
+	for _, notifier := range nm.channels {
+		add(notifier)
+	}
+	return trackers
+}
@@ -0,0 +1,236 @@
+package notify
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeliveryStatus is the state of one notification on one channel
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+)
+
+// Delivery tracks one notification sent through one channel
+type Delivery struct {
+	ID          string         `json:"id"`
+	Channel     string         `json:"channel"` // named channel or notifier type
+	QueryName   string         `json:"query_name"`
+	Subject     string         `json:"subject"`
+	Status      DeliveryStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	LastError   string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	LastAttempt time.Time      `json:"last_attempt,omitempty"`
+	SentAt      time.Time      `json:"sent_at,omitempty"`
+
+	// Notification is kept until the delivery succeeds so it can be resent.
+	// Attachments are not kept.
+	Notification *Notification `json:"notification,omitempty"`
+}
+
+// OutboxStore persists deliveries between runs
+type OutboxStore interface {
+	GetDeliveries() []Delivery
+	SetDeliveries(deliveries []Delivery)
+}
+
+// OutboxPolicy decides how long failed deliveries are retried and kept
+type OutboxPolicy struct {
+	MaxAttempts int           // automatic retries stop after this many attempts
+	Retention   time.Duration // deliveries created longer ago than this are dropped
+}
+
+// Outbox records every notification per channel as pending, sent or failed
+// so that failed deliveries can be retried on a later run
+type Outbox struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+	store      OutboxStore
+	policy     OutboxPolicy
+}
+
+// NewOutbox loads the deliveries saved in store and keeps it up to date
+func NewOutbox(store OutboxStore, policy OutboxPolicy) *Outbox {
+	return &Outbox{
+		deliveries: append(make([]Delivery, 0), store.GetDeliveries()...),
+		store:      store,
+		policy:     policy,
+	}
+}
+
+// persist writes the deliveries back to the store. Callers hold the lock.
+func (o *Outbox) persist() {
+	o.store.SetDeliveries(append([]Delivery(nil), o.deliveries...))
+}
+
+// Record adds a pending delivery of notification through channel and
+// returns its ID
+func (o *Outbox) Record(channel string, notification Notification) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	notification.Attachments = nil
+	delivery := Delivery{
+		ID:           newDeliveryID(),
+		Channel:      channel,
+		QueryName:    notification.QueryName,
+		Subject:      notification.Subject,
+		Status:       DeliveryPending,
+		CreatedAt:    time.Now(),
+		Notification: &notification,
+	}
+	o.deliveries = append(o.deliveries, delivery)
+	o.persist()
+	return delivery.ID
+}
+
+// Complete records the result of a delivery attempt. A successful delivery
+// drops its notification, which is no longer needed.
+func (o *Outbox) Complete(id string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.deliveries {
+		delivery := &o.deliveries[i]
+		if delivery.ID != id {
+			continue
+		}
+
+		now := time.Now()
+		delivery.Attempts++
+		delivery.LastAttempt = now
+		if err != nil {
+			delivery.Status = DeliveryFailed
+			delivery.LastError = err.Error()
+		} else {
+			delivery.Status = DeliverySent
+			delivery.LastError = ""
+			delivery.SentAt = now
+			delivery.Notification = nil
+		}
+		o.persist()
+		return
+	}
+}
+
+// List returns deliveries with the given status, or all of them when status
+// is empty, oldest first
+func (o *Outbox) List(status DeliveryStatus) []Delivery {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	deliveries := make([]Delivery, 0)
+	for _, delivery := range o.deliveries {
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries
+}
+
+// Retryable returns the unsent deliveries that have attempts left under the
+// policy, oldest first. Pending deliveries were interrupted before their
+// result was recorded.
+func (o *Outbox) Retryable() []Delivery {
+	retryable := make([]Delivery, 0)
+	for _, delivery := range o.List("") {
+		if delivery.Status == DeliverySent || delivery.Notification == nil {
+			continue
+		}
+		if o.policy.MaxAttempts > 0 && delivery.Attempts >= o.policy.MaxAttempts {
+			continue
+		}
+		retryable = append(retryable, delivery)
+	}
+	return retryable
+}
+
+// Get returns the delivery with the given ID
+func (o *Outbox) Get(id string) (Delivery, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, delivery := range o.deliveries {
+		if delivery.ID == id {
+			return delivery, true
+		}
+	}
+	return Delivery{}, false
+}
+
+// Prune drops deliveries created longer ago than the retention period and
+// returns how many failed deliveries were dropped without being sent
+func (o *Outbox) Prune(now time.Time) int {
+	if o.policy.Retention <= 0 {
+		return 0
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	cutoff := now.Add(-o.policy.Retention)
+	kept := make([]Delivery, 0, len(o.deliveries))
+	expired := 0
+	for _, delivery := range o.deliveries {
+		if delivery.CreatedAt.Before(cutoff) {
+			if delivery.Status != DeliverySent {
+				expired++
+			}
+			continue
+		}
+		kept = append(kept, delivery)
+	}
+
+	if len(kept) != len(o.deliveries) {
+		o.deliveries = kept
+		o.persist()
+	}
+	return expired
+}
+
+// OutboxStats summarizes deliveries by status
+type OutboxStats struct {
+	Pending int `json:"pending"`
+	Sent    int `json:"sent"`
+	Failed  int `json:"failed"`
+}
+
+// Stats counts deliveries by status
+func (o *Outbox) Stats() OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var stats OutboxStats
+	for _, delivery := range o.deliveries {
+		switch delivery.Status {
+		case DeliveryPending:
+			stats.Pending++
+		case DeliverySent:
+			stats.Sent++
+		case DeliveryFailed:
+			stats.Failed++
+		}
+	}
+	return stats
+}
+
+// newDeliveryID returns a short random ID that is easy to type on the
+// command line
+func newDeliveryID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
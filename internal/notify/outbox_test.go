@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// memoryOutboxStore keeps deliveries in memory
+type memoryOutboxStore struct {
+	deliveries []Delivery
+	saves      int
+}
+
+func (s *memoryOutboxStore) GetDeliveries() []Delivery { return s.deliveries }
+
+func (s *memoryOutboxStore) SetDeliveries(deliveries []Delivery) {
+	s.deliveries = deliveries
+	s.saves++
+}
+
+func TestOutboxRecordAndComplete(t *testing.T) {
+	store := &memoryOutboxStore{}
+	outbox := NewOutbox(store, OutboxPolicy{MaxAttempts: 3})
+
+	id := outbox.Record("ops-slack", Notification{
+		QueryName:   "Cloud",
+		Subject:     "2 new opportunities",
+		Attachments: []Attachment{{Name: "deadlines.ics", Content: []byte("BEGIN:VCALENDAR")}},
+	})
+
+	delivery, ok := outbox.Get(id)
+	if !ok {
+		t.Fatalf("recorded delivery %s not found", id)
+	}
+	if delivery.Status != DeliveryPending || delivery.Channel != "ops-slack" || delivery.QueryName != "Cloud" {
+		t.Errorf("recorded delivery = %+v", delivery)
+	}
+	if delivery.Notification == nil || delivery.Notification.Attachments != nil {
+		t.Errorf("recorded notification = %+v, want it kept without attachments", delivery.Notification)
+	}
+	if len(store.deliveries) != 1 {
+		t.Errorf("store has %d deliveries after Record, want 1", len(store.deliveries))
+	}
+
+	outbox.Complete(id, errors.New("webhook returned 502"))
+	delivery, _ = outbox.Get(id)
+	if delivery.Status != DeliveryFailed || delivery.Attempts != 1 || delivery.LastError != "webhook returned 502" {
+		t.Errorf("failed delivery = %+v", delivery)
+	}
+	if delivery.Notification == nil {
+		t.Errorf("failed delivery dropped its notification, so it cannot be resent")
+	}
+
+	outbox.Complete(id, nil)
+	delivery, _ = outbox.Get(id)
+	if delivery.Status != DeliverySent || delivery.Attempts != 2 || delivery.LastError != "" || delivery.SentAt.IsZero() {
+		t.Errorf("sent delivery = %+v", delivery)
+	}
+	if delivery.Notification != nil {
+		t.Errorf("sent delivery kept its notification")
+	}
+
+	// Completing an unknown delivery changes nothing
+	saves := store.saves
+	outbox.Complete("unknown", nil)
+	if store.saves != saves {
+		t.Errorf("completing an unknown delivery saved the outbox")
+	}
+
+	// A later run sees the saved deliveries
+	reloaded := NewOutbox(store, OutboxPolicy{})
+	if stats := reloaded.Stats(); stats.Sent != 1 || stats.Pending != 0 || stats.Failed != 0 {
+		t.Errorf("reloaded stats = %+v, want 1 sent", stats)
+	}
+}
+
+func TestOutboxRetryable(t *testing.T) {
+	notification := &Notification{Subject: "New opportunity"}
+
+	tests := []struct {
+		name        string
+		delivery    Delivery
+		maxAttempts int
+		want        bool
+	}{
+		{"failed with attempts left", Delivery{Status: DeliveryFailed, Attempts: 2, Notification: notification}, 3, true},
+		{"failed at max attempts", Delivery{Status: DeliveryFailed, Attempts: 3, Notification: notification}, 3, false},
+		{"no attempt limit", Delivery{Status: DeliveryFailed, Attempts: 10, Notification: notification}, 0, true},
+		{"interrupted before its result", Delivery{Status: DeliveryPending, Notification: notification}, 3, true},
+		{"sent", Delivery{Status: DeliverySent, Attempts: 1}, 3, false},
+		{"failed without a notification to resend", Delivery{Status: DeliveryFailed, Attempts: 1}, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.delivery.ID = "d1"
+			outbox := NewOutbox(&memoryOutboxStore{deliveries: []Delivery{tt.delivery}}, OutboxPolicy{MaxAttempts: tt.maxAttempts})
+
+			retryable := outbox.Retryable()
+			if got := len(retryable) == 1; got != tt.want {
+				t.Errorf("retryable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboxRetryableOldestFirst(t *testing.T) {
+	now := time.Now()
+	notification := &Notification{Subject: "New opportunity"}
+	outbox := NewOutbox(&memoryOutboxStore{deliveries: []Delivery{
+		{ID: "newer", Status: DeliveryFailed, CreatedAt: now, Notification: notification},
+		{ID: "older", Status: DeliveryFailed, CreatedAt: now.Add(-time.Hour), Notification: notification},
+	}}, OutboxPolicy{MaxAttempts: 3})
+
+	retryable := outbox.Retryable()
+	if len(retryable) != 2 || retryable[0].ID != "older" || retryable[1].ID != "newer" {
+		t.Errorf("retryable = %+v, want older then newer", retryable)
+	}
+}
+
+func TestOutboxPrune(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	deliveries := []Delivery{
+		{ID: "old-sent", Status: DeliverySent, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+		{ID: "old-failed", Status: DeliveryFailed, CreatedAt: now.Add(-8 * 24 * time.Hour)},
+		{ID: "old-pending", Status: DeliveryPending, CreatedAt: now.Add(-8 * 24 * time.Hour)},
+		{ID: "recent-failed", Status: DeliveryFailed, CreatedAt: now.Add(-24 * time.Hour)},
+	}
+
+	tests := []struct {
+		name        string
+		retention   time.Duration
+		wantExpired int
+		wantKept    []string
+	}{
+		{"drops deliveries past retention", 7 * 24 * time.Hour, 2, []string{"recent-failed"}},
+		{"keeps deliveries within retention", 30 * 24 * time.Hour, 0, []string{"old-sent", "old-failed", "old-pending", "recent-failed"}},
+		{"no retention keeps everything", 0, 0, []string{"old-sent", "old-failed", "old-pending", "recent-failed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryOutboxStore{deliveries: append([]Delivery(nil), deliveries...)}
+			outbox := NewOutbox(store, OutboxPolicy{Retention: tt.retention})
+
+			if expired := outbox.Prune(now); expired != tt.wantExpired {
+				t.Errorf("Prune() = %d failed deliveries dropped, want %d", expired, tt.wantExpired)
+			}
+			kept := make([]string, 0)
+			for _, delivery := range store.deliveries {
+				kept = append(kept, delivery.ID)
+			}
+			if len(kept) != len(tt.wantKept) {
+				t.Fatalf("store keeps %v, want %v", kept, tt.wantKept)
+			}
+			for i := range kept {
+				if kept[i] != tt.wantKept[i] {
+					t.Errorf("store keeps %v, want %v", kept, tt.wantKept)
+					break
+				}
+			}
+		})
+	}
+}
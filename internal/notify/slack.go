@@ -15,6 +15,7 @@ import (
 
 // SlackNotifier implements Slack webhook notifications
 type SlackNotifier struct {
+	templateSelector
 	config  SlackConfig
 	verbose bool
 	client  *http.Client
@@ -23,8 +24,9 @@ type SlackNotifier struct {
 // NewSlackNotifier creates a new Slack notifier
 func NewSlackNotifier(config SlackConfig, verbose bool) *SlackNotifier {
 	return &SlackNotifier{
-		config:  config,
-		verbose: verbose,
+		templateSelector: templateSelector{template: config.Template},
+		config:           config,
+		verbose:          verbose,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -86,6 +88,16 @@ func (sn *SlackNotifier) buildSlackMessage(notification Notification) (*SlackMes
 		message.IconEmoji = ":rotating_light:"
 	}
 
+	// A user-supplied template replaces the blocks with its text
+	text, ok, err := sn.render(TemplateSlack, notification, newTemplateData(notification, TemplateSlack))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		message.Text = text
+		return message, nil
+	}
+
 	// Build blocks
 	blocks := sn.buildMessageBlocks(notification)
 	message.Blocks = blocks
@@ -111,7 +123,14 @@ func (sn *SlackNotifier) buildMessageBlocks(notification Notification) []SlackBl
 
 	// Summary block
 	summaryFields := make([]SlackField, 0)
-	
+
+	if notification.Reminder != "" {
+		summaryFields = append(summaryFields, SlackField{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Deadline In:*\n%s", notification.Reminder),
+		})
+	}
+
 	if notification.Summary.NewOpportunities > 0 {
 		summaryFields = append(summaryFields, SlackField{
 			Type: "mrkdwn",
@@ -125,7 +144,14 @@ func (sn *SlackNotifier) buildMessageBlocks(notification Notification) []SlackBl
 			Text: fmt.Sprintf("*Updated Opportunities:*\n%d", notification.Summary.UpdatedOpportunities),
 		})
 	}
-	
+
+	if notification.Summary.AwardedOpportunities > 0 {
+		summaryFields = append(summaryFields, SlackField{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Awarded Opportunities:*\n%d", notification.Summary.AwardedOpportunities),
+		})
+	}
+
 	summaryFields = append(summaryFields, SlackField{
 		Type: "mrkdwn",
 		Text: fmt.Sprintf("*Priority:*\n%s", strings.Title(string(notification.Priority))),
@@ -160,7 +186,7 @@ func (sn *SlackNotifier) buildMessageBlocks(notification Notification) []SlackBl
 			break
 		}
 
-		blocks = append(blocks, sn.buildOpportunityBlock(opp))
+		blocks = append(blocks, sn.buildOpportunityBlock(opp, notification.SharedMatch(opp.NoticeID)))
 	}
 
 	// Divider before footer
@@ -186,12 +212,28 @@ func (sn *SlackNotifier) buildMessageBlocks(notification Notification) []SlackBl
 	return blocks
 }
 
-// buildOpportunityBlock creates a block for a single opportunity
-func (sn *SlackNotifier) buildOpportunityBlock(opp samgov.Opportunity) SlackBlock {
+// buildOpportunityBlock creates a block for a single opportunity. matchedBy
+// lists the queries that matched it when there was more than one.
+func (sn *SlackNotifier) buildOpportunityBlock(opp samgov.Opportunity, matchedBy []string) SlackBlock {
 	// Main text with title and basic info
 	mainText := fmt.Sprintf("*<%s|%s>*\n", opp.UILink, opp.Title)
-	mainText += fmt.Sprintf("Notice ID: `%s` • Type: %s • Posted: %s", 
+	mainText += fmt.Sprintf("Notice ID: `%s` • Type: %s • Posted: %s",
 		opp.NoticeID, opp.Type, opp.PostedDate)
+	if opp.Relevance != nil {
+		mainText += fmt.Sprintf(" • Score: *%g* (%s)", opp.Relevance.Score, opp.Relevance.Priority)
+	}
+
+	if desc := opp.DescriptionBody(); desc != "" {
+		mainText += "\n>" + truncateText(strings.ReplaceAll(desc, "\n", " "), 280)
+	}
+
+	if len(matchedBy) > 0 {
+		mainText += "\nMatched by: " + strings.Join(matchedBy, ", ")
+	}
+
+	if len(opp.Changes) > 0 {
+		mainText += "\n*What changed:*" + sn.formatChanges(opp.Changes)
+	}
 
 	// Additional fields
 	fields := make([]SlackField, 0)
@@ -202,7 +244,14 @@ func (sn *SlackNotifier) buildOpportunityBlock(opp samgov.Opportunity) SlackBloc
 			Text: fmt.Sprintf("*Organization:*\n%s", opp.FullParentPath),
 		})
 	}
-	
+
+	if award := describeAward(opp.Award); award != "" {
+		fields = append(fields, SlackField{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Awarded:* :trophy:\n%s", award),
+		})
+	}
+
 	if opp.ResponseDeadline != nil {
 		deadlineEmoji := ":calendar:"
 		if sn.isUrgentDeadline(*opp.ResponseDeadline) {
@@ -246,6 +295,33 @@ func (sn *SlackNotifier) buildOpportunityBlock(opp samgov.Opportunity) SlackBloc
 	}
 }
 
+// formatChanges renders field changes as bullet lines, old values struck through
+func (sn *SlackNotifier) formatChanges(changes []samgov.FieldChange) string {
+	const maxChanges = 8
+
+	var b strings.Builder
+	for i, change := range changes {
+		if i >= maxChanges {
+			b.WriteString(fmt.Sprintf("\n• _... and %d more changes_", len(changes)-maxChanges))
+			break
+		}
+		if change.Old == "" && change.New == "" {
+			b.WriteString(fmt.Sprintf("\n• *%s* changed", change.Field))
+			continue
+		}
+		old := "(none)"
+		if change.Old != "" {
+			old = "~" + truncateText(change.Old, 100) + "~"
+		}
+		new := "(none)"
+		if change.New != "" {
+			new = truncateText(change.New, 100)
+		}
+		b.WriteString(fmt.Sprintf("\n• *%s:* %s → %s", change.Field, old, new))
+	}
+	return b.String()
+}
+
 // sendWebhook sends the message to Slack webhook
 func (sn *SlackNotifier) sendWebhook(ctx context.Context, message *SlackMessage) error {
 	// Marshal message to JSON
@@ -383,4 +459,13 @@ func BuildSlackTestMessage() *SlackMessage {
 			},
 		},
 	}
-}
\ No newline at end of file
+}
+
+// truncateText shortens text to at most max runes, adding an ellipsis
+func truncateText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:max])) + "…"
+}
@@ -0,0 +1,509 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// Teams rejects webhook payloads over about 28 KB. Cards are split well below
+// that so the header and footer repeated on every part still fit.
+const teamsMaxPayloadBytes = 24 * 1024
+
+// TeamsNotifier implements Microsoft Teams incoming webhook notifications
+// using Adaptive Cards
+type TeamsNotifier struct {
+	config  TeamsConfig
+	verbose bool
+	client  *http.Client
+}
+
+// NewTeamsNotifier creates a new Teams notifier
+func NewTeamsNotifier(config TeamsConfig, verbose bool) *TeamsNotifier {
+	return &TeamsNotifier{
+		config:  config,
+		verbose: verbose,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send sends a Teams notification, as several messages when the card is
+// too large for one
+func (tn *TeamsNotifier) Send(ctx context.Context, notification Notification) error {
+	if !tn.config.Enabled {
+		return nil
+	}
+
+	if tn.verbose {
+		log.Printf("Sending Teams notification: %s", notification.Subject)
+	}
+
+	messages, err := tn.buildTeamsMessages(notification)
+	if err != nil {
+		return fmt.Errorf("building Teams message: %w", err)
+	}
+
+	for i, message := range messages {
+		if err := tn.sendWebhook(ctx, message); err != nil {
+			if len(messages) > 1 {
+				return fmt.Errorf("sending part %d of %d: %w", i+1, len(messages), err)
+			}
+			return err
+		}
+	}
+
+	if tn.verbose {
+		log.Printf("Teams notification sent successfully (%d messages)", len(messages))
+	}
+
+	return nil
+}
+
+// GetType returns the notifier type
+func (tn *TeamsNotifier) GetType() string {
+	return "teams"
+}
+
+// IsEnabled returns whether Teams notifications are enabled
+func (tn *TeamsNotifier) IsEnabled() bool {
+	return tn.config.Enabled
+}
+
+// buildTeamsMessages lays the opportunities out over as many cards as it
+// takes to keep each payload under the Teams size limit. Every card repeats
+// the header and footer; the summary is only on the first.
+func (tn *TeamsNotifier) buildTeamsMessages(notification Notification) ([]*TeamsMessage, error) {
+	footer := tn.buildFooter(notification)
+
+	base, err := json.Marshal(newTeamsMessage(append(tn.buildHeader(notification, 1, 1), footer)))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling card: %w", err)
+	}
+	summary := tn.buildSummary(notification)
+	summarySize, err := elementsSize(summary)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pack opportunities greedily. An opportunity that is too large on its
+	// own still gets a card of its own rather than being dropped.
+	chunks := [][]CardElement{{}}
+	size := len(base) + summarySize
+	for _, opp := range notification.Opportunities {
+		element := tn.buildOpportunityContainer(opp, notification.SharedMatch(opp.NoticeID))
+		elementSize, err := elementsSize([]CardElement{element})
+		if err != nil {
+			return nil, err
+		}
+
+		last := len(chunks) - 1
+		if len(chunks[last]) > 0 && size+elementSize > teamsMaxPayloadBytes {
+			chunks = append(chunks, []CardElement{})
+			last++
+			size = len(base)
+		}
+		chunks[last] = append(chunks[last], element)
+		size += elementSize
+	}
+
+	messages := make([]*TeamsMessage, 0, len(chunks))
+	for i, chunk := range chunks {
+		body := tn.buildHeader(notification, i+1, len(chunks))
+		if i == 0 {
+			body = append(body, summary...)
+		}
+		body = append(body, chunk...)
+		body = append(body, footer)
+		messages = append(messages, newTeamsMessage(body))
+	}
+
+	return messages, nil
+}
+
+// elementsSize returns the encoded size of card elements, counting the
+// separating commas
+func elementsSize(elements []CardElement) (int, error) {
+	size := 0
+	for _, element := range elements {
+		data, err := json.Marshal(element)
+		if err != nil {
+			return 0, fmt.Errorf("marshaling card element: %w", err)
+		}
+		size += len(data) + 1
+	}
+	return size, nil
+}
+
+// buildHeader creates the priority-coloured title banner. part and parts
+// number the cards when a notification is split.
+func (tn *TeamsNotifier) buildHeader(notification Notification, part, parts int) []CardElement {
+	title := fmt.Sprintf("%s %s", tn.getEmojiForPriority(notification.Priority), notification.Subject)
+	if parts > 1 {
+		title += fmt.Sprintf(" (%d of %d)", part, parts)
+	}
+
+	return []CardElement{
+		{
+			Type:  "Container",
+			Style: tn.getStyleForPriority(notification.Priority),
+			Bleed: true,
+			Items: []CardElement{
+				{
+					Type:   "TextBlock",
+					Text:   title,
+					Size:   "Large",
+					Weight: "Bolder",
+					Color:  tn.getColorForPriority(notification.Priority),
+					Wrap:   true,
+				},
+			},
+		},
+	}
+}
+
+// buildSummary creates the fact set with the notification's counts
+func (tn *TeamsNotifier) buildSummary(notification Notification) []CardElement {
+	facts := make([]CardFact, 0)
+
+	if notification.Reminder != "" {
+		facts = append(facts, CardFact{Title: "Deadline In", Value: notification.Reminder})
+	}
+
+	if notification.Summary.NewOpportunities > 0 {
+		facts = append(facts, CardFact{
+			Title: "New Opportunities",
+			Value: fmt.Sprintf("%d", notification.Summary.NewOpportunities),
+		})
+	}
+
+	if notification.Summary.UpdatedOpportunities > 0 {
+		facts = append(facts, CardFact{
+			Title: "Updated Opportunities",
+			Value: fmt.Sprintf("%d", notification.Summary.UpdatedOpportunities),
+		})
+	}
+
+	if notification.Summary.AwardedOpportunities > 0 {
+		facts = append(facts, CardFact{
+			Title: "Awarded Opportunities",
+			Value: fmt.Sprintf("%d", notification.Summary.AwardedOpportunities),
+		})
+	}
+
+	facts = append(facts, CardFact{Title: "Priority", Value: strings.Title(string(notification.Priority))})
+
+	if notification.Summary.UpcomingDeadlines > 0 {
+		facts = append(facts, CardFact{
+			Title: "Upcoming Deadlines",
+			Value: fmt.Sprintf("%d", notification.Summary.UpcomingDeadlines),
+		})
+	}
+
+	return []CardElement{{Type: "FactSet", Facts: facts}}
+}
+
+// buildOpportunityContainer creates the section for a single opportunity.
+// matchedBy lists the queries that matched it when there was more than one.
+func (tn *TeamsNotifier) buildOpportunityContainer(opp samgov.Opportunity, matchedBy []string) CardElement {
+	items := []CardElement{
+		{
+			Type:   "TextBlock",
+			Text:   opp.Title,
+			Weight: "Bolder",
+			Wrap:   true,
+		},
+	}
+
+	meta := fmt.Sprintf("Notice ID: %s • Type: %s • Posted: %s", opp.NoticeID, opp.Type, opp.PostedDate)
+	if opp.Relevance != nil {
+		meta += fmt.Sprintf(" • Score: **%g** (%s)", opp.Relevance.Score, opp.Relevance.Priority)
+	}
+	items = append(items, CardElement{
+		Type:     "TextBlock",
+		Text:     meta,
+		IsSubtle: true,
+		Spacing:  "None",
+		Wrap:     true,
+	})
+
+	if desc := opp.DescriptionBody(); desc != "" {
+		items = append(items, CardElement{
+			Type: "TextBlock",
+			Text: truncateText(strings.ReplaceAll(desc, "\n", " "), 280),
+			Wrap: true,
+		})
+	}
+
+	facts := make([]CardFact, 0)
+	if opp.FullParentPath != "" {
+		facts = append(facts, CardFact{Title: "Agency", Value: opp.FullParentPath})
+	}
+	if opp.NAICSCode != "" {
+		facts = append(facts, CardFact{Title: "NAICS", Value: opp.NAICSCode})
+	}
+	if opp.TypeOfSetAside != "" {
+		facts = append(facts, CardFact{Title: "Set-Aside", Value: opp.TypeOfSetAside})
+	}
+	if award := describeAward(opp.Award); award != "" {
+		facts = append(facts, CardFact{Title: "Awarded", Value: "🏆 " + award})
+	}
+	if opp.ResponseDeadline != nil {
+		deadline := "📅 " + *opp.ResponseDeadline
+		if tn.isUrgentDeadline(*opp.ResponseDeadline) {
+			deadline = "⚠️ " + *opp.ResponseDeadline
+		}
+		facts = append(facts, CardFact{Title: "Deadline", Value: deadline})
+	}
+	if len(matchedBy) > 0 {
+		facts = append(facts, CardFact{Title: "Matched By", Value: strings.Join(matchedBy, ", ")})
+	}
+	if len(facts) > 0 {
+		items = append(items, CardElement{Type: "FactSet", Facts: facts})
+	}
+
+	if len(opp.Changes) > 0 {
+		items = append(items, CardElement{
+			Type:   "TextBlock",
+			Text:   "What changed:",
+			Weight: "Bolder",
+			Wrap:   true,
+		}, CardElement{
+			Type:    "TextBlock",
+			Text:    tn.formatChanges(opp.Changes),
+			Spacing: "None",
+			Wrap:    true,
+		})
+	}
+
+	if opp.UILink != "" {
+		items = append(items, CardElement{
+			Type: "ActionSet",
+			Actions: []CardAction{
+				{Type: "Action.OpenUrl", Title: "Open in SAM.gov", URL: opp.UILink},
+			},
+		})
+	}
+
+	return CardElement{
+		Type:      "Container",
+		Separator: true,
+		Spacing:   "Medium",
+		Items:     items,
+	}
+}
+
+// formatChanges renders field changes as a markdown list. Adaptive Cards
+// have no strikethrough, so old values are shown in italics.
+func (tn *TeamsNotifier) formatChanges(changes []samgov.FieldChange) string {
+	const maxChanges = 8
+
+	lines := make([]string, 0, len(changes))
+	for i, change := range changes {
+		if i >= maxChanges {
+			lines = append(lines, fmt.Sprintf("- _... and %d more changes_", len(changes)-maxChanges))
+			break
+		}
+		if change.Old == "" && change.New == "" {
+			lines = append(lines, fmt.Sprintf("- **%s** changed", change.Field))
+			continue
+		}
+		old := "(none)"
+		if change.Old != "" {
+			old = "_" + truncateText(change.Old, 100) + "_"
+		}
+		new := "(none)"
+		if change.New != "" {
+			new = truncateText(change.New, 100)
+		}
+		lines = append(lines, fmt.Sprintf("- **%s:** %s → %s", change.Field, old, new))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildFooter creates the generated-at line shown at the bottom of each card
+func (tn *TeamsNotifier) buildFooter(notification Notification) CardElement {
+	return CardElement{
+		Type: "TextBlock",
+		Text: fmt.Sprintf("Generated on %s • Query: %s",
+			notification.Timestamp.Format("Jan 2, 2006 at 3:04 PM MST"),
+			notification.QueryName),
+		Size:      "Small",
+		IsSubtle:  true,
+		Separator: true,
+		Wrap:      true,
+	}
+}
+
+// sendWebhook posts one message to the Teams webhook. Classic connectors
+// answer 200 and workflow webhooks 202, so any 2xx is success.
+func (tn *TeamsNotifier) sendWebhook(ctx context.Context, message *TeamsMessage) error {
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshaling message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tn.config.WebhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := tn.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if text := strings.TrimSpace(string(body)); text != "" {
+			return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, text)
+		}
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// getEmojiForPriority returns emoji for priority level
+func (tn *TeamsNotifier) getEmojiForPriority(priority Priority) string {
+	switch priority {
+	case PriorityHigh:
+		return "🚨"
+	case PriorityMedium:
+		return "⚠️"
+	case PriorityLow:
+		return "ℹ️"
+	default:
+		return "🔔"
+	}
+}
+
+// getStyleForPriority returns the container style used for the header banner
+func (tn *TeamsNotifier) getStyleForPriority(priority Priority) string {
+	switch priority {
+	case PriorityHigh:
+		return "attention"
+	case PriorityMedium:
+		return "warning"
+	case PriorityLow:
+		return "accent"
+	default:
+		return "emphasis"
+	}
+}
+
+// getColorForPriority returns the header text colour for priority level
+func (tn *TeamsNotifier) getColorForPriority(priority Priority) string {
+	switch priority {
+	case PriorityHigh:
+		return "Attention"
+	case PriorityMedium:
+		return "Warning"
+	case PriorityLow:
+		return "Accent"
+	default:
+		return "Default"
+	}
+}
+
+// isUrgentDeadline checks if a deadline is within 7 days
+func (tn *TeamsNotifier) isUrgentDeadline(deadlineStr string) bool {
+	deadline, ok := samgov.ParseDate(deadlineStr)
+	if !ok {
+		return false
+	}
+
+	return time.Until(deadline) <= 7*24*time.Hour
+}
+
+// Teams message structures
+
+// TeamsMessage represents a Teams webhook message carrying one Adaptive Card
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment wraps an Adaptive Card in a Teams message
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	ContentURL  *string      `json:"contentUrl"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard represents an Adaptive Card
+type AdaptiveCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []CardElement `json:"body"`
+	MSTeams *CardMSTeams  `json:"msteams,omitempty"`
+}
+
+// CardMSTeams holds Teams-specific card settings
+type CardMSTeams struct {
+	Width string `json:"width,omitempty"`
+}
+
+// CardElement represents an Adaptive Card element: a TextBlock, FactSet,
+// Container or ActionSet
+type CardElement struct {
+	Type      string        `json:"type"`
+	Text      string        `json:"text,omitempty"`
+	Size      string        `json:"size,omitempty"`
+	Weight    string        `json:"weight,omitempty"`
+	Color     string        `json:"color,omitempty"`
+	IsSubtle  bool          `json:"isSubtle,omitempty"`
+	Wrap      bool          `json:"wrap,omitempty"`
+	Spacing   string        `json:"spacing,omitempty"`
+	Separator bool          `json:"separator,omitempty"`
+	Style     string        `json:"style,omitempty"`
+	Bleed     bool          `json:"bleed,omitempty"`
+	Items     []CardElement `json:"items,omitempty"`
+	Facts     []CardFact    `json:"facts,omitempty"`
+	Actions   []CardAction  `json:"actions,omitempty"`
+}
+
+// CardFact represents one title/value pair in a FactSet
+type CardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// CardAction represents an Adaptive Card action
+type CardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+}
+
+// newTeamsMessage wraps card body elements in a full-width Adaptive Card
+func newTeamsMessage(body []CardElement) *TeamsMessage {
+	return &TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: AdaptiveCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body:    body,
+					MSTeams: &CardMSTeams{Width: "Full"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,455 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// Kinds of user-supplied template. The template "brief" is made of the files
+// brief.email.tmpl, brief.github.tmpl and brief.slack.tmpl in the template
+// directory, any of which may be left out.
+const (
+	TemplateEmail  = "email"  // HTML email body, parsed with html/template
+	TemplateGitHub = "github" // Markdown issue body, parsed with text/template
+	TemplateSlack  = "slack"  // Slack mrkdwn message text, parsed with text/template
+)
+
+// TemplateKinds lists every kind of user-supplied template
+var TemplateKinds = []string{TemplateEmail, TemplateGitHub, TemplateSlack}
+
+// templateExt is the file extension of user-supplied templates
+const templateExt = ".tmpl"
+
+// templateName restricts template names to plain file name stems
+var templateName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// TemplateData is the data user-supplied templates are executed with. It has
+// every field of EmailTemplateData, so {{.QueryName}}, {{.Opportunities}},
+// {{.Summary.NewOpportunities}}, {{.Reminder}} and the rest work in all
+// three kinds of template, plus the fields GitHub issues need.
+type TemplateData struct {
+	EmailTemplateData
+	Opportunity  samgov.Opportunity `json:"opportunity,omitempty"` // The notice of an individual GitHub issue
+	IsIndividual bool               `json:"is_individual"`         // True for one-issue-per-notice GitHub issues
+	Channel      string             `json:"channel"`               // email, github or slack
+}
+
+// newTemplateData builds the template data for a notification rendered for
+// a channel
+func newTemplateData(notification Notification, channel string) TemplateData {
+	return TemplateData{
+		EmailTemplateData: EmailTemplateData{
+			QueryName:     notification.QueryName,
+			Subject:       notification.Subject,
+			Opportunities: notification.Opportunities,
+			FilteredOut:   notification.FilteredOut,
+			Summary:       notification.Summary,
+			Priority:      string(notification.Priority),
+			PriorityClass: priorityClass(notification.Priority),
+			MatchedBy:     notification.MatchedBy,
+			Reminder:      notification.Reminder,
+			Timestamp:     notification.Timestamp,
+		},
+		Channel: channel,
+	}
+}
+
+// TemplateFuncs returns the helper functions available to every template,
+// built-in or user-supplied:
+//
+//	formatDeadline  a deadline or date as "Jan 2, 2006", with the time when it has one
+//	daysUntil       whole days from today until a deadline, negative once it has passed
+//	money           an amount such as an award's as "$1,234,567"
+//	award           an award's winner, amount and date, "" without one
+//	join, title     strings.Join and strings.Title
+//	shared          every query that matched a notice, when more than one did
+//	mdcell          a value made safe for a Markdown table cell
+//	add             the sum of two integers
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"formatDeadline": formatDeadline,
+		"daysUntil":      daysUntil,
+		"money":          money,
+		"award":          describeAward,
+		"join":           strings.Join,
+		"title":          strings.Title,
+		"shared":         sharedMatch,
+		"mdcell":         markdownCell,
+		"add":            func(a, b int) int { return a + b },
+	}
+}
+
+// templateDate parses the date arguments template helpers accept: SAM.gov
+// date strings, pointers to them and times
+func templateDate(value interface{}) (time.Time, string, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, "", !v.IsZero()
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, "", false
+		}
+		return *v, "", !v.IsZero()
+	case *string:
+		if v == nil {
+			return time.Time{}, "", false
+		}
+		return templateDate(*v)
+	case string:
+		t, ok := samgov.ParseDate(v)
+		return t, strings.TrimSpace(v), ok
+	}
+	return time.Time{}, "", false
+}
+
+// formatDeadline renders a deadline for people, returning unparseable values
+// as they are and "" when there is no deadline
+func formatDeadline(value interface{}) string {
+	t, raw, ok := templateDate(value)
+	if !ok {
+		return raw
+	}
+	if (raw == "" || len(raw) > 10) && (t.Hour() != 0 || t.Minute() != 0) {
+		return t.Format("Jan 2, 2006 3:04 PM MST")
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// daysUntil counts calendar days from today (UTC) until the deadline's date,
+// returning 0 when there is no deadline
+func daysUntil(value interface{}) int {
+	t, _, ok := templateDate(value)
+	if !ok {
+		return 0
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(math.Round(day.Sub(today).Hours() / 24))
+}
+
+// money renders an amount in dollars with thousands separators, showing
+// cents only when there are some. Award amounts arrive as numbers or
+// strings; strings that are not numbers are returned as they are.
+func money(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	amount, ok := samgov.ParseAmount(value)
+	if !ok {
+		if text, isString := value.(string); isString {
+			return text
+		}
+		return fmt.Sprint(value)
+	}
+	return samgov.FormatDollars(amount)
+}
+
+// UserTemplates is one named set of user-supplied templates
+type UserTemplates struct {
+	Name   string
+	email  *htmltemplate.Template
+	github *template.Template
+	slack  *template.Template
+}
+
+// LoadTemplates parses the templates called name in dir and checks that each
+// renders every kind of notification. At least one kind must exist.
+func LoadTemplates(dir, name string) (*UserTemplates, error) {
+	if !templateName.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name '%s': use letters, digits, '-' and '_'", name)
+	}
+
+	set := &UserTemplates{Name: name}
+	found := 0
+	for _, kind := range TemplateKinds {
+		path := filepath.Join(dir, name+"."+kind+templateExt)
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+		if err := set.parse(kind, filepath.Base(path), string(content)); err != nil {
+			return nil, err
+		}
+		found++
+	}
+
+	if found == 0 {
+		return nil, fmt.Errorf("template '%s' not found: no %s.{email,github,slack}%s in %s", name, name, templateExt, dir)
+	}
+	if err := set.validate(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// parse compiles one kind of template
+func (ut *UserTemplates) parse(kind, file, content string) error {
+	var err error
+	switch kind {
+	case TemplateEmail:
+		ut.email, err = htmltemplate.New(file).Funcs(TemplateFuncs()).Parse(content)
+	case TemplateGitHub:
+		ut.github, err = template.New(file).Funcs(TemplateFuncs()).Parse(content)
+	case TemplateSlack:
+		ut.slack, err = template.New(file).Funcs(TemplateFuncs()).Parse(content)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	return nil
+}
+
+// Has reports whether the set has a template of the given kind
+func (ut *UserTemplates) Has(kind string) bool {
+	switch kind {
+	case TemplateEmail:
+		return ut.email != nil
+	case TemplateGitHub:
+		return ut.github != nil
+	case TemplateSlack:
+		return ut.slack != nil
+	}
+	return false
+}
+
+// Execute renders the template of the given kind
+func (ut *UserTemplates) Execute(kind string, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := ut.execute(&buf, kind, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (ut *UserTemplates) execute(w io.Writer, kind string, data TemplateData) error {
+	if !ut.Has(kind) {
+		return fmt.Errorf("template '%s' has no %s template", ut.Name, kind)
+	}
+
+	var err error
+	switch kind {
+	case TemplateEmail:
+		err = ut.email.Execute(w, data)
+	case TemplateGitHub:
+		err = ut.github.Execute(w, data)
+	case TemplateSlack:
+		err = ut.slack.Execute(w, data)
+	}
+	if err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+// validate executes each template against sample notifications: new,
+// updated and reminder notifications, individual and summary issues, and
+// notices with and without optional fields such as the deadline and award.
+// Templates only report unknown fields and nil dereferences on the branches
+// they take, so this catches mistakes before a real notification fails.
+func (ut *UserTemplates) validate() error {
+	for _, kind := range TemplateKinds {
+		if !ut.Has(kind) {
+			continue
+		}
+		for _, sample := range sampleTemplateData(kind) {
+			if err := ut.execute(io.Discard, kind, sample); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sampleTemplateData returns the sample notifications templates are
+// validated with
+func sampleTemplateData(channel string) []TemplateData {
+	deadline := time.Now().AddDate(0, 0, 10).Format("2006-01-02T15:04:05-07:00")
+	full := samgov.Opportunity{
+		NoticeID:         "sample0000000000000000000000001",
+		Title:            "Sample Software Development Services",
+		SolicitationNum:  "SAMPLE-0001",
+		FullParentPath:   "SAMPLE DEPARTMENT.SAMPLE AGENCY",
+		PostedDate:       time.Now().Format("2006-01-02"),
+		Type:             "Solicitation",
+		ResponseDeadline: &deadline,
+		UILink:           "https://sam.gov/opp/sample/view",
+		Active:           "Yes",
+		Description:      "https://api.sam.gov/prod/opportunities/v1/noticedesc?noticeid=sample",
+		DescriptionText:  "Sample description.",
+		PointOfContact: []samgov.Contact{
+			{FullName: "Sample Contact", Title: "Contracting Officer", Email: "contact@example.gov", Phone: "555-0100", Type: "primary"},
+		},
+		Award: &samgov.Award{Date: "2024-01-15", Number: "SAMPLE-AWARD", Amount: 1250000.0,
+			Awardee: &samgov.Awardee{Name: "Sample Contractor LLC", UEISAM: "SAMPLEUEI001"}},
+		PlaceOfPerformance: &samgov.Place{City: "Washington", State: "DC", ZipCode: "20001", Country: "USA"},
+		TypeOfSetAside:     "SBA",
+		NAICSCode:          "541511",
+		ResourceLinks:      []string{"https://sam.gov/api/prod/opps/v3/opportunities/resources/files/sample/download"},
+		Relevance:          &samgov.Relevance{Score: 12, Priority: "high", Reasons: []string{"title matches \"software\" (+5)"}},
+	}
+	minimal := samgov.Opportunity{
+		NoticeID:   "sample0000000000000000000000002",
+		Title:      "Sample Notice Without Optional Fields",
+		PostedDate: time.Now().Format("2006-01-02"),
+		Type:       "Sources Sought",
+		Active:     "Yes",
+	}
+	updated := full
+	updated.Changes = []samgov.FieldChange{{Field: "Response Deadline", Old: "2024-01-01", New: deadline}}
+
+	base := func(priority Priority, opportunities ...samgov.Opportunity) TemplateData {
+		notification := Notification{
+			QueryName:     "Sample Query",
+			Priority:      priority,
+			Subject:       "Sample SAM.gov notification",
+			Opportunities: opportunities,
+			Summary:       NotificationSummary{NewOpportunities: len(opportunities), UpcomingDeadlines: 1},
+			MatchedBy:     map[string][]string{full.NoticeID: {"Sample Query", "Other Query"}},
+			Timestamp:     time.Now(),
+		}
+		return newTemplateData(notification, channel)
+	}
+
+	newData := base(PriorityHigh, full, minimal)
+	newData.FilteredOut = []samgov.Opportunity{minimal}
+	newData.Summary.FilteredOpportunities = 1
+
+	updatedData := base(PriorityMedium, updated)
+	updatedData.Summary = NotificationSummary{UpdatedOpportunities: 1}
+
+	reminderData := base(PriorityLow, full)
+	reminderData.Reminder = "3 days"
+	reminderData.Summary = NotificationSummary{UpcomingDeadlines: 1}
+
+	awarded := full
+	awarded.Type = "Award Notice"
+	awardedData := base(PriorityMedium, awarded, minimal)
+	awardedData.Summary = NotificationSummary{AwardedOpportunities: 2}
+
+	samples := []TemplateData{newData, updatedData, reminderData, awardedData, base(PriorityLow)}
+	if channel == TemplateGitHub {
+		for _, opp := range []samgov.Opportunity{full, minimal, updated} {
+			individual := base(PriorityHigh, opp)
+			individual.Opportunity = opp
+			individual.IsIndividual = true
+			samples = append(samples, individual)
+		}
+	}
+	return samples
+}
+
+// TemplateLibrary loads user-supplied templates from a directory on first
+// use and keeps them for the life of the process
+type TemplateLibrary struct {
+	dir  string
+	mu   sync.Mutex
+	sets map[string]*UserTemplates
+}
+
+// NewTemplateLibrary creates a library reading templates from dir
+func NewTemplateLibrary(dir string) *TemplateLibrary {
+	return &TemplateLibrary{
+		dir:  dir,
+		sets: make(map[string]*UserTemplates),
+	}
+}
+
+// Dir returns the directory templates are read from
+func (tl *TemplateLibrary) Dir() string {
+	return tl.dir
+}
+
+// Get returns the named set of templates, loading it on first use
+func (tl *TemplateLibrary) Get(name string) (*UserTemplates, error) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if set, ok := tl.sets[name]; ok {
+		return set, nil
+	}
+	set, err := LoadTemplates(tl.dir, name)
+	if err != nil {
+		return nil, err
+	}
+	tl.sets[name] = set
+	return set, nil
+}
+
+// templateSelector picks the user-supplied template a notifier renders a
+// notification with: the query's template first, then the channel's. Either
+// is skipped when it has no template for the notifier's kind, leaving the
+// built-in template.
+type templateSelector struct {
+	library  *TemplateLibrary
+	template string // Channel template, from the notifier's config
+}
+
+// SetTemplateLibrary sets where user-supplied templates are loaded from
+func (ts *templateSelector) SetTemplateLibrary(library *TemplateLibrary) {
+	ts.library = library
+}
+
+// render executes the selected template of the given kind, reporting false
+// when there is none and the built-in template should be used
+func (ts *templateSelector) render(kind string, notification Notification, data TemplateData) (string, bool, error) {
+	if ts.library == nil {
+		return "", false, nil
+	}
+
+	for _, name := range []string{notification.Template, ts.template} {
+		if name == "" {
+			continue
+		}
+		set, err := ts.library.Get(name)
+		if err != nil {
+			return "", false, err
+		}
+		if !set.Has(kind) {
+			continue
+		}
+		out, err := set.Execute(kind, data)
+		if err != nil {
+			return "", false, fmt.Errorf("template '%s': %w", name, err)
+		}
+		return out, true, nil
+	}
+	return "", false, nil
+}
+
+// templateUser is implemented by notifiers that render user-supplied
+// templates
+type templateUser interface {
+	SetTemplateLibrary(library *TemplateLibrary)
+}
+
+// SetTemplateLibrary gives every notifier that renders templates, including
+// named channels registered so far, the user-supplied template library
+func (nm *NotificationManager) SetTemplateLibrary(library *TemplateLibrary) {
+	for _, notifier := range nm.notifiers {
+		if user, ok := notifier.(templateUser); ok {
+			user.SetTemplateLibrary(library)
+		}
+	}
+	for _, notifier := range nm.channels {
+		if user, ok := notifier.(templateUser); ok {
+			user.SetTemplateLibrary(library)
+		}
+	}
+}
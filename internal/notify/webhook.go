@@ -0,0 +1,301 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// WebhookPayloadVersion is bumped whenever a field of WebhookPayload is
+// renamed or removed. Adding fields does not change it.
+const WebhookPayloadVersion = 1
+
+// Webhook request headers. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret, prefixed "sha256=".
+const (
+	WebhookSignatureHeader   = "X-SAM-Monitor-Signature"
+	WebhookTimestampHeader   = "X-SAM-Monitor-Timestamp"
+	WebhookEventHeader       = "X-SAM-Monitor-Event"
+	WebhookIdempotencyHeader = "Idempotency-Key"
+)
+
+// Webhook retry defaults, used when the config leaves them unset
+const (
+	DefaultWebhookMaxRetries = 3
+	DefaultWebhookRetryDelay = 2 * time.Second
+	maxWebhookRetryDelay     = 60 * time.Second
+)
+
+// WebhookNotifier posts notifications as signed JSON to an HTTP endpoint
+type WebhookNotifier struct {
+	config  WebhookConfig
+	verbose bool
+	client  *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(config WebhookConfig, verbose bool) *WebhookNotifier {
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultWebhookMaxRetries
+	} else if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = DefaultWebhookRetryDelay
+	}
+
+	return &WebhookNotifier{
+		config:  config,
+		verbose: verbose,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send posts the notification, retrying server errors and rate limits
+func (wn *WebhookNotifier) Send(ctx context.Context, notification Notification) error {
+	if !wn.config.Enabled {
+		return nil
+	}
+
+	if wn.verbose {
+		log.Printf("Sending webhook notification: %s", notification.Subject)
+	}
+
+	payload := BuildWebhookPayload(notification)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling webhook payload: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= wn.config.MaxRetries; attempt++ {
+		retryAfter, err := wn.post(ctx, payload, body)
+		if err == nil {
+			if wn.verbose {
+				log.Printf("Webhook notification sent successfully (id %s)", payload.ID)
+			}
+			return nil
+		}
+		lastErr = err
+
+		var retryable *retryableWebhookError
+		if !errors.As(err, &retryable) || attempt == wn.config.MaxRetries {
+			break
+		}
+
+		delay := wn.calculateDelay(attempt, retryAfter)
+		if wn.verbose {
+			log.Printf("Webhook failed (attempt %d/%d), retrying in %v: %v",
+				attempt+1, wn.config.MaxRetries+1, delay, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return fmt.Errorf("webhook failed: %w", lastErr)
+}
+
+// GetType returns the notifier type
+func (wn *WebhookNotifier) GetType() string {
+	return "webhook"
+}
+
+// IsEnabled returns whether webhook notifications are enabled
+func (wn *WebhookNotifier) IsEnabled() bool {
+	return wn.config.Enabled
+}
+
+// post makes one delivery attempt. Network errors, 429 and 5xx responses are
+// wrapped in retryableWebhookError; retryAfter is the delay the server asked
+// for, if any.
+func (wn *WebhookNotifier) post(ctx context.Context, payload WebhookPayload, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", wn.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	// Custom headers first so they cannot override the signature
+	for name, value := range wn.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SAM.gov-Monitor/1.0")
+	req.Header.Set(WebhookEventHeader, payload.Event)
+	req.Header.Set(WebhookIdempotencyHeader, payload.ID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if wn.config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(wn.config.Secret, timestamp, body))
+	}
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, err
+		}
+		return 0, &retryableWebhookError{err: fmt.Errorf("sending webhook: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+
+	err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if text := strings.TrimSpace(string(respBody)); text != "" {
+		err = fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, text)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		retryAfter := time.Duration(0)
+		if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return retryAfter, &retryableWebhookError{err: err}
+	}
+	return 0, err
+}
+
+// calculateDelay doubles the retry delay on each attempt, or waits as long
+// as the server asked to, capped at a minute
+func (wn *WebhookNotifier) calculateDelay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := wn.config.RetryDelay << uint(attempt)
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	if delay > maxWebhookRetryDelay || delay <= 0 {
+		delay = maxWebhookRetryDelay
+	}
+	return delay
+}
+
+// retryableWebhookError marks a delivery failure worth retrying
+type retryableWebhookError struct {
+	err error
+}
+
+func (e *retryableWebhookError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableWebhookError) Unwrap() error {
+	return e.err
+}
+
+// SignWebhook returns the signature header value for a request body sent at
+// timestamp (Unix seconds). Receivers recompute it with the shared secret and
+// compare with hmac.Equal, rejecting stale timestamps to stop replays.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookPayload is the JSON body posted by the webhook notifier
+type WebhookPayload struct {
+	Version       int                    `json:"version"`
+	ID            string                 `json:"id"`    // Idempotency key, also sent as a header
+	Event         string                 `json:"event"` // new, updated, awarded, reminder or digest
+	Query         string                 `json:"query"`
+	Priority      Priority               `json:"priority"`
+	Subject       string                 `json:"subject"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Reminder      string                 `json:"reminder,omitempty"`
+	Summary       NotificationSummary    `json:"summary"`
+	Opportunities []samgov.Opportunity   `json:"opportunities"`
+	FilteredOut   []samgov.Opportunity   `json:"filtered_out,omitempty"`
+	MatchedBy     map[string][]string    `json:"matched_by,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// BuildWebhookPayload converts a notification to the webhook JSON body
+func BuildWebhookPayload(notification Notification) WebhookPayload {
+	event := webhookEvent(notification)
+	opportunities := notification.Opportunities
+	if opportunities == nil {
+		opportunities = []samgov.Opportunity{}
+	}
+
+	return WebhookPayload{
+		Version:       WebhookPayloadVersion,
+		ID:            webhookIdempotencyKey(event, notification),
+		Event:         event,
+		Query:         notification.QueryName,
+		Priority:      notification.Priority,
+		Subject:       notification.Subject,
+		Timestamp:     notification.Timestamp.UTC(),
+		Reminder:      notification.Reminder,
+		Summary:       notification.Summary,
+		Opportunities: opportunities,
+		FilteredOut:   notification.FilteredOut,
+		MatchedBy:     notification.MatchedBy,
+		Metadata:      notification.Metadata,
+	}
+}
+
+// webhookEvent names the kind of notification from its metadata
+func webhookEvent(notification Notification) string {
+	if digest, _ := notification.Metadata["digest"].(bool); digest {
+		return "digest"
+	}
+	if notification.Reminder != "" {
+		return "reminder"
+	}
+	if queryType, ok := notification.Metadata["query_type"].(string); ok && queryType != "" {
+		return queryType
+	}
+	if notification.Summary.UpdatedOpportunities > 0 {
+		return "updated"
+	}
+	if notification.Summary.AwardedOpportunities > 0 {
+		return "awarded"
+	}
+	return "new"
+}
+
+// webhookIdempotencyKey derives a stable ID from the query and notice IDs, so
+// a receiver can drop a notification delivered twice. The event, reminder
+// offset and update changes are included so that a later reminder or
+// amendment of the same notices gets a new key.
+func webhookIdempotencyKey(event string, notification Notification) string {
+	ids := make([]string, 0, len(notification.Opportunities))
+	for _, opp := range notification.Opportunities {
+		id := opp.NoticeID
+		for _, change := range opp.Changes {
+			id += "|" + change.String()
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", event, notification.QueryName, notification.Reminder)
+	for _, id := range ids {
+		fmt.Fprintf(hash, "%s\n", id)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
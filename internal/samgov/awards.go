@@ -0,0 +1,124 @@
+package samgov
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AwardRecord is an award notice recorded by a query that tracks awards. It
+// is linked to the tracked solicitation with the same solicitation number,
+// when there is one.
+type AwardRecord struct {
+	NoticeID           string    `json:"notice_id"` // The award notice
+	Title              string    `json:"title"`
+	SolicitationNumber string    `json:"solicitation_number,omitempty"`
+	Agency             string    `json:"agency,omitempty"`
+	NAICSCode          string    `json:"naics_code,omitempty"`
+	SetAside           string    `json:"set_aside,omitempty"`
+	Awardee            string    `json:"awardee,omitempty"`
+	AwardeeUEI         string    `json:"awardee_uei,omitempty"`
+	Amount             float64   `json:"amount,omitempty"` // Zero when the notice gives no amount
+	AwardNumber        string    `json:"award_number,omitempty"`
+	AwardDate          string    `json:"award_date,omitempty"`
+	LinkedNoticeID     string    `json:"linked_notice_id,omitempty"` // The tracked solicitation that was awarded
+	Query              string    `json:"query"`
+	UILink             string    `json:"ui_link,omitempty"`
+	RecordedAt         time.Time `json:"recorded_at"`
+}
+
+// NewAwardRecord captures the award details of an award notice
+func NewAwardRecord(opp Opportunity, queryName string, now time.Time) AwardRecord {
+	record := AwardRecord{
+		NoticeID:           opp.NoticeID,
+		Title:              opp.Title,
+		SolicitationNumber: opp.SolicitationNum,
+		Agency:             opp.FullParentPath,
+		NAICSCode:          opp.NAICSCode,
+		SetAside:           opp.TypeOfSetAside,
+		Query:              queryName,
+		UILink:             opp.UILink,
+		RecordedAt:         now,
+	}
+	if opp.Award != nil {
+		record.AwardNumber = opp.Award.Number
+		record.AwardDate = opp.Award.Date
+		record.Amount, _ = ParseAmount(opp.Award.Amount)
+		if opp.Award.Awardee != nil {
+			record.Awardee = strings.TrimSpace(opp.Award.Awardee.Name)
+			record.AwardeeUEI = opp.Award.Awardee.UEISAM
+		}
+	}
+	return record
+}
+
+// IsAwardNotice reports whether a notice type is an award, given by name or
+// by its SAM.gov code
+func IsAwardNotice(noticeType string) bool {
+	return strings.EqualFold(noticeType, "Award Notice") || strings.EqualFold(noticeType, "a")
+}
+
+// NormalizeSolicitationNumber reduces a solicitation number to its letters
+// and digits in upper case, since award notices often write the number of
+// the solicitation they award without its dashes or spaces
+func NormalizeSolicitationNumber(number string) string {
+	var b strings.Builder
+	for _, r := range number {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// ParseAmount reads a dollar amount given as a number or as a string such
+// as "$1,250,000.00"
+func ParseAmount(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		cleaned := strings.NewReplacer("$", "", ",", "", " ", "").Replace(v)
+		if cleaned == "" {
+			return 0, false
+		}
+		amount, err := strconv.ParseFloat(cleaned, 64)
+		if err != nil {
+			return 0, false
+		}
+		return amount, true
+	}
+	return 0, false
+}
+
+// FormatDollars renders an amount as "$1,234,567", showing cents only when
+// there are some
+func FormatDollars(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	digits := strconv.FormatInt(cents/100, 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if cents%100 != 0 {
+		return fmt.Sprintf("%s$%s.%02d", sign, grouped.String(), cents%100)
+	}
+	return sign + "$" + grouped.String()
+}
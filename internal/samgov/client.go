@@ -25,8 +25,15 @@ type Client struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	limiter    *RateLimiter
+	observer   RequestObserver
+	noRetry    bool // Set when a RetryClient does the retrying
 }
 
+// RequestObserver is told how long each HTTP attempt took and its error,
+// nil when the API answered with results
+type RequestObserver func(duration time.Duration, err error)
+
 // NewClient creates a new SAM.gov API client
 func NewClient(apiKey string) *Client {
 	return &Client{
@@ -56,20 +63,38 @@ func NewClientWithOptions(apiKey, baseURL string, timeout time.Duration) *Client
 	}
 }
 
+// SetRateLimiter makes every HTTP attempt wait on and report to limiter
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.limiter = limiter
+}
+
+// SetRequestObserver reports every HTTP attempt, including retries, to observer
+func (c *Client) SetRequestObserver(observer RequestObserver) {
+	c.observer = observer
+}
+
+// observe reports an attempt that started at start to the observer, if any
+func (c *Client) observe(start time.Time, err error) {
+	if c.observer != nil {
+		c.observer(time.Since(start), err)
+	}
+}
+
 // Search executes a search query against the SAM.gov API with retry logic
 func (c *Client) Search(ctx context.Context, params map[string]string) (*SearchResponse, error) {
 	if c.apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	// Retry configuration with environment variable overrides
+	// Retry configuration with environment variable overrides. A RetryClient
+	// wrapping this client applies them itself, so retries are not stacked.
 	maxRetries := 0 // Default to no retries to preserve daily quota
-	if envRetries := os.Getenv("SAM_MAX_RETRIES"); envRetries != "" {
+	if envRetries := os.Getenv("SAM_MAX_RETRIES"); envRetries != "" && !c.noRetry {
 		if n, err := strconv.Atoi(envRetries); err == nil && n >= 0 {
 			maxRetries = n
 		}
 	}
-	
+
 	baseDelay := 5 * time.Second
 	if envDelay := os.Getenv("SAM_RATE_LIMIT_DELAY"); envDelay != "" {
 		if d, err := time.ParseDuration(envDelay); err == nil {
@@ -109,35 +134,51 @@ func (c *Client) Search(ctx context.Context, params map[string]string) (*SearchR
 		req.Header.Set("User-Agent", userAgent)
 		req.Header.Set("Accept", "application/json")
 
+		// Every attempt, including retries, is charged against the rate limiter
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
 		// Execute request
+		start := time.Now()
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			c.observe(start, err)
 			if attempt < maxRetries && IsRetryableError(err) {
 				delay := time.Duration(1<<attempt) * baseDelay
-				time.Sleep(delay)
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, fmt.Errorf("executing request: %w", err)
 		}
 
-		// Log rate limit headers if available
-		if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
-			log.Printf("Rate limit remaining: %s", remaining)
-		}
-		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-			log.Printf("Retry-After header: %s", retryAfter)
+		// Feed rate limit headers back to the limiter, or just log them
+		if c.limiter != nil {
+			c.limiter.Observe(resp.Header)
+		} else {
+			if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+				log.Printf("Rate limit remaining: %s", remaining)
+			}
+			if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+				log.Printf("Retry-After header: %s", retryAfter)
+			}
 		}
-		
+
 		// Check status code
 		if resp.StatusCode != http.StatusOK {
 			resp.Body.Close()
-			
+
 			apiErr := &APIError{
 				StatusCode: resp.StatusCode,
 				Message:    fmt.Sprintf("API returned status %d", resp.StatusCode),
 				Details:    resp.Status,
 			}
-			
+			c.observe(start, apiErr)
+
 			// Retry on rate limit errors with exponential backoff + jitter
 			if resp.StatusCode == 429 && attempt < maxRetries {
 				// Calculate delay with exponential backoff
@@ -146,12 +187,14 @@ func (c *Client) Search(ctx context.Context, params map[string]string) (*SearchR
 				// Add jitter (random 0-50% additional delay)
 				jitter := time.Duration(rand.Float64() * 0.5 * float64(delay))
 				totalDelay := delay + jitter
-				
+
 				log.Printf("Received 429 rate limit error, retrying in %v (attempt %d/%d)", totalDelay, attempt+1, maxRetries)
-				time.Sleep(totalDelay)
+				if err := sleepContext(ctx, totalDelay); err != nil {
+					return nil, err
+				}
 				continue
 			}
-			
+
 			return nil, apiErr
 		}
 
@@ -159,9 +202,11 @@ func (c *Client) Search(ctx context.Context, params map[string]string) (*SearchR
 		var result SearchResponse
 		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 			resp.Body.Close()
+			c.observe(start, err)
 			return nil, fmt.Errorf("decoding response: %w", err)
 		}
 		resp.Body.Close()
+		c.observe(start, nil)
 
 		return &result, nil
 	}
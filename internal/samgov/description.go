@@ -0,0 +1,254 @@
+package samgov
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxDescriptions caps description requests per query when the query does not set one
+const DefaultMaxDescriptions = 5
+
+// maxDescriptionBytes bounds how much of a description response is read
+const maxDescriptionBytes = 1 << 20
+
+// IsDescriptionURL reports whether a search result's description field is a
+// link to the notice-description API rather than the text itself
+func IsDescriptionURL(description string) bool {
+	return strings.HasPrefix(description, "http") && strings.Contains(description, "noticedesc")
+}
+
+// DescriptionBody returns the resolved description text, or the description
+// field itself when it already holds text rather than a link
+func (o Opportunity) DescriptionBody() string {
+	if o.DescriptionText != "" {
+		return o.DescriptionText
+	}
+	if IsDescriptionURL(o.Description) {
+		return ""
+	}
+	return o.Description
+}
+
+// FetchDescription downloads the description behind a noticedesc link and
+// returns it as plain text. A notice without a description returns "".
+func (c *Client) FetchDescription(ctx context.Context, descriptionURL string) (string, error) {
+	if c.apiKey == "" {
+		return "", fmt.Errorf("API key is required")
+	}
+
+	u, err := url.Parse(descriptionURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing description URL: %w", err)
+	}
+	c.setAPIKey(u)
+
+	// Description requests count against the same quota as searches
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	userAgent := DefaultUserAgent
+	if envUA := os.Getenv("SAM_USER_AGENT"); envUA != "" {
+		userAgent = envUA
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.keyGuardedHTTPClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if c.limiter != nil {
+		c.limiter.Observe(resp.Header)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("API returned status %d", resp.StatusCode),
+			Details:    resp.Status,
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDescriptionBytes))
+	if err != nil {
+		return "", fmt.Errorf("reading description: %w", err)
+	}
+
+	// The endpoint normally wraps the HTML body in {"description": "..."}
+	raw := string(body)
+	var wrapped struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil {
+		raw = wrapped.Description
+	}
+
+	text := HTMLToText(raw)
+	if strings.EqualFold(text, "description not found") {
+		return "", nil
+	}
+	return text, nil
+}
+
+var (
+	blockTagPattern = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/li|/h[1-6]|/tr)[^>]*>`)
+	tagPattern      = regexp.MustCompile(`<[^>]*>`)
+	spacePattern    = regexp.MustCompile(`[ \t\r\f\v]+`)
+	newlinePattern  = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// HTMLToText strips markup from a description, keeping paragraph breaks
+func HTMLToText(s string) string {
+	s = blockTagPattern.ReplaceAllString(s, "\n")
+	s = tagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+	s = spacePattern.ReplaceAllString(s, " ")
+	s = newlinePattern.ReplaceAllString(s, "\n\n")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// DescriptionFetcher resolves description links and caches the text on disk,
+// so each version of a notice's description is only downloaded once
+type DescriptionFetcher struct {
+	client   *Client
+	cacheDir string
+	verbose  bool
+
+	mu     sync.Mutex
+	memory map[string]string
+}
+
+// NewDescriptionFetcher creates a fetcher. An empty cacheDir keeps the cache in memory only.
+func NewDescriptionFetcher(client *Client, cacheDir string, verbose bool) (*DescriptionFetcher, error) {
+	if cacheDir != "" {
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("creating description cache directory: %w", err)
+		}
+	}
+
+	return &DescriptionFetcher{
+		client:   client,
+		cacheDir: cacheDir,
+		verbose:  verbose,
+		memory:   make(map[string]string),
+	}, nil
+}
+
+// Enrich sets DescriptionText on opportunities whose description is a link.
+// Cached descriptions are free; at most maxFetches are downloaded. It stops
+// early, without error, when the request budget runs out.
+func (df *DescriptionFetcher) Enrich(ctx context.Context, opportunities []Opportunity, maxFetches int) (fetched int, err error) {
+	skipped := 0
+	for i := range opportunities {
+		opp := &opportunities[i]
+		if opp.DescriptionText != "" || !IsDescriptionURL(opp.Description) {
+			continue
+		}
+
+		if text, ok := df.cached(*opp); ok {
+			opp.DescriptionText = text
+			continue
+		}
+
+		if fetched >= maxFetches {
+			skipped++
+			continue
+		}
+
+		text, err := df.client.FetchDescription(ctx, opp.Description)
+		if err != nil {
+			if errors.Is(err, ErrBudgetExhausted) || ctx.Err() != nil {
+				log.Printf("Stopped fetching descriptions after %d: %v", fetched, err)
+				return fetched, nil
+			}
+			if df.verbose {
+				log.Printf("Failed to fetch description for %s: %v", opp.NoticeID, err)
+			}
+			continue
+		}
+
+		fetched++
+		opp.DescriptionText = text
+		df.store(*opp, text)
+	}
+
+	if skipped > 0 && df.verbose {
+		log.Printf("Description limit reached: %d descriptions not fetched", skipped)
+	}
+	return fetched, nil
+}
+
+// cacheKey identifies a version of a notice's description. SAM.gov keeps the
+// same noticedesc link when a notice is amended, so the posted date, which
+// an amendment changes, is part of the key.
+func (df *DescriptionFetcher) cacheKey(opp Opportunity) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(opp.Description+"\n"+opp.PostedDate)))
+}
+
+func (df *DescriptionFetcher) cached(opp Opportunity) (string, bool) {
+	key := df.cacheKey(opp)
+
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	if text, ok := df.memory[key]; ok {
+		return text, true
+	}
+	if df.cacheDir == "" {
+		return "", false
+	}
+
+	data, err := os.ReadFile(filepath.Join(df.cacheDir, key+".txt"))
+	if err != nil {
+		return "", false
+	}
+	df.memory[key] = string(data)
+	return string(data), true
+}
+
+func (df *DescriptionFetcher) store(opp Opportunity, text string) {
+	key := df.cacheKey(opp)
+
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	df.memory[key] = text
+	if df.cacheDir == "" {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(df.cacheDir, key+".txt"), []byte(text), 0644); err != nil && df.verbose {
+		log.Printf("Warning: could not cache description: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package samgov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// MaxPageSize is the largest page the SAM.gov search API will return
+const MaxPageSize = 1000
+
+// ErrBudgetExhausted is returned when the daily request budget does not allow another request
+var ErrBudgetExhausted = errors.New("daily request budget exhausted")
+
+// Searcher is implemented by anything that can execute a single search request
+type Searcher interface {
+	Search(ctx context.Context, params map[string]string) (*SearchResponse, error)
+}
+
+// PagedResponse is the combined result of walking every page of a search
+type PagedResponse struct {
+	*SearchResponse
+	Pages           int    `json:"pages"`
+	Truncated       bool   `json:"truncated"`
+	TruncatedReason string `json:"truncated_reason,omitempty"`
+}
+
+// Paginate walks offsets until TotalRecords is reached. The limit parameter is
+// used as the page size. If a later page is refused because the daily budget
+// ran out or the API rate limited us, the pages collected so far are returned
+// with Truncated set. Any other error fails the whole search, since a partial
+// result would look like notices had disappeared.
+func Paginate(ctx context.Context, searcher Searcher, params map[string]string) (*PagedResponse, error) {
+	pageSize := MaxPageSize
+	if limit, err := strconv.Atoi(params["limit"]); err == nil && limit > 0 {
+		pageSize = limit
+	}
+
+	offset := 0
+	if start, err := strconv.Atoi(params["offset"]); err == nil && start > 0 {
+		offset = start
+	}
+
+	combined := &PagedResponse{
+		SearchResponse: &SearchResponse{
+			Limit:             pageSize,
+			Offset:            offset,
+			OpportunitiesData: make([]Opportunity, 0),
+		},
+	}
+
+	for {
+		pageParams := make(map[string]string, len(params))
+		for key, value := range params {
+			pageParams[key] = value
+		}
+		pageParams["limit"] = strconv.Itoa(pageSize)
+		pageParams["offset"] = strconv.Itoa(offset)
+
+		page, err := searcher.Search(ctx, pageParams)
+		if err != nil {
+			if combined.Pages == 0 || !truncatesSearch(err) {
+				return nil, err
+			}
+			combined.Truncated = true
+			combined.TruncatedReason = fmt.Sprintf("page %d (offset %d): %v", combined.Pages+1, offset, err)
+			return combined, nil
+		}
+
+		combined.Pages++
+		combined.TotalRecords = page.TotalRecords
+		combined.OpportunitiesData = append(combined.OpportunitiesData, page.OpportunitiesData...)
+		offset += len(page.OpportunitiesData)
+
+		// Stop on the last page or when the API returns nothing more
+		if len(page.OpportunitiesData) == 0 || offset >= page.TotalRecords {
+			return combined, nil
+		}
+	}
+}
+
+// truncatesSearch reports whether a failed page should end the search with the
+// pages collected so far: the quota ran out, not the search itself failing
+func truncatesSearch(err error) bool {
+	if errors.Is(err, ErrBudgetExhausted) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
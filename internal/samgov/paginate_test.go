@@ -0,0 +1,169 @@
+package samgov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// fakeSearcher serves total notices in pages of the requested limit and
+// fails the page whose index is in failures
+type fakeSearcher struct {
+	total    int
+	failures map[int]error
+	offsets  []string
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, params map[string]string) (*SearchResponse, error) {
+	page := len(f.offsets)
+	f.offsets = append(f.offsets, params["offset"])
+	if err := f.failures[page]; err != nil {
+		return nil, err
+	}
+
+	limit, _ := strconv.Atoi(params["limit"])
+	offset, _ := strconv.Atoi(params["offset"])
+	resp := &SearchResponse{TotalRecords: f.total, Limit: limit, Offset: offset}
+	for i := offset; i < f.total && i < offset+limit; i++ {
+		resp.OpportunitiesData = append(resp.OpportunitiesData, Opportunity{NoticeID: fmt.Sprintf("N-%d", i)})
+	}
+	return resp, nil
+}
+
+func TestPaginate(t *testing.T) {
+	rateLimited := &APIError{StatusCode: 429, Message: "API returned status 429"}
+	serverError := &APIError{StatusCode: 500, Message: "API returned status 500"}
+
+	tests := []struct {
+		name        string
+		total       int
+		params      map[string]string
+		failures    map[int]error
+		wantOffsets []string
+		wantNotices int
+		wantPages   int
+		wantTrunc   bool
+		wantErr     error
+	}{
+		{
+			name:        "single page",
+			total:       3,
+			params:      map[string]string{"limit": "10"},
+			wantOffsets: []string{"0"},
+			wantNotices: 3,
+			wantPages:   1,
+		},
+		{
+			name:        "stops at total records",
+			total:       25,
+			params:      map[string]string{"limit": "10"},
+			wantOffsets: []string{"0", "10", "20"},
+			wantNotices: 25,
+			wantPages:   3,
+		},
+		{
+			name:        "exact multiple needs no empty page",
+			total:       20,
+			params:      map[string]string{"limit": "10"},
+			wantOffsets: []string{"0", "10"},
+			wantNotices: 20,
+			wantPages:   2,
+		},
+		{
+			name:        "starts at the given offset",
+			total:       25,
+			params:      map[string]string{"limit": "10", "offset": "15"},
+			wantOffsets: []string{"15"},
+			wantNotices: 10,
+			wantPages:   1,
+		},
+		{
+			name:        "no results",
+			total:       0,
+			params:      map[string]string{"limit": "10"},
+			wantOffsets: []string{"0"},
+			wantPages:   1,
+		},
+		{
+			name:        "missing limit uses the largest page",
+			total:       1500,
+			params:      map[string]string{},
+			wantOffsets: []string{"0", "1000"},
+			wantNotices: 1500,
+			wantPages:   2,
+		},
+		{
+			name:        "budget runs out after the first page",
+			total:       30,
+			params:      map[string]string{"limit": "10"},
+			failures:    map[int]error{1: fmt.Errorf("page refused: %w", ErrBudgetExhausted)},
+			wantOffsets: []string{"0", "10"},
+			wantNotices: 10,
+			wantPages:   1,
+			wantTrunc:   true,
+		},
+		{
+			name:        "rate limited on the third page",
+			total:       30,
+			params:      map[string]string{"limit": "10"},
+			failures:    map[int]error{2: rateLimited},
+			wantOffsets: []string{"0", "10", "20"},
+			wantNotices: 20,
+			wantPages:   2,
+			wantTrunc:   true,
+		},
+		{
+			name:        "budget exhausted on the first page fails",
+			total:       30,
+			params:      map[string]string{"limit": "10"},
+			failures:    map[int]error{0: ErrBudgetExhausted},
+			wantOffsets: []string{"0"},
+			wantErr:     ErrBudgetExhausted,
+		},
+		{
+			name:        "other errors after the first page fail",
+			total:       30,
+			params:      map[string]string{"limit": "10"},
+			failures:    map[int]error{1: serverError},
+			wantOffsets: []string{"0", "10"},
+			wantErr:     serverError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &fakeSearcher{total: tt.total, failures: tt.failures}
+			got, err := Paginate(context.Background(), searcher, tt.params)
+
+			if fmt.Sprint(searcher.offsets) != fmt.Sprint(tt.wantOffsets) {
+				t.Errorf("requested offsets %v, want %v", searcher.offsets, tt.wantOffsets)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+			if len(got.OpportunitiesData) != tt.wantNotices {
+				t.Errorf("notices = %d, want %d", len(got.OpportunitiesData), tt.wantNotices)
+			}
+			if got.Pages != tt.wantPages {
+				t.Errorf("pages = %d, want %d", got.Pages, tt.wantPages)
+			}
+			if got.Truncated != tt.wantTrunc {
+				t.Errorf("truncated = %v, want %v", got.Truncated, tt.wantTrunc)
+			}
+			if tt.wantTrunc && got.TruncatedReason == "" {
+				t.Errorf("truncated without a reason")
+			}
+			if got.TotalRecords != tt.total {
+				t.Errorf("total records = %d, want %d", got.TotalRecords, tt.total)
+			}
+		})
+	}
+}
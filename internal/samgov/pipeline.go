@@ -0,0 +1,88 @@
+package samgov
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stage is a tracked opportunity's place in the capture pipeline
+type Stage string
+
+// Pipeline stages, in the order an opportunity usually moves through them
+const (
+	StageNew       Stage = "new"
+	StageReviewing Stage = "reviewing"
+	StagePursuing  Stage = "pursuing"
+	StageSubmitted Stage = "submitted"
+	StageWon       Stage = "won"
+	StageLost      Stage = "lost"
+	StageNoBid     Stage = "no-bid"
+)
+
+// Stages lists every pipeline stage in order
+var Stages = []Stage{StageNew, StageReviewing, StagePursuing, StageSubmitted, StageWon, StageLost, StageNoBid}
+
+// stageTransitions lists where each stage can move. A no-bid decision can be
+// revisited; won and lost are final.
+var stageTransitions = map[Stage][]Stage{
+	StageNew:       {StageReviewing, StagePursuing, StageNoBid},
+	StageReviewing: {StageNew, StagePursuing, StageNoBid},
+	StagePursuing:  {StageReviewing, StageSubmitted, StageNoBid},
+	StageSubmitted: {StageWon, StageLost},
+	StageNoBid:     {StageReviewing},
+}
+
+// ParseStage reads a stage name, accepting "nobid" and "no_bid" for no-bid
+func ParseStage(name string) (Stage, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "nobid" || normalized == "no_bid" {
+		normalized = string(StageNoBid)
+	}
+	for _, stage := range Stages {
+		if string(stage) == normalized {
+			return stage, nil
+		}
+	}
+	return "", fmt.Errorf("unknown stage %q (valid stages: %s)", name, stageNames(Stages))
+}
+
+// CanMoveTo reports whether the pipeline allows moving from s to next.
+// Staying in the same stage is always allowed.
+func (s Stage) CanMoveTo(next Stage) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range stageTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStages lists the stages s can move to
+func (s Stage) NextStages() []Stage {
+	return stageTransitions[s]
+}
+
+// Closed reports whether the capture decision is final, so the notice no
+// longer needs amendment alerts or deadline reminders
+func (s Stage) Closed() bool {
+	return s == StageWon || s == StageLost || s == StageNoBid
+}
+
+// PipelineStage returns the notice's stage, StageNew when none was set
+func (s OpportunityState) PipelineStage() Stage {
+	if s.Stage == "" {
+		return StageNew
+	}
+	return s.Stage
+}
+
+func stageNames(stages []Stage) string {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = string(stage)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,292 @@
+package samgov
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRequestsPerMinute spaces requests out enough to avoid 429s
+	DefaultRequestsPerMinute = 6
+	// NonFederalDailyLimit is the SAM.gov quota for non-federal API keys
+	NonFederalDailyLimit = 10
+	// FederalDailyLimit is the SAM.gov quota for federal API keys
+	FederalDailyLimit = 1000
+)
+
+// RateLimitStore persists the daily request count and any server-imposed
+// back-off so limits survive process restarts
+type RateLimitStore interface {
+	// TryIncrementDailyRequests charges one request for the current UTC day
+	// unless limit has been reached
+	TryIncrementDailyRequests(limit int) (int, bool)
+	// GetDailyRequestCount returns the count for the current UTC day
+	GetDailyRequestCount() (int, string)
+	// ExhaustDailyRequests marks the current UTC day as fully used
+	ExhaustDailyRequests(limit int)
+	GetRateLimitedUntil() time.Time
+	SetRateLimitedUntil(t time.Time)
+}
+
+// RateLimitConfig configures the per-minute and per-UTC-day budgets
+type RateLimitConfig struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	DailyLimit        int `json:"daily_limit"`
+}
+
+// RateLimitConfigFromEnv builds a config from SAM_ACCOUNT_TYPE,
+// SAM_REQUESTS_PER_MINUTE and SAM_DAILY_LIMIT
+func RateLimitConfigFromEnv() RateLimitConfig {
+	config := RateLimitConfig{
+		RequestsPerMinute: DefaultRequestsPerMinute,
+		DailyLimit:        NonFederalDailyLimit,
+	}
+
+	if os.Getenv("SAM_ACCOUNT_TYPE") == "federal" {
+		config.DailyLimit = FederalDailyLimit
+	}
+	if n, err := strconv.Atoi(os.Getenv("SAM_REQUESTS_PER_MINUTE")); err == nil && n > 0 {
+		config.RequestsPerMinute = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("SAM_DAILY_LIMIT")); err == nil && n > 0 {
+		config.DailyLimit = n
+	}
+
+	return config
+}
+
+// RateLimiter is a token bucket for the per-minute budget combined with a
+// persisted per-UTC-day counter. It also honours Retry-After and
+// X-RateLimit-Remaining headers returned by the API.
+type RateLimiter struct {
+	config  RateLimitConfig
+	store   RateLimitStore
+	verbose bool
+
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	reserved   int // Requests set aside for searches that have not started
+}
+
+// NewRateLimiter creates a rate limiter backed by store
+func NewRateLimiter(config RateLimitConfig, store RateLimitStore, verbose bool) *RateLimiter {
+	if config.RequestsPerMinute <= 0 {
+		config.RequestsPerMinute = DefaultRequestsPerMinute
+	}
+
+	return &RateLimiter{
+		config:     config,
+		store:      store,
+		verbose:    verbose,
+		tokens:     float64(config.RequestsPerMinute),
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a request is allowed and charges it against both budgets.
+// It returns ErrBudgetExhausted when the daily budget is used up, or the
+// context error if ctx is cancelled while waiting.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Server asked us to back off
+		if until := rl.store.GetRateLimitedUntil(); time.Now().Before(until) {
+			delay := time.Until(until)
+			if rl.verbose {
+				log.Printf("Rate limited by server, waiting %v", delay.Round(time.Second))
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		delay := rl.reserveToken()
+		if delay > 0 {
+			if rl.verbose {
+				log.Printf("Per-minute request budget used, waiting %v", delay.Round(time.Second))
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		count, ok := rl.store.TryIncrementDailyRequests(rl.config.DailyLimit)
+		if !ok {
+			rl.returnToken()
+			if rl.verbose {
+				log.Printf("Daily API budget exhausted (%d/%d)", count, rl.config.DailyLimit)
+			}
+			return ErrBudgetExhausted
+		}
+
+		if rl.verbose {
+			log.Printf("Making API request %d/%d for today (UTC)", count, rl.config.DailyLimit)
+		}
+		return nil
+	}
+}
+
+// reserveToken takes a token from the bucket, or reports how long until one is available
+func (rl *RateLimiter) reserveToken() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	perSecond := float64(rl.config.RequestsPerMinute) / 60
+	rl.tokens += now.Sub(rl.lastRefill).Seconds() * perSecond
+	if max := float64(rl.config.RequestsPerMinute); rl.tokens > max {
+		rl.tokens = max
+	}
+	rl.lastRefill = now
+
+	if rl.tokens >= 1 {
+		rl.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - rl.tokens) / perSecond * float64(time.Second))
+}
+
+// returnToken gives back a token that was reserved but not used
+func (rl *RateLimiter) returnToken() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.tokens++
+}
+
+// Observe updates the limiter from API response headers
+func (rl *RateLimiter) Observe(header http.Header) {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if delay, ok := parseRetryAfter(retryAfter); ok {
+			until := time.Now().Add(delay)
+			if until.After(rl.store.GetRateLimitedUntil()) {
+				rl.store.SetRateLimitedUntil(until)
+			}
+			log.Printf("Server requested Retry-After %v", delay.Round(time.Second))
+		}
+	}
+
+	if remaining := header.Get("X-RateLimit-Remaining"); remaining != "" {
+		n, err := strconv.Atoi(remaining)
+		if err != nil {
+			return
+		}
+		if rl.verbose {
+			log.Printf("Rate limit remaining: %d", n)
+		}
+		// The server knows better than our local counter
+		if n <= 0 {
+			rl.store.ExhaustDailyRequests(rl.config.DailyLimit)
+		}
+	}
+}
+
+// DailyLimit returns the configured per-UTC-day limit
+func (rl *RateLimiter) DailyLimit() int {
+	return rl.config.DailyLimit
+}
+
+// DailyRemaining returns how many requests are left for the current UTC day
+func (rl *RateLimiter) DailyRemaining() int {
+	count, _ := rl.store.GetDailyRequestCount()
+	if remaining := rl.config.DailyLimit - count; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// ReserveForRun sets aside n requests of today's budget for the first request
+// of each search in a run. Retries may not spend them, so one failing query
+// cannot use up the quota the other queries need.
+func (rl *RateLimiter) ReserveForRun(n int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.reserved = n
+}
+
+// reservationKey is the context key of a search's reservation
+type reservationKey struct{}
+
+// reservation is one of the requests set aside by ReserveForRun, held by a
+// single search however many pages and retries it makes
+type reservation struct {
+	limiter *RateLimiter
+	once    sync.Once
+}
+
+// WithReservation returns a context for one search, holding one of the
+// requests set aside by ReserveForRun. The first request made under it
+// claims the reservation; later pages and retries do not. Call release when
+// the search ends, to give back a reservation no request claimed.
+func (rl *RateLimiter) WithReservation(ctx context.Context) (searchCtx context.Context, release func()) {
+	held := &reservation{limiter: rl}
+	return context.WithValue(ctx, reservationKey{}, held), held.claim
+}
+
+// claim releases the reservation the first time it is called
+func (r *reservation) claim() {
+	r.once.Do(r.limiter.claimReserved)
+}
+
+// claimReservation claims the reservation of the search ctx belongs to, if any
+func claimReservation(ctx context.Context) {
+	if held, ok := ctx.Value(reservationKey{}).(*reservation); ok {
+		held.claim()
+	}
+}
+
+// claimReserved releases one reservation
+func (rl *RateLimiter) claimReserved() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.reserved > 0 {
+		rl.reserved--
+	}
+}
+
+// RetryAllowed reports whether today's budget has room for a retry once the
+// requests reserved for the rest of the run are set aside
+func (rl *RateLimiter) RetryAllowed() bool {
+	rl.mu.Lock()
+	reserved := rl.reserved
+	rl.mu.Unlock()
+	return rl.DailyRemaining() > reserved
+}
+
+// parseRetryAfter accepts either delay-seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(t); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext sleeps for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,275 @@
+package samgov
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryRateLimitStore keeps the daily count and back-off in memory
+type memoryRateLimitStore struct {
+	mu          sync.Mutex
+	count       int
+	limitedTill time.Time
+}
+
+func (s *memoryRateLimitStore) TryIncrementDailyRequests(limit int) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count >= limit {
+		return s.count, false
+	}
+	s.count++
+	return s.count, true
+}
+
+func (s *memoryRateLimitStore) GetDailyRequestCount() (int, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count, time.Now().UTC().Format("2006-01-02")
+}
+
+func (s *memoryRateLimitStore) ExhaustDailyRequests(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count < limit {
+		s.count = limit
+	}
+}
+
+func (s *memoryRateLimitStore) GetRateLimitedUntil() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limitedTill
+}
+
+func (s *memoryRateLimitStore) SetRateLimitedUntil(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limitedTill = t
+}
+
+// newTestLimiter returns a limiter with a daily limit and no per-minute wait
+func newTestLimiter(dailyLimit int) (*RateLimiter, *memoryRateLimitStore) {
+	store := &memoryRateLimitStore{}
+	config := RateLimitConfig{RequestsPerMinute: 6000, DailyLimit: dailyLimit}
+	return NewRateLimiter(config, store, false), store
+}
+
+// reservedRequests returns the requests still set aside for the run
+func reservedRequests(rl *RateLimiter) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.reserved
+}
+
+func TestReservationHeldPerQuery(t *testing.T) {
+	// "three-pages" has one notice per page, and its last page fails once
+	// with a server error; "one-page" has a single notice
+	var mu sync.Mutex
+	lastPageFailed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		total := 1
+		if query.Get("title") == "three-pages" {
+			total = 3
+			mu.Lock()
+			failNow := offset == 2 && !lastPageFailed
+			lastPageFailed = lastPageFailed || failNow
+			mu.Unlock()
+			if failNow {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(SearchResponse{
+			TotalRecords:      total,
+			OpportunitiesData: []Opportunity{{NoticeID: query.Get("title") + "-" + query.Get("offset")}},
+		})
+	}))
+	defer server.Close()
+
+	// Four requests: three pages for the first query, one for the second
+	limiter, store := newTestLimiter(4)
+	client := NewClientWithOptions("test-key", server.URL, 0)
+	client.SetRateLimiter(limiter)
+	config := DefaultRetryConfig()
+	config.MaxRetries = 1
+	config.InitialDelay = time.Millisecond
+	retrier := NewStatsTrackingRetryClientFromClient(client, config, false)
+
+	limiter.ReserveForRun(2)
+	defer limiter.ReserveForRun(0)
+
+	search := func(title string) (*PagedResponse, error) {
+		ctx, release := limiter.WithReservation(context.Background())
+		defer release()
+		return Paginate(ctx, retrier, map[string]string{"title": title, "limit": "1"})
+	}
+
+	// The failed last page may not be retried with the second query's request
+	if _, err := search("three-pages"); err == nil {
+		t.Errorf("three-page search succeeded, want the last page's error")
+	}
+	if got := reservedRequests(limiter); got != 1 {
+		t.Errorf("reserved after the three-page query = %d, want 1", got)
+	}
+
+	resp, err := search("one-page")
+	if err != nil {
+		t.Fatalf("second query: %v", err)
+	}
+	if len(resp.OpportunitiesData) != 1 {
+		t.Errorf("second query returned %d notices, want 1", len(resp.OpportunitiesData))
+	}
+	if count, _ := store.GetDailyRequestCount(); count != 4 {
+		t.Errorf("requests made = %d, want 4", count)
+	}
+	if got := reservedRequests(limiter); got != 0 {
+		t.Errorf("reserved after both queries = %d, want 0", got)
+	}
+}
+
+func TestReservationReleasedWithoutRequests(t *testing.T) {
+	limiter, _ := newTestLimiter(10)
+	limiter.ReserveForRun(2)
+
+	// A search answered from the cache never claims its reservation
+	_, release := limiter.WithReservation(context.Background())
+	release()
+	release()
+	if got := reservedRequests(limiter); got != 1 {
+		t.Errorf("reserved = %d, want 1", got)
+	}
+
+	// Claims outside a reserved search leave the reservations alone
+	claimReservation(context.Background())
+	if got := reservedRequests(limiter); got != 1 {
+		t.Errorf("reserved = %d, want 1", got)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		previousUntil time.Duration // Existing back-off, from now
+		wantBackoff   time.Duration // Zero means no back-off
+		wantCount     int
+	}{
+		{name: "no headers", headers: map[string]string{}},
+		{name: "Retry-After seconds", headers: map[string]string{"Retry-After": "120"}, wantBackoff: 2 * time.Minute},
+		{name: "Retry-After HTTP date", headers: map[string]string{"Retry-After": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}, wantBackoff: time.Hour},
+		{name: "invalid Retry-After is ignored", headers: map[string]string{"Retry-After": "soon"}},
+		{name: "shorter Retry-After keeps the longer back-off", headers: map[string]string{"Retry-After": "60"}, previousUntil: time.Hour, wantBackoff: time.Hour},
+		{name: "requests remaining", headers: map[string]string{"X-RateLimit-Remaining": "5"}, wantCount: 1},
+		{name: "nothing remaining ends the day", headers: map[string]string{"X-RateLimit-Remaining": "0"}, wantCount: 10},
+		{name: "invalid remaining is ignored", headers: map[string]string{"X-RateLimit-Remaining": "none"}, wantCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, store := newTestLimiter(10)
+			store.count = 1
+			if tt.previousUntil > 0 {
+				store.limitedTill = time.Now().Add(tt.previousUntil)
+			}
+			header := http.Header{}
+			for key, value := range tt.headers {
+				header.Set(key, value)
+			}
+
+			limiter.Observe(header)
+
+			until := store.GetRateLimitedUntil()
+			if tt.wantBackoff == 0 {
+				if !until.IsZero() {
+					t.Errorf("backing off until %v, want no back-off", until)
+				}
+			} else if delay := time.Until(until); delay < tt.wantBackoff-5*time.Second || delay > tt.wantBackoff {
+				t.Errorf("backing off for %v, want %v", delay, tt.wantBackoff)
+			}
+			wantCount := tt.wantCount
+			if wantCount == 0 {
+				wantCount = 1
+			}
+			if count, _ := store.GetDailyRequestCount(); count != wantCount {
+				t.Errorf("daily count = %d, want %d", count, wantCount)
+			}
+		})
+	}
+}
+
+func TestRetryAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     int
+		reserved int
+		claimed  int // Searches that have made their first request
+		want     bool
+	}{
+		{name: "nothing reserved", used: 9, want: true},
+		{name: "budget used up", used: 10, want: false},
+		{name: "room beyond the reservations", used: 5, reserved: 4, want: true},
+		{name: "remaining budget is all reserved", used: 6, reserved: 4, want: false},
+		{name: "claimed reservations free the budget", used: 6, reserved: 4, claimed: 1, want: true},
+		{name: "more claims than reservations", used: 9, reserved: 1, claimed: 3, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, store := newTestLimiter(10)
+			store.count = tt.used
+			limiter.ReserveForRun(tt.reserved)
+			for i := 0; i < tt.claimed; i++ {
+				ctx, _ := limiter.WithReservation(context.Background())
+				claimReservation(ctx)
+				claimReservation(ctx) // A second request of the same search
+			}
+
+			if got := limiter.RetryAllowed(); got != tt.want {
+				t.Errorf("RetryAllowed() = %v, want %v (remaining %d, reserved %d)",
+					got, tt.want, limiter.DailyRemaining(), reservedRequests(limiter))
+			}
+		})
+	}
+}
+
+func TestWaitDailyBudget(t *testing.T) {
+	limiter, store := newTestLimiter(2)
+	for i := 1; i <= 2; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if err := limiter.Wait(context.Background()); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("third request error = %v, want ErrBudgetExhausted", err)
+	}
+	if count, _ := store.GetDailyRequestCount(); count != 2 {
+		t.Errorf("daily count = %d, want 2", count)
+	}
+	if remaining := limiter.DailyRemaining(); remaining != 0 {
+		t.Errorf("DailyRemaining() = %d, want 0", remaining)
+	}
+}
+
+func TestWaitHonoursBackoff(t *testing.T) {
+	limiter, store := newTestLimiter(10)
+	store.limitedTill = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want the context deadline", err)
+	}
+	if count, _ := store.GetDailyRequestCount(); count != 0 {
+		t.Errorf("daily count = %d, want 0 while backing off", count)
+	}
+}
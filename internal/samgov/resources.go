@@ -0,0 +1,157 @@
+package samgov
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// MaxResourceBytes is the largest resource file that will be downloaded
+const MaxResourceBytes = 50 << 20
+
+// Resource is a downloaded resource file held in memory
+type Resource struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// DownloadResource fetches a file from an opportunity's resourceLinks. The
+// request is charged against the rate limiter like any other API call.
+func (c *Client) DownloadResource(ctx context.Context, link string) (*Resource, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resource link: %w", err)
+	}
+
+	// Links come back with a placeholder key that must be replaced
+	c.setAPIKey(u)
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	userAgent := DefaultUserAgent
+	if envUA := os.Getenv("SAM_USER_AGENT"); envUA != "" {
+		userAgent = envUA
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	// Resource downloads can be much larger than search responses
+	httpClient := c.keyGuardedHTTPClient()
+	httpClient.Timeout = 0
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if c.limiter != nil {
+		c.limiter.Observe(resp.Header)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("API returned status %d", resp.StatusCode),
+			Details:    resp.Status,
+		}
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, MaxResourceBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading resource: %w", err)
+	}
+	if len(content) > MaxResourceBytes {
+		return nil, fmt.Errorf("resource exceeds %d bytes", MaxResourceBytes)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	return &Resource{
+		Name:        resourceName(resp, u),
+		ContentType: contentType,
+		Content:     content,
+	}, nil
+}
+
+// apiKeyHosts are the hosts the API key may be sent to, besides the host of
+// the client's base URL
+var apiKeyHosts = map[string]bool{
+	"sam.gov":     true,
+	"api.sam.gov": true,
+}
+
+// sendsAPIKey reports whether the API key may be sent to the link's host
+func (c *Client) sendsAPIKey(u *url.URL) bool {
+	if apiKeyHosts[strings.ToLower(u.Hostname())] {
+		return true
+	}
+	base, err := url.Parse(c.baseURL)
+	return err == nil && strings.EqualFold(base.Host, u.Host)
+}
+
+// setAPIKey puts the API key in the link's query when the link points at
+// SAM.gov, and removes any key from links to other hosts
+func (c *Client) setAPIKey(u *url.URL) {
+	q := u.Query()
+	if c.sendsAPIKey(u) {
+		q.Set("api_key", c.apiKey)
+	} else {
+		q.Del("api_key")
+	}
+	u.RawQuery = q.Encode()
+}
+
+// keyGuardedHTTPClient returns a copy of the HTTP client for links that carry
+// the API key. SAM.gov redirects downloads to other hosts, such as presigned
+// S3 URLs, so redirects there lose the key and the Referer that repeats it.
+func (c *Client) keyGuardedHTTPClient() http.Client {
+	httpClient := *c.httpClient
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if !c.sendsAPIKey(req.URL) {
+			q := req.URL.Query()
+			if q.Has("api_key") {
+				q.Del("api_key")
+				req.URL.RawQuery = q.Encode()
+			}
+			req.Header.Del("Referer")
+		}
+		return nil
+	}
+	return httpClient
+}
+
+// resourceName prefers the server's Content-Disposition filename over the URL path
+func resourceName(resp *http.Response, u *url.URL) string {
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != "" {
+		if _, params, err := mime.ParseMediaType(disposition); err == nil && params["filename"] != "" {
+			return path.Base(strings.ReplaceAll(params["filename"], "\\", "/"))
+		}
+	}
+
+	name := path.Base(u.Path)
+	if name == "download" || name == "/" || name == "." {
+		name = path.Base(path.Dir(u.Path))
+	}
+	return name
+}
@@ -2,9 +2,13 @@ package samgov
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"os"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -30,6 +34,21 @@ func DefaultRetryConfig() RetryConfig {
 	}
 }
 
+// RetryConfigFromEnv returns the default retry config with SAM_MAX_RETRIES
+// and SAM_RATE_LIMIT_DELAY applied. Retries are off unless SAM_MAX_RETRIES
+// is set, since each one spends a request of the daily quota.
+func RetryConfigFromEnv() RetryConfig {
+	config := DefaultRetryConfig()
+	config.MaxRetries = 0
+	if n, err := strconv.Atoi(os.Getenv("SAM_MAX_RETRIES")); err == nil && n >= 0 {
+		config.MaxRetries = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("SAM_RATE_LIMIT_DELAY")); err == nil && d > 0 {
+		config.InitialDelay = d
+	}
+	return config
+}
+
 // RetryClient wraps the SAM.gov client with retry logic
 type RetryClient struct {
 	*Client
@@ -39,8 +58,16 @@ type RetryClient struct {
 
 // NewRetryClient creates a client with retry capabilities
 func NewRetryClient(apiKey string, config RetryConfig, verbose bool) *RetryClient {
+	return NewRetryClientFromClient(NewClient(apiKey), config, verbose)
+}
+
+// NewRetryClientFromClient adds retry logic to an existing client, keeping
+// its rate limiter and HTTP settings. The client's own retries are turned
+// off so each failure is retried by one layer only.
+func NewRetryClientFromClient(client *Client, config RetryConfig, verbose bool) *RetryClient {
+	client.noRetry = true
 	return &RetryClient{
-		Client:  NewClient(apiKey),
+		Client:  client,
 		config:  config,
 		verbose: verbose,
 	}
@@ -54,8 +81,11 @@ func NewRetryClientWithDefaults(apiKey string, verbose bool) *RetryClient {
 // SearchWithRetry executes a search with automatic retry on failure
 func (rc *RetryClient) SearchWithRetry(ctx context.Context, params map[string]string) (*SearchResponse, error) {
 	var lastErr error
-	
+	attempts := 0
+	rc.firstAttempt(ctx)
+
 	for attempt := 0; attempt <= rc.config.MaxRetries; attempt++ {
+		attempts++
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
@@ -87,7 +117,7 @@ func (rc *RetryClient) SearchWithRetry(ctx context.Context, params map[string]st
 		}
 
 		// Don't wait after the last attempt
-		if attempt == rc.config.MaxRetries {
+		if attempt == rc.config.MaxRetries || !rc.BudgetAllowsRetry() {
 			break
 		}
 
@@ -115,7 +145,12 @@ func (rc *RetryClient) SearchWithRetry(ctx context.Context, params map[string]st
 		}
 	}
 
-	return nil, fmt.Errorf("search failed after %d attempts: %w", rc.config.MaxRetries+1, lastErr)
+	return nil, fmt.Errorf("search failed after %d attempts: %w", attempts, lastErr)
+}
+
+// Search implements Searcher with retry logic
+func (rc *RetryClient) Search(ctx context.Context, params map[string]string) (*SearchResponse, error) {
+	return rc.SearchWithRetry(ctx, params)
 }
 
 // SearchWithDefaultsAndRetry combines default parameters with retry logic
@@ -142,6 +177,11 @@ func (rc *RetryClient) SearchWithDefaultsAndRetry(ctx context.Context, customPar
 
 // isRetryableError determines if an error should trigger a retry
 func (rc *RetryClient) isRetryableError(err error) bool {
+	// Exhausted budgets and open circuits will not recover within a retry delay
+	if errors.Is(err, ErrBudgetExhausted) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
 	// Check if it's an API error with retryable status code
 	if apiErr, ok := err.(*APIError); ok {
 		for _, code := range rc.config.RetryableErrors {
@@ -173,6 +213,24 @@ func (rc *RetryClient) isRetryableError(err error) bool {
 	return false
 }
 
+// firstAttempt releases the request reserved for the search ctx belongs to.
+// Only the first page of a paginated search claims it.
+func (rc *RetryClient) firstAttempt(ctx context.Context) {
+	claimReservation(ctx)
+}
+
+// BudgetAllowsRetry reports whether the daily budget has room for a retry
+// that does not eat into the requests reserved for other searches
+func (rc *RetryClient) BudgetAllowsRetry() bool {
+	if rc.limiter == nil || rc.limiter.RetryAllowed() {
+		return true
+	}
+	if rc.verbose {
+		log.Printf("Not retrying: the rest of today's API budget is reserved for other searches")
+	}
+	return false
+}
+
 // calculateDelay computes the delay before the next retry attempt
 func (rc *RetryClient) calculateDelay(attempt int) time.Duration {
 	// Exponential backoff: delay = initial_delay * (backoff_factor ^ attempt)
@@ -224,6 +282,7 @@ type RetryStats struct {
 // StatsTrackingRetryClient wraps RetryClient with statistics tracking
 type StatsTrackingRetryClient struct {
 	*RetryClient
+	mu    sync.Mutex
 	stats RetryStats
 }
 
@@ -237,63 +296,100 @@ func NewStatsTrackingRetryClient(apiKey string, config RetryConfig, verbose bool
 	}
 }
 
+// NewStatsTrackingRetryClientFromClient adds retry logic and statistics to an existing client
+func NewStatsTrackingRetryClientFromClient(client *Client, config RetryConfig, verbose bool) *StatsTrackingRetryClient {
+	return &StatsTrackingRetryClient{
+		RetryClient: NewRetryClientFromClient(client, config, verbose),
+		stats: RetryStats{
+			RetryReasons: make(map[string]int),
+		},
+	}
+}
+
+// Search implements Searcher with retry logic and statistics
+func (src *StatsTrackingRetryClient) Search(ctx context.Context, params map[string]string) (*SearchResponse, error) {
+	return src.SearchWithRetryAndStats(ctx, params)
+}
+
 // SearchWithRetryAndStats executes search with retry and tracks statistics
 func (src *StatsTrackingRetryClient) SearchWithRetryAndStats(ctx context.Context, params map[string]string) (*SearchResponse, error) {
-	src.stats.TotalRequests++
+	src.record(func(stats *RetryStats) { stats.TotalRequests++ })
 	attemptCount := 0
-	
+	src.firstAttempt(ctx)
+
 	var lastErr error
 	
 	for attempt := 0; attempt <= src.config.MaxRetries; attempt++ {
 		attemptCount++
-		
+
 		select {
 		case <-ctx.Done():
-			src.stats.FailedRequests++
+			src.record(func(stats *RetryStats) { stats.FailedRequests++ })
 			return nil, ctx.Err()
 		default:
 		}
 
 		result, err := src.Client.Search(ctx, params)
 		if err == nil {
-			src.stats.SuccessfulRequests++
-			if attempt > 0 {
-				src.stats.TotalRetries += attempt
-			}
+			src.record(func(stats *RetryStats) {
+				stats.SuccessfulRequests++
+				stats.TotalRetries += attempt
+			})
 			return result, nil
 		}
 
 		lastErr = err
 
 		// Track retry reason
+		reason := "network_error"
 		if apiErr, ok := err.(*APIError); ok {
-			reason := fmt.Sprintf("HTTP_%d", apiErr.StatusCode)
-			src.stats.RetryReasons[reason]++
-		} else {
-			src.stats.RetryReasons["network_error"]++
+			reason = fmt.Sprintf("HTTP_%d", apiErr.StatusCode)
+		} else if errors.Is(err, ErrBudgetExhausted) {
+			reason = "budget_exhausted"
 		}
+		src.record(func(stats *RetryStats) { stats.RetryReasons[reason]++ })
 
-		if !src.isRetryableError(err) || attempt == src.config.MaxRetries {
+		if !src.isRetryableError(err) || attempt == src.config.MaxRetries || !src.BudgetAllowsRetry() {
 			break
 		}
 
 		delay := src.calculateDelay(attempt)
+		if src.verbose {
+			log.Printf("Search failed (attempt %d/%d), retrying in %v: %v",
+				attempt+1, src.config.MaxRetries+1, delay, err)
+		}
 		select {
 		case <-ctx.Done():
-			src.stats.FailedRequests++
+			src.record(func(stats *RetryStats) { stats.FailedRequests++ })
 			return nil, ctx.Err()
 		case <-time.After(delay):
 		}
 	}
 
-	src.stats.FailedRequests++
-	src.stats.TotalRetries += attemptCount - 1
+	src.record(func(stats *RetryStats) {
+		stats.FailedRequests++
+		stats.TotalRetries += attemptCount - 1
+	})
 	return nil, fmt.Errorf("search failed after %d attempts: %w", attemptCount, lastErr)
 }
 
+// record applies an update to the statistics under the lock
+func (src *StatsTrackingRetryClient) record(update func(stats *RetryStats)) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	update(&src.stats)
+}
+
 // GetStats returns current retry statistics
 func (src *StatsTrackingRetryClient) GetStats() RetryStats {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
 	stats := src.stats
+	stats.RetryReasons = make(map[string]int, len(src.stats.RetryReasons))
+	for reason, count := range src.stats.RetryReasons {
+		stats.RetryReasons[reason] = count
+	}
 	if stats.TotalRequests > 0 {
 		stats.AverageRetries = float64(stats.TotalRetries) / float64(stats.TotalRequests)
 	}
@@ -302,6 +398,8 @@ func (src *StatsTrackingRetryClient) GetStats() RetryStats {
 
 // ResetStats clears retry statistics
 func (src *StatsTrackingRetryClient) ResetStats() {
+	src.mu.Lock()
+	defer src.mu.Unlock()
 	src.stats = RetryStats{
 		RetryReasons: make(map[string]int),
 	}
@@ -356,13 +454,17 @@ func containsIgnoreCase(text, substr string) bool {
 	return false
 }
 
+// ErrCircuitOpen is returned while the circuit breaker is rejecting calls
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
 // CircuitBreaker implements a simple circuit breaker pattern
 type CircuitBreaker struct {
-	maxFailures   int
-	resetTimeout  time.Duration
-	failures      int
-	lastFailTime  time.Time
-	state         string // "closed", "open", "half-open"
+	mu           sync.Mutex
+	maxFailures  int
+	resetTimeout time.Duration
+	failures     int
+	lastFailTime time.Time
+	state        string // "closed", "open", "half-open"
 }
 
 // NewCircuitBreaker creates a new circuit breaker
@@ -376,28 +478,88 @@ func NewCircuitBreaker(maxFailures int, resetTimeout time.Duration) *CircuitBrea
 
 // Execute runs a function with circuit breaker protection
 func (cb *CircuitBreaker) Execute(fn func() error) error {
+	if err := cb.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	cb.recordResult(err)
+	return err
+}
+
+// State returns "closed", "open" or "half-open"
+func (cb *CircuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// allow reports whether a call may proceed, moving an expired open circuit to half-open
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
 	if cb.state == "open" {
 		if time.Since(cb.lastFailTime) > cb.resetTimeout {
 			cb.state = "half-open"
 		} else {
-			return fmt.Errorf("circuit breaker is open")
+			return ErrCircuitOpen
 		}
 	}
+	return nil
+}
+
+// recordResult counts a failure or resets the breaker on success
+func (cb *CircuitBreaker) recordResult(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
 
-	err := fn()
-	
 	if err != nil {
 		cb.failures++
 		cb.lastFailTime = time.Now()
-		
+
 		if cb.failures >= cb.maxFailures {
 			cb.state = "open"
 		}
-		return err
+		return
 	}
 
 	// Reset on success
 	cb.failures = 0
 	cb.state = "closed"
-	return nil
-}
\ No newline at end of file
+}
+
+// CircuitBreakerSearcher stops calling the API after repeated failures
+type CircuitBreakerSearcher struct {
+	searcher Searcher
+	breaker  *CircuitBreaker
+}
+
+// NewCircuitBreakerSearcher wraps a searcher with breaker
+func NewCircuitBreakerSearcher(searcher Searcher, breaker *CircuitBreaker) *CircuitBreakerSearcher {
+	return &CircuitBreakerSearcher{
+		searcher: searcher,
+		breaker:  breaker,
+	}
+}
+
+// Search executes the search unless the circuit is open. An exhausted budget
+// or a cancelled context says nothing about API health, so neither is counted.
+func (cbs *CircuitBreakerSearcher) Search(ctx context.Context, params map[string]string) (*SearchResponse, error) {
+	if err := cbs.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	response, err := cbs.searcher.Search(ctx, params)
+	if errors.Is(err, ErrBudgetExhausted) || ctx.Err() != nil {
+		return response, err
+	}
+
+	cbs.breaker.recordResult(err)
+	return response, err
+}
+
+// Breaker returns the underlying circuit breaker
+func (cbs *CircuitBreakerSearcher) Breaker() *CircuitBreaker {
+	return cbs.breaker
+}
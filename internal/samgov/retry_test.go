@@ -0,0 +1,37 @@
+package samgov
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxRetries  string
+		delay       string
+		wantRetries int
+		wantDelay   time.Duration
+	}{
+		{"retries are off by default", "", "", 0, 2 * time.Second},
+		{"SAM_MAX_RETRIES opts in", "3", "", 3, 2 * time.Second},
+		{"invalid values are ignored", "many", "soon", 0, 2 * time.Second},
+		{"negative retries are ignored", "-1", "", 0, 2 * time.Second},
+		{"SAM_RATE_LIMIT_DELAY sets the first delay", "1", "30s", 1, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SAM_MAX_RETRIES", tt.maxRetries)
+			t.Setenv("SAM_RATE_LIMIT_DELAY", tt.delay)
+
+			config := RetryConfigFromEnv()
+			if config.MaxRetries != tt.wantRetries {
+				t.Errorf("MaxRetries = %d, want %d", config.MaxRetries, tt.wantRetries)
+			}
+			if config.InitialDelay != tt.wantDelay {
+				t.Errorf("InitialDelay = %v, want %v", config.InitialDelay, tt.wantDelay)
+			}
+		})
+	}
+}
@@ -2,26 +2,38 @@ package samgov
 
 import (
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Opportunity represents a SAM.gov opportunity
 type Opportunity struct {
-	NoticeID         string     `json:"noticeId"`
-	Title            string     `json:"title"`
-	SolicitationNum  string     `json:"solicitationNumber"`
-	FullParentPath   string     `json:"fullParentPathName"`
-	PostedDate       string     `json:"postedDate"`
-	Type             string     `json:"type"`
-	ResponseDeadline *string    `json:"responseDeadLine"`
-	UILink           string     `json:"uiLink"`
-	Active           string     `json:"active"`
-	Description      string     `json:"description"`
-	PointOfContact   []Contact  `json:"pointOfContact"`
-	Award            *Award     `json:"award,omitempty"`
-	PlaceOfPerformance *Place   `json:"placeOfPerformance,omitempty"`
-	TypeOfSetAside   string     `json:"typeOfSetAside"`
-	NAICSCode        string     `json:"naicsCode"`
+	NoticeID           string        `json:"noticeId"`
+	Title              string        `json:"title"`
+	SolicitationNum    string        `json:"solicitationNumber"`
+	FullParentPath     string        `json:"fullParentPathName"`
+	PostedDate         string        `json:"postedDate"`
+	Type               string        `json:"type"`
+	ResponseDeadline   *string       `json:"responseDeadLine"`
+	UILink             string        `json:"uiLink"`
+	Active             string        `json:"active"`
+	Description        string        `json:"description"`               // Link to the noticedesc API in search results
+	DescriptionText    string        `json:"descriptionText,omitempty"` // Resolved description, when fetched
+	PointOfContact     []Contact     `json:"pointOfContact"`
+	Award              *Award        `json:"award,omitempty"`
+	PlaceOfPerformance *Place        `json:"placeOfPerformance,omitempty"`
+	TypeOfSetAside     string        `json:"typeOfSetAside"`
+	NAICSCode          string        `json:"naicsCode"`
+	ResourceLinks      []string      `json:"resourceLinks,omitempty"` // Download URLs for solicitation documents
+	Changes            []FieldChange `json:"changes,omitempty"`       // Set on updated opportunities by the monitor
+	Relevance          *Relevance    `json:"relevance,omitempty"`     // Set by the monitor when the query has scoring rules
+}
+
+// Relevance is the score a query's scoring rules gave an opportunity
+type Relevance struct {
+	Score    float64  `json:"score"`
+	Priority string   `json:"priority"`          // high, medium or low
+	Reasons  []string `json:"reasons,omitempty"` // One entry per rule that contributed points
 }
 
 // Contact represents a point of contact for an opportunity
@@ -36,9 +48,17 @@ type Contact struct {
 
 // Award contains award information if available
 type Award struct {
-	Date   string      `json:"date"`
-	Number string      `json:"number"`
-	Amount interface{} `json:"amount"`
+	Date    string      `json:"date"`
+	Number  string      `json:"number"`
+	Amount  interface{} `json:"amount"`
+	Awardee *Awardee    `json:"awardee,omitempty"`
+}
+
+// Awardee is the winner named by an award notice
+type Awardee struct {
+	Name     string `json:"name"`
+	UEISAM   string `json:"ueiSAM,omitempty"`
+	Location *Place `json:"location,omitempty"`
 }
 
 // Place represents place of performance
@@ -65,6 +85,10 @@ type QueryResult struct {
 	FilteredOut   []Opportunity `json:"filteredOut,omitempty"`
 	ExecutionTime time.Duration `json:"executionTime"`
 	Error         error         `json:"error,omitempty"`
+	TotalRecords  int           `json:"totalRecords"`
+	Pages         int           `json:"pages"`
+	Truncated     bool          `json:"truncated,omitempty"` // Budget ran out before every page was fetched
+	Fallback      bool          `json:"fallback,omitempty"`  // Results of a broader recovery search, not notified
 }
 
 // DiffResult represents the difference between current and previous opportunities
@@ -76,13 +100,69 @@ type DiffResult struct {
 
 // OpportunityState tracks the state of an opportunity over time
 type OpportunityState struct {
-	FirstSeen    time.Time `json:"first_seen"`
-	LastSeen     time.Time `json:"last_seen"`
-	LastModified time.Time `json:"last_modified"`
-	NoticeID     string    `json:"notice_id"`
-	Title        string    `json:"title"`
-	Deadline     *string   `json:"deadline,omitempty"`
-	Hash         string    `json:"hash"`
+	FirstSeen            time.Time            `json:"first_seen"`
+	LastSeen             time.Time            `json:"last_seen"`
+	LastModified         time.Time            `json:"last_modified"`
+	NoticeID             string               `json:"notice_id"`
+	Title                string               `json:"title"`
+	Deadline             *string              `json:"deadline,omitempty"`
+	Hash                 string               `json:"hash"`
+	ResourceLinks        []string             `json:"resource_links,omitempty"`
+	ResourceLinksTracked bool                 `json:"resource_links_tracked,omitempty"` // False for entries saved before links were recorded
+	Documents            []Document           `json:"documents,omitempty"`
+	Versions             []OpportunityVersion `json:"versions,omitempty"`       // Every observed version, oldest first
+	Queries              []string             `json:"queries,omitempty"`        // Queries that have matched the notice
+	RemindersSent        []string             `json:"reminders_sent,omitempty"` // Deadline reminders already sent, see monitor.reminderKey
+	Stage                Stage                `json:"stage,omitempty"`          // Capture pipeline stage; empty means StageNew
+	Owner                string               `json:"owner,omitempty"`          // Who is working the capture
+	Notes                string               `json:"notes,omitempty"`
+	StageUpdated         time.Time            `json:"stage_updated,omitempty"` // When the stage, owner or notes last changed
+}
+
+// Document is a downloaded solicitation file
+type Document struct {
+	URL          string    `json:"url"`
+	Name         string    `json:"name"`
+	Path         string    `json:"path"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// NewResourceLinks returns links in current that are not in previous
+func NewResourceLinks(previous, current []string) []string {
+	seen := make(map[string]bool, len(previous))
+	for _, link := range previous {
+		seen[link] = true
+	}
+
+	added := make([]string, 0)
+	for _, link := range current {
+		if !seen[link] {
+			added = append(added, link)
+			seen[link] = true
+		}
+	}
+	return added
+}
+
+// ParseDate parses the date formats SAM.gov uses for posted dates and
+// response deadlines: RFC 3339 timestamps or a leading YYYY-MM-DD
+func ParseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if len(value) >= 10 {
+		if t, err := time.Parse("2006-01-02", value[:10]); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
 }
 
 // APIError represents an error from the SAM.gov API
@@ -0,0 +1,161 @@
+package samgov
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldChange is one field that differs between two versions of an opportunity
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// String renders the change as "Field: old → new"
+func (fc FieldChange) String() string {
+	if fc.Old == "" && fc.New == "" {
+		return fc.Field + " changed"
+	}
+	return fmt.Sprintf("%s: %s → %s", fc.Field, orNone(fc.Old), orNone(fc.New))
+}
+
+// OpportunitySnapshot holds the fields of an opportunity that are compared
+// between observed versions
+type OpportunitySnapshot struct {
+	Title              string   `json:"title"`
+	SolicitationNumber string   `json:"solicitation_number,omitempty"`
+	Type               string   `json:"type,omitempty"`
+	PostedDate         string   `json:"posted_date,omitempty"`
+	ResponseDeadline   string   `json:"response_deadline,omitempty"`
+	SetAside           string   `json:"set_aside,omitempty"`
+	NAICSCode          string   `json:"naics_code,omitempty"`
+	Organization       string   `json:"organization,omitempty"`
+	PointOfContact     string   `json:"point_of_contact,omitempty"`
+	PlaceOfPerformance string   `json:"place_of_performance,omitempty"`
+	Active             string   `json:"active,omitempty"`
+	DescriptionHash    string   `json:"description_hash,omitempty"` // Only set when the full text was fetched
+	ResourceLinks      []string `json:"resource_links,omitempty"`
+}
+
+// OpportunityVersion is one observed version of an opportunity
+type OpportunityVersion struct {
+	ObservedAt time.Time           `json:"observed_at"`
+	Snapshot   OpportunitySnapshot `json:"snapshot"`
+}
+
+// Snapshot captures the comparable fields of opp
+func Snapshot(opp Opportunity) OpportunitySnapshot {
+	snapshot := OpportunitySnapshot{
+		Title:              opp.Title,
+		SolicitationNumber: opp.SolicitationNum,
+		Type:               opp.Type,
+		PostedDate:         opp.PostedDate,
+		SetAside:           opp.TypeOfSetAside,
+		NAICSCode:          opp.NAICSCode,
+		Organization:       opp.FullParentPath,
+		PointOfContact:     formatContacts(opp.PointOfContact),
+		PlaceOfPerformance: formatPlace(opp.PlaceOfPerformance),
+		Active:             opp.Active,
+		ResourceLinks:      append([]string(nil), opp.ResourceLinks...),
+	}
+	if opp.ResponseDeadline != nil {
+		snapshot.ResponseDeadline = *opp.ResponseDeadline
+	}
+	if opp.DescriptionText != "" {
+		snapshot.DescriptionHash = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.TrimSpace(opp.DescriptionText))))
+	}
+	return snapshot
+}
+
+// Diff lists the fields that differ from s to next, in a fixed order.
+// Descriptions are only compared when both versions have the full text,
+// since it is fetched on demand.
+func (s OpportunitySnapshot) Diff(next OpportunitySnapshot) []FieldChange {
+	changes := make([]FieldChange, 0)
+	compare := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	compare("Title", s.Title, next.Title)
+	compare("Response Deadline", s.ResponseDeadline, next.ResponseDeadline)
+	compare("Set-Aside", s.SetAside, next.SetAside)
+	compare("NAICS Code", s.NAICSCode, next.NAICSCode)
+	compare("Notice Type", s.Type, next.Type)
+	compare("Solicitation Number", s.SolicitationNumber, next.SolicitationNumber)
+	compare("Point of Contact", s.PointOfContact, next.PointOfContact)
+	compare("Place of Performance", s.PlaceOfPerformance, next.PlaceOfPerformance)
+	compare("Organization", s.Organization, next.Organization)
+	compare("Posted Date", s.PostedDate, next.PostedDate)
+	compare("Active", s.Active, next.Active)
+
+	if s.DescriptionHash != "" && next.DescriptionHash != "" && s.DescriptionHash != next.DescriptionHash {
+		changes = append(changes, FieldChange{Field: "Description"})
+	}
+
+	if added := NewResourceLinks(s.ResourceLinks, next.ResourceLinks); len(added) > 0 {
+		changes = append(changes, FieldChange{
+			Field: "Documents",
+			Old:   fmt.Sprintf("%d files", len(s.ResourceLinks)),
+			New:   fmt.Sprintf("%d files (%d new)", len(s.ResourceLinks)+len(added), len(added)),
+		})
+	}
+
+	return changes
+}
+
+// LatestVersion returns the most recently observed version, if any were recorded
+func (s OpportunityState) LatestVersion() (OpportunityVersion, bool) {
+	if len(s.Versions) == 0 {
+		return OpportunityVersion{}, false
+	}
+	return s.Versions[len(s.Versions)-1], true
+}
+
+// formatContacts renders contacts as "Name <email>" joined by semicolons
+func formatContacts(contacts []Contact) string {
+	parts := make([]string, 0, len(contacts))
+	for _, c := range contacts {
+		part := strings.TrimSpace(c.FullName)
+		if c.Email != "" {
+			if part != "" {
+				part += " "
+			}
+			part += "<" + c.Email + ">"
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatPlace renders a place of performance as "City, State Zip, Country"
+func formatPlace(p *Place) string {
+	if p == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, 3)
+	if city := p.GetCity(); city != "" {
+		parts = append(parts, city)
+	}
+	if state := strings.TrimSpace(p.GetState() + " " + p.GetZipCode()); state != "" {
+		parts = append(parts, state)
+	}
+	if country := p.GetCountry(); country != "" {
+		parts = append(parts, country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
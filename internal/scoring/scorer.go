@@ -0,0 +1,234 @@
+// Package scoring ranks opportunities by the relevance rules configured on
+// a query, so the best matches are listed first and weak ones can be dropped.
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+// Priorities assigned to scored opportunities
+const (
+	PriorityHigh   = "high"
+	PriorityMedium = "medium"
+	PriorityLow    = "low"
+)
+
+var priorityRank = map[string]int{PriorityLow: 1, PriorityMedium: 2, PriorityHigh: 3}
+
+// Scorer applies one query's scoring rules
+type Scorer struct {
+	config   config.ScoringConfig
+	keywords []keywordRule
+	agencies []string // Sorted so reasons come out in a stable order
+	now      func() time.Time
+
+	queryPriority string // Used when no thresholds are set, and the most a score can reach
+}
+
+type keywordRule struct {
+	keyword string
+	points  float64
+	pattern *regexp.Regexp
+}
+
+// New creates a scorer for the given rules
+func New(cfg config.ScoringConfig) *Scorer {
+	s := &Scorer{
+		config: cfg,
+		now:    time.Now,
+	}
+
+	for keyword, points := range cfg.Keywords {
+		// Whole-word matching keeps short keywords like "AI" out of "maintain".
+		// \b would need a word character at each end, which "C++" and ".NET"
+		// lack, so the keyword is bounded by non-word characters instead.
+		pattern := regexp.MustCompile(`(?i)(?:^|[^\w])` + regexp.QuoteMeta(strings.TrimSpace(keyword)) + `(?:$|[^\w])`)
+		s.keywords = append(s.keywords, keywordRule{keyword: keyword, points: points, pattern: pattern})
+	}
+	sort.Slice(s.keywords, func(i, j int) bool { return s.keywords[i].keyword < s.keywords[j].keyword })
+
+	for agency := range cfg.Agencies {
+		s.agencies = append(s.agencies, agency)
+	}
+	sort.Strings(s.agencies)
+
+	return s
+}
+
+// Score computes the relevance of a single opportunity
+func (s *Scorer) Score(opp samgov.Opportunity) samgov.Relevance {
+	relevance := samgov.Relevance{Reasons: make([]string, 0)}
+	add := func(points float64, format string, args ...interface{}) {
+		if points == 0 {
+			return
+		}
+		relevance.Score += points
+		relevance.Reasons = append(relevance.Reasons, fmt.Sprintf("%s (%+g)", fmt.Sprintf(format, args...), points))
+	}
+
+	description := opp.DescriptionBody()
+	for _, rule := range s.keywords {
+		if rule.pattern.MatchString(opp.Title) {
+			add(rule.points, "keyword %q in title", rule.keyword)
+		} else if rule.pattern.MatchString(description) {
+			add(rule.points/2, "keyword %q in description", rule.keyword)
+		}
+	}
+
+	if points, reason := s.scoreNAICS(opp.NAICSCode); reason != "" {
+		add(points, "%s", reason)
+	}
+
+	if setAside := strings.TrimSpace(opp.TypeOfSetAside); setAside != "" {
+		for code, points := range s.config.SetAsides {
+			if strings.EqualFold(code, setAside) {
+				add(points, "set-aside %s", setAside)
+				break
+			}
+		}
+	}
+
+	for _, agency := range s.agencies {
+		if strings.Contains(strings.ToLower(opp.FullParentPath), strings.ToLower(agency)) {
+			add(s.config.Agencies[agency], "agency %s", agency)
+		}
+	}
+
+	if s.config.Deadline.Points != 0 && opp.ResponseDeadline != nil {
+		if deadline, ok := samgov.ParseDate(*opp.ResponseDeadline); ok {
+			days := float64(int(deadline.Sub(s.now()).Hours() / 24))
+			if days >= 0 && inRange(days, s.config.Deadline) {
+				add(s.config.Deadline.Points, "%g days to respond", days)
+			}
+		}
+	}
+
+	if s.config.Value.Points != 0 && opp.Award != nil && opp.Award.Amount != nil {
+		if amount := opp.Award.GetAmount(); inRange(amount, s.config.Value) {
+			add(s.config.Value.Points, "award value $%.0f", amount)
+		}
+	}
+
+	relevance.Priority = s.Priority(relevance.Score)
+	return relevance
+}
+
+// SetQueryPriority sets the query's own notification priority. Every
+// opportunity gets it when neither threshold is set, and no score raises an
+// opportunity above it.
+func (s *Scorer) SetQueryPriority(priority string) {
+	s.queryPriority = priority
+}
+
+// Priority maps a score onto high, medium or low using the configured
+// thresholds, capped at the query priority. Without thresholds it returns
+// the query priority, which is empty unless set.
+func (s *Scorer) Priority(score float64) string {
+	if s.config.HighPriority == 0 && s.config.LowPriority == 0 {
+		return s.queryPriority
+	}
+
+	priority := PriorityMedium
+	if s.config.HighPriority != 0 && score >= s.config.HighPriority {
+		priority = PriorityHigh
+	} else if s.config.LowPriority != 0 && score < s.config.LowPriority {
+		priority = PriorityLow
+	}
+	if s.queryPriority != "" && priorityRank[priority] > priorityRank[s.queryPriority] {
+		return s.queryPriority
+	}
+	return priority
+}
+
+// Apply scores each opportunity, drops those below the minimum score and
+// returns the rest sorted from most to least relevant. Ties keep their
+// original order.
+func (s *Scorer) Apply(opportunities []samgov.Opportunity) (kept []samgov.Opportunity, dropped []samgov.Opportunity) {
+	kept = make([]samgov.Opportunity, 0, len(opportunities))
+	dropped = make([]samgov.Opportunity, 0)
+
+	for _, opp := range opportunities {
+		relevance := s.Score(opp)
+		opp.Relevance = &relevance
+		if s.config.MinScore != 0 && relevance.Score < s.config.MinScore {
+			dropped = append(dropped, opp)
+			continue
+		}
+		kept = append(kept, opp)
+	}
+
+	SortByScore(kept)
+	return kept, dropped
+}
+
+// SortByScore orders opportunities from highest to lowest score. Unscored
+// opportunities sort last.
+func SortByScore(opportunities []samgov.Opportunity) {
+	sort.SliceStable(opportunities, func(i, j int) bool {
+		return scoreOf(opportunities[i]) > scoreOf(opportunities[j])
+	})
+}
+
+// HighestPriority returns the most urgent priority among scored
+// opportunities, or "" when none were scored
+func HighestPriority(opportunities []samgov.Opportunity) string {
+	best := ""
+	for _, opp := range opportunities {
+		if opp.Relevance != nil && priorityRank[opp.Relevance.Priority] > priorityRank[best] {
+			best = opp.Relevance.Priority
+		}
+	}
+	return best
+}
+
+// scoreNAICS awards exact-match points, or prefix points when only the
+// leading digits match one of the listed codes
+func (s *Scorer) scoreNAICS(code string) (float64, string) {
+	code = strings.TrimSpace(code)
+	if code == "" || len(s.config.NAICS.Codes) == 0 {
+		return 0, ""
+	}
+
+	for _, want := range s.config.NAICS.Codes {
+		if code == strings.TrimSpace(want) {
+			return s.config.NAICS.Exact, fmt.Sprintf("NAICS %s", code)
+		}
+	}
+
+	length := s.config.NAICS.PrefixLength
+	if length == 0 {
+		length = config.DefaultNAICSPrefixLength
+	}
+	if len(code) < length {
+		return 0, ""
+	}
+	for _, want := range s.config.NAICS.Codes {
+		want = strings.TrimSpace(want)
+		if len(want) >= length && want[:length] == code[:length] {
+			return s.config.NAICS.Prefix, fmt.Sprintf("NAICS %s shares prefix %s", code, code[:length])
+		}
+	}
+	return 0, ""
+}
+
+func inRange(value float64, r config.RangeScoring) bool {
+	if value < r.Min {
+		return false
+	}
+	return r.Max == 0 || value <= r.Max
+}
+
+func scoreOf(opp samgov.Opportunity) float64 {
+	if opp.Relevance == nil {
+		return math.Inf(-1)
+	}
+	return opp.Relevance.Score
+}
@@ -0,0 +1,173 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestKeywordMatching(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyword     string
+		title       string
+		description string
+		want        float64
+	}{
+		{"whole word in title", "AI", "AI-enabled analytics", "", 10},
+		{"case is ignored", "cloud", "Cloud Migration", "", 10},
+		{"part of a word does not match", "AI", "Facility maintenance", "", 0},
+		{"description earns half", "zero trust", "Network services", "Adopt a zero trust architecture.", 5},
+		{"title wins over description", "zero trust", "Zero Trust pilot", "zero trust", 10},
+		{"keyword ending in symbols", "C++", "C++ developer support", "", 10},
+		{"keyword ending in symbols at the end", "C++", "Legacy code in C++", "", 10},
+		{"keyword ending in symbols inside a word", "C++", "ABC++X", "", 0},
+		{"keyword starting with a dot", ".NET", "Migrate .NET applications", "", 10},
+		{"keyword starting with a dot at the start", ".NET", ".NET modernization", "", 10},
+		{"keyword starting with a dot inside a word", ".NET", "ASP.NET hosting", "", 0},
+		{"surrounding spaces are trimmed", " AI ", "AI services", "", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := New(config.ScoringConfig{Keywords: map[string]float64{tt.keyword: 10}})
+			relevance := scorer.Score(samgov.Opportunity{Title: tt.title, DescriptionText: tt.description})
+			if relevance.Score != tt.want {
+				t.Errorf("score = %g, want %g (reasons %v)", relevance.Score, tt.want, relevance.Reasons)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	deadline := "2024-03-21"
+	cfg := config.ScoringConfig{
+		NAICS:     config.NAICSScoring{Codes: []string{"541512"}, Exact: 15, Prefix: 5},
+		SetAsides: map[string]float64{"SBA": 10},
+		Agencies:  map[string]float64{"Navy": -10},
+		Deadline:  config.RangeScoring{Min: 7, Max: 60, Points: 5},
+		Value:     config.RangeScoring{Min: 100000, Points: 5},
+	}
+
+	tests := []struct {
+		name string
+		opp  samgov.Opportunity
+		want float64
+	}{
+		{"nothing matches", samgov.Opportunity{NAICSCode: "236220"}, 0},
+		{"exact NAICS", samgov.Opportunity{NAICSCode: "541512"}, 15},
+		{"NAICS prefix", samgov.Opportunity{NAICSCode: "541519"}, 5},
+		{"set-aside ignores case", samgov.Opportunity{TypeOfSetAside: "sba"}, 10},
+		{"agency penalty", samgov.Opportunity{FullParentPath: "DEPT OF DEFENSE.DEPT OF THE NAVY"}, -10},
+		{"deadline in range", samgov.Opportunity{ResponseDeadline: &deadline}, 5},
+		{"award value in range", samgov.Opportunity{Award: &samgov.Award{Amount: 250000.0}}, 5},
+		{"award value below range", samgov.Opportunity{Award: &samgov.Award{Amount: 5000.0}}, 0},
+		{"points add up", samgov.Opportunity{NAICSCode: "541512", TypeOfSetAside: "SBA", ResponseDeadline: &deadline}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := New(cfg)
+			scorer.now = func() time.Time { return now }
+			relevance := scorer.Score(tt.opp)
+			if relevance.Score != tt.want {
+				t.Errorf("score = %g, want %g (reasons %v)", relevance.Score, tt.want, relevance.Reasons)
+			}
+		})
+	}
+}
+
+func TestPriority(t *testing.T) {
+	thresholds := config.ScoringConfig{HighPriority: 30, LowPriority: 15}
+
+	tests := []struct {
+		name          string
+		cfg           config.ScoringConfig
+		queryPriority string
+		score         float64
+		want          string
+	}{
+		{"high score", thresholds, "", 30, PriorityHigh},
+		{"middle score", thresholds, "", 20, PriorityMedium},
+		{"low score", thresholds, "", 10, PriorityLow},
+		{"high score on a high query", thresholds, PriorityHigh, 40, PriorityHigh},
+		{"high score is capped at a low query", thresholds, PriorityLow, 40, PriorityLow},
+		{"high score is capped at a medium query", thresholds, PriorityMedium, 40, PriorityMedium},
+		{"low score lowers a high query", thresholds, PriorityHigh, 10, PriorityLow},
+		{"only a high threshold", config.ScoringConfig{HighPriority: 30}, "", 0, PriorityMedium},
+		{"only a low threshold", config.ScoringConfig{LowPriority: 15}, "", 100, PriorityMedium},
+		{"no thresholds keep the query priority", config.ScoringConfig{}, PriorityLow, 100, PriorityLow},
+		{"no thresholds and no query priority", config.ScoringConfig{}, "", 100, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := New(tt.cfg)
+			scorer.SetQueryPriority(tt.queryPriority)
+			if got := scorer.Priority(tt.score); got != tt.want {
+				t.Errorf("Priority(%g) = %q, want %q", tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	scorer := New(config.ScoringConfig{
+		Keywords: map[string]float64{"cloud": 10, "security": 20},
+		MinScore: 10,
+	})
+
+	kept, dropped := scorer.Apply([]samgov.Opportunity{
+		{NoticeID: "cloud", Title: "Cloud hosting"},
+		{NoticeID: "none", Title: "Janitorial services"},
+		{NoticeID: "both", Title: "Cloud security"},
+		{NoticeID: "security", Title: "Security review"},
+	})
+
+	wantKept := []string{"both", "security", "cloud"}
+	if len(kept) != len(wantKept) {
+		t.Fatalf("kept %d opportunities, want %d", len(kept), len(wantKept))
+	}
+	for i, opp := range kept {
+		if opp.NoticeID != wantKept[i] {
+			t.Errorf("kept[%d] = %s, want %s", i, opp.NoticeID, wantKept[i])
+		}
+		if opp.Relevance == nil {
+			t.Errorf("%s has no relevance", opp.NoticeID)
+		}
+	}
+	if len(dropped) != 1 || dropped[0].NoticeID != "none" || dropped[0].Relevance == nil {
+		t.Errorf("dropped = %+v, want the scored janitorial notice", dropped)
+	}
+}
+
+func TestHighestPriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities []string // "" is an unscored opportunity
+		want       string
+	}{
+		{"nothing scored", []string{"", ""}, ""},
+		{"most urgent wins", []string{PriorityLow, PriorityHigh, PriorityMedium}, PriorityHigh},
+		{"unscored are ignored", []string{"", PriorityLow}, PriorityLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opportunities := make([]samgov.Opportunity, 0, len(tt.priorities))
+			for _, priority := range tt.priorities {
+				opp := samgov.Opportunity{}
+				if priority != "" {
+					opp.Relevance = &samgov.Relevance{Priority: priority}
+				}
+				opportunities = append(opportunities, opp)
+			}
+			if got := HighestPriority(opportunities); got != tt.want {
+				t.Errorf("HighestPriority() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestAwardTrackingIntegration(t *testing.T) {
+	stateFile := t.TempDir() + "/monitor.json"
+	state, err := monitor.LoadState(stateFile)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+
+	now := time.Now()
+	awards := []samgov.Opportunity{
+		{NoticeID: "AWD-1", Title: "Radar Maintenance", Type: "Award Notice", SolicitationNum: "fa8650 24 r 0001",
+			FullParentPath: "DEPT OF DEFENSE.DEPT OF THE AIR FORCE.AFMC", NAICSCode: "336411",
+			Award: &samgov.Award{Number: "FA8650-24-C-0001", Amount: "$1,250,000.00", Date: "2024-03-01",
+				Awardee: &samgov.Awardee{Name: "Acme Corp", UEISAM: "ACME00000001"}}},
+		{NoticeID: "AWD-2", Title: "Hangar Repairs", Type: "Award Notice",
+			FullParentPath: "DEPT OF DEFENSE.DEPT OF THE AIR FORCE.ACC", NAICSCode: "236220",
+			Award: &samgov.Award{Amount: 400000.0, Awardee: &samgov.Awardee{Name: "ACME CORP.", UEISAM: "ACME00000001"}}},
+		{NoticeID: "AWD-3", Title: "Radar Spares", Type: "a",
+			FullParentPath: "DEPT OF THE NAVY", NAICSCode: "336411",
+			Award: &samgov.Award{Amount: 75000.0, Awardee: &samgov.Awardee{Name: "Beta LLC"}}},
+	}
+	for _, opp := range awards {
+		record := samgov.NewAwardRecord(opp, "Awards", now)
+		if opp.NoticeID == "AWD-1" {
+			record.LinkedNoticeID = "SOL-1"
+		}
+		if !state.RecordAward(record) {
+			t.Errorf("Expected award %s to be new", opp.NoticeID)
+		}
+	}
+	// Seeing an award again keeps its link
+	if state.RecordAward(samgov.NewAwardRecord(awards[0], "Awards", now.Add(time.Hour))) {
+		t.Error("Expected a repeated award not to be new")
+	}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	reloaded, err := monitor.LoadState(stateFile)
+	if err != nil {
+		t.Fatalf("Failed to reload state: %v", err)
+	}
+	report := monitor.BuildAwardReport(reloaded.ListAwards())
+	if report.Awards != 3 || report.Linked != 1 || report.TotalAmount != 1725000 {
+		t.Fatalf("Expected 3 awards, 1 linked, $1,725,000 total, got %d, %d, %v", report.Awards, report.Linked, report.TotalAmount)
+	}
+	if samgov.NormalizeSolicitationNumber("fa8650 24 r 0001") != samgov.NormalizeSolicitationNumber("FA8650-24-R-0001") {
+		t.Error("Expected solicitation numbers to match regardless of case and separators")
+	}
+
+	if len(report.ByAgency) != 2 || report.ByAgency[0].Key != "DEPT OF DEFENSE.DEPT OF THE AIR FORCE" {
+		t.Fatalf("Unexpected agency groups: %+v", report.ByAgency)
+	}
+	winners := report.ByAgency[0].Winners
+	if len(winners) != 1 || winners[0].Awards != 2 || winners[0].TotalAmount != 1650000 {
+		t.Errorf("Expected one awardee with both Air Force awards by UEI, got %+v", winners)
+	}
+	if len(report.ByNAICS) != 2 || report.ByNAICS[0].Key != "336411" || len(report.ByNAICS[0].Winners) != 2 {
+		t.Errorf("Expected two winners under NAICS 336411 first, got %+v", report.ByNAICS)
+	}
+
+	var payload notify.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	notification := notify.NewNotificationBuilder().
+		WithQuery("Awards", notify.PriorityMedium).
+		WithAwards(awards[:1]).
+		WithSubject("🏆 1 Tracked SAM.gov Opportunities Awarded - Awards").
+		Build()
+	notifier := notify.NewWebhookNotifier(notify.WebhookConfig{Enabled: true, URL: server.URL}, testing.Verbose())
+	if err := notifier.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Webhook notification failed: %v", err)
+	}
+	if payload.Event != "awarded" || payload.Summary.AwardedOpportunities != 1 {
+		t.Errorf("Expected an awarded event for 1 opportunity, got '%s' (%d)", payload.Event, payload.Summary.AwardedOpportunities)
+	}
+}
@@ -0,0 +1,108 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestGitHubIssueLifecycleIntegration(t *testing.T) {
+	var created, comments, closed []string
+	var labels []string
+	listed := "[]"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/bids/issues":
+			fmt.Fprint(w, listed)
+		case r.Method == "POST" && r.URL.Path == "/repos/acme/bids/issues":
+			created = append(created, string(body))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"number": %d}`, 40+len(created))
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/comments"):
+			comments = append(comments, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/labels"):
+			labels = append(labels, string(body))
+			fmt.Fprint(w, `[]`)
+		case r.Method == "PATCH":
+			closed = append(closed, r.URL.Path)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	state, err := monitor.LoadState("")
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	config := notify.GitHubConfig{Enabled: true, Token: "test", Owner: "acme", Repository: "bids", APIURL: server.URL}
+	notifier := notify.NewGitHubNotifier(config, false)
+	notifier.SetIssueStore(state)
+
+	opp := samgov.Opportunity{NoticeID: "GH-001", Title: "Hypersonics Research", UILink: "https://sam.gov/opp/GH-001/view"}
+	send := func(n *notify.GitHubNotifier, opp samgov.Opportunity) {
+		notification := notify.NewNotificationBuilder().
+			WithQuery("Hypersonics", notify.PriorityHigh).
+			WithOpportunities([]samgov.Opportunity{opp}).
+			Build()
+		if err := n.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	// A notice reported twice gets one issue
+	send(notifier, opp)
+	send(notifier, opp)
+	if len(created) != 1 || !strings.Contains(created[0], "sam-gov-monitor:notice=GH-001") {
+		t.Fatalf("Expected one issue with a notice marker, got %d", len(created))
+	}
+	if record, ok := state.GetIssue("acme/bids", "GH-001"); !ok || record.Number != 41 {
+		t.Errorf("Expected issue #41 to be recorded, got %+v", record)
+	}
+
+	// An amendment is commented on the existing issue
+	amended := opp
+	amended.Changes = []samgov.FieldChange{{Field: "Deadline", Old: "2025-03-01", New: "2025-03-15"}}
+	send(notifier, amended)
+	if len(created) != 1 || len(comments) != 1 || comments[0] != "/repos/acme/bids/issues/41/comments" {
+		t.Errorf("Expected a comment on issue #41, got %v (%d issues)", comments, len(created))
+	}
+
+	// Without a stored record the issue is found by its marker
+	listed = `[{"number": 7, "state": "open", "body": "details\n<!-- sam-gov-monitor:notice=GH-002 -->"}]`
+	fresh := notify.NewGitHubNotifier(config, false)
+	other := amended
+	other.NoticeID = "GH-002"
+	send(fresh, other)
+	if len(created) != 1 || comments[len(comments)-1] != "/repos/acme/bids/issues/7/comments" {
+		t.Errorf("Expected the amendment on marked issue #7, got %v (%d issues)", comments, len(created))
+	}
+
+	// Awarded notices are closed once, with a label
+	ok, err := notifier.CloseIssue(context.Background(), notify.IssueClosure{NoticeID: "GH-001", Reason: notify.ClosedAwarded})
+	if err != nil || !ok {
+		t.Fatalf("Expected issue #41 to be closed, got %v, %v", ok, err)
+	}
+	if len(closed) != 1 || closed[0] != "/repos/acme/bids/issues/41" || !strings.Contains(labels[0], "awarded") {
+		t.Errorf("Expected issue #41 closed with the awarded label, got %v %v", closed, labels)
+	}
+	if ok, _ := notifier.CloseIssue(context.Background(), notify.IssueClosure{NoticeID: "GH-001", Reason: notify.ClosedAwarded}); ok {
+		t.Errorf("Expected a closed issue not to be closed again")
+	}
+}
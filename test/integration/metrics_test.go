@@ -0,0 +1,102 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestPrometheusMetricsIntegration(t *testing.T) {
+	dir := t.TempDir()
+	metricsFile := dir + "/metrics.json"
+	collector := monitor.NewMetricsCollector(metricsFile, false)
+
+	// Every HTTP attempt is reported to the client's observer
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("title") == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(samgov.SearchResponse{})
+	}))
+	defer api.Close()
+
+	client := samgov.NewClientWithOptions("key", api.URL, 5*time.Second)
+	client.SetRequestObserver(func(duration time.Duration, err error) {
+		collector.RecordAPIRequest(duration, err == nil, 0)
+	})
+	client.Search(context.Background(), map[string]string{"title": "radar"})
+	client.Search(context.Background(), map[string]string{"title": "broken"})
+
+	start := collector.RecordRunStart()
+	query := config.Query{Name: `Radar "Sustainment"`}
+	collector.RecordQueryExecution(query, 2*time.Second, true, nil, 7)
+	collector.RecordQueryChanges(query.Name, 3, 1)
+	collector.RecordNotification("email", true)
+	collector.RecordNotification("slack", false)
+	collector.RecordQuota(6, 10)
+	collector.RecordRetries(1, map[string]int{"HTTP_500": 1})
+	collector.RecordRunEnd(start)
+	if err := collector.SaveMetrics(); err != nil {
+		t.Fatalf("Failed to save metrics: %v", err)
+	}
+
+	server := httptest.NewServer(monitor.MetricsFileHandler(metricsFile))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Expected the Prometheus text format, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	for _, want := range []string{
+		`samgov_monitor_runs_total 1`,
+		`samgov_monitor_api_requests_total{result="success"} 1`,
+		`samgov_monitor_api_requests_total{result="failure"} 1`,
+		`samgov_monitor_api_request_duration_seconds_bucket{le="+Inf"} 2`,
+		`samgov_monitor_api_request_duration_seconds_count 2`,
+		`samgov_monitor_api_retries_total 1`,
+		`samgov_monitor_api_errors_total{reason="HTTP_500"} 1`,
+		`samgov_monitor_api_quota_remaining 6`,
+		`samgov_monitor_query_new_opportunities_total{query="Radar \"Sustainment\""} 3`,
+		`samgov_monitor_query_updated_opportunities_total{query="Radar \"Sustainment\""} 1`,
+		`samgov_monitor_notifications_sent_total{channel="email"} 1`,
+		`samgov_monitor_notification_failures_total{channel="slack"} 1`,
+		`# TYPE samgov_monitor_run_duration_seconds histogram`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected /metrics to contain %s", want)
+		}
+	}
+
+	// One-shot runs export the same text for the textfile collector
+	textfile := dir + "/textfile/samgov.prom"
+	if err := collector.WriteTextfile(textfile); err != nil {
+		t.Fatalf("Failed to write textfile: %v", err)
+	}
+	data, err := os.ReadFile(textfile)
+	if err != nil {
+		t.Fatalf("Failed to read textfile: %v", err)
+	}
+	if string(data) != string(body) {
+		t.Errorf("Expected the textfile to match /metrics")
+	}
+}
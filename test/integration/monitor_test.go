@@ -485,4 +485,4 @@ func TestEndToEndIntegration(t *testing.T) {
 	}
 
 	t.Log("End-to-end test completed successfully")
-}
\ No newline at end of file
+}
@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestCapturePipelineIntegration(t *testing.T) {
+	stateFile := t.TempDir() + "/monitor.json"
+	store, err := monitor.OpenStateStore(monitor.StateBackendSQLite, stateFile, false)
+	if err != nil {
+		t.Fatalf("Failed to open state: %v", err)
+	}
+	defer store.Close()
+
+	deadline := time.Now().AddDate(0, 0, 20).Format("2006-01-02")
+	store.AddOpportunity(samgov.Opportunity{NoticeID: "CAP-1", Title: "Radar Maintenance", ResponseDeadline: &deadline})
+	store.AddOpportunity(samgov.Opportunity{NoticeID: "CAP-2", Title: "Hangar Repairs"})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	owner := "alice"
+	if _, err := store.UpdatePipeline("CAP-1", monitor.PipelineChange{Stage: samgov.StagePursuing, Owner: &owner}); err != nil {
+		t.Fatalf("Expected new to move to pursuing: %v", err)
+	}
+	if _, err := store.UpdatePipeline("CAP-1", monitor.PipelineChange{Stage: samgov.StageWon}); err == nil {
+		t.Error("Expected pursuing not to move straight to won")
+	}
+	if _, err := store.UpdatePipeline("CAP-2", monitor.PipelineChange{Stage: samgov.StageNoBid}); err != nil {
+		t.Fatalf("Expected new to move to no-bid: %v", err)
+	}
+	if _, err := store.UpdatePipeline("CAP-9", monitor.PipelineChange{Stage: samgov.StageReviewing}); err == nil {
+		t.Error("Expected an untracked notice to be rejected")
+	}
+	if stage, err := samgov.ParseStage("NoBid"); err != nil || stage != samgov.StageNoBid {
+		t.Errorf("Expected NoBid to parse as no-bid, got %q (%v)", stage, err)
+	}
+
+	// Seeing the notices again keeps their pipeline details
+	store.AddOpportunity(samgov.Opportunity{NoticeID: "CAP-1", Title: "Radar Maintenance (Amended)", ResponseDeadline: &deadline})
+	store.AddOpportunity(samgov.Opportunity{NoticeID: "CAP-2", Title: "Hangar Repairs"})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+	tracked, _ := store.GetOpportunity("CAP-1")
+	if tracked.PipelineStage() != samgov.StagePursuing || tracked.Owner != "alice" || tracked.Title != "Radar Maintenance (Amended)" {
+		t.Errorf("Expected CAP-1 pursuing by alice with the amended title, got %s by %q: %s", tracked.PipelineStage(), tracked.Owner, tracked.Title)
+	}
+
+	server := httptest.NewServer(monitor.NewAPIServer(store, false).Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/opportunities?stage=no_bid")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var page monitor.OpportunityPage
+	json.NewDecoder(resp.Body).Decode(&page)
+	resp.Body.Close()
+	if page.Total != 1 || page.Opportunities[0].NoticeID != "CAP-2" || page.Opportunities[0].Stage != "no-bid" {
+		t.Errorf("Expected only CAP-2 in stage no-bid, got %+v", page.Opportunities)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/monitor"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestQueryAPIIntegration(t *testing.T) {
+	state, err := monitor.LoadState("")
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+
+	soon := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+	later := time.Now().AddDate(0, 0, 60).Format("2006-01-02")
+	for i := 1; i <= 12; i++ {
+		deadline := later
+		naics := "336411"
+		if i%3 == 0 {
+			deadline = soon
+			naics = "541511"
+		}
+		state.AddOpportunity(samgov.Opportunity{
+			NoticeID:         fmt.Sprintf("API-%03d", i),
+			Title:            fmt.Sprintf("Opportunity %d", i),
+			NAICSCode:        naics,
+			FullParentPath:   "DEPT OF DEFENSE.DEPT OF THE AIR FORCE",
+			ResponseDeadline: &deadline,
+		})
+		state.AddQueryMatch(fmt.Sprintf("API-%03d", i), "Software")
+	}
+
+	server := httptest.NewServer(monitor.NewAPIServer(state, false).Handler())
+	defer server.Close()
+
+	get := func(path string, target interface{}) int {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if target != nil {
+			json.NewDecoder(resp.Body).Decode(target)
+		}
+		return resp.StatusCode
+	}
+
+	var page monitor.OpportunityPage
+	if status := get("/api/opportunities?naics=5415&due_within=7&agency=air+force&query=software", &page); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", status)
+	}
+	if page.Total != 4 {
+		t.Errorf("Expected 4 opportunities due soon, got %d", page.Total)
+	}
+	for _, opp := range page.Opportunities {
+		if opp.UILink != "https://sam.gov/opp/"+opp.NoticeID+"/view" {
+			t.Errorf("Unexpected UI link %s", opp.UILink)
+		}
+	}
+
+	page = monitor.OpportunityPage{}
+	get("/api/opportunities?sort=title&per_page=5&page=3", &page)
+	if page.Total != 12 || page.Pages != 3 || len(page.Opportunities) != 2 {
+		t.Errorf("Expected the last 2 of 12 opportunities on page 3, got total %d, pages %d, %d items",
+			page.Total, page.Pages, len(page.Opportunities))
+	}
+
+	if status := get("/api/opportunities?deadline_after=soon", nil); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid date, got %d", status)
+	}
+	if status := get("/api/opportunities/API-999", nil); status != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown notice, got %d", status)
+	}
+
+	resp, err := http.Post(server.URL+"/api/opportunities", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("GET / failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "https://sam.gov/opp/API-003/view") {
+		t.Errorf("Expected the dashboard to link to an upcoming deadline")
+	}
+}
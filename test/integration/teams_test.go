@@ -0,0 +1,91 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestTeamsNotifierIntegration(t *testing.T) {
+	var cards []notify.TeamsMessage
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			http.Error(w, "Webhook message delivery failed", http.StatusBadRequest)
+			return
+		}
+		var message notify.TeamsMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Failed to decode Teams message: %v", err)
+		}
+		cards = append(cards, message)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	// Enough opportunities with long descriptions to need several cards
+	deadline := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	opportunities := make([]samgov.Opportunity, 0, 60)
+	for i := 0; i < 60; i++ {
+		opportunities = append(opportunities, samgov.Opportunity{
+			NoticeID:         fmt.Sprintf("TEAMS-%03d", i),
+			Title:            fmt.Sprintf("Test Opportunity %d", i),
+			FullParentPath:   "DEPT OF DEFENSE.DEFENSE ADVANCED RESEARCH PROJECTS AGENCY",
+			NAICSCode:        "541511",
+			TypeOfSetAside:   "SBA",
+			ResponseDeadline: &deadline,
+			DescriptionText:  strings.Repeat("Autonomous systems research. ", 20),
+			UILink:           fmt.Sprintf("https://sam.gov/opp/TEAMS-%03d/view", i),
+		})
+	}
+
+	notification := notify.NewNotificationBuilder().
+		WithQuery("Teams Integration Query", notify.PriorityHigh).
+		WithOpportunities(opportunities).
+		WithSubject("60 New SAM.gov Opportunities").
+		Build()
+
+	notifier := notify.NewTeamsNotifier(notify.TeamsConfig{Enabled: true, WebhookURL: server.URL}, testing.Verbose())
+	if err := notifier.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Teams notification failed: %v", err)
+	}
+
+	if len(cards) < 2 {
+		t.Fatalf("Expected the notification to be split, got %d cards", len(cards))
+	}
+
+	seen := 0
+	for _, message := range cards {
+		data, _ := json.Marshal(message)
+		if len(data) > 28*1024 {
+			t.Errorf("Card is %d bytes, over the Teams limit", len(data))
+		}
+		card := message.Attachments[0].Content
+		if card.Type != "AdaptiveCard" {
+			t.Errorf("Expected an AdaptiveCard, got %s", card.Type)
+		}
+		if card.Body[0].Style != "attention" {
+			t.Errorf("Expected high priority header style 'attention', got '%s'", card.Body[0].Style)
+		}
+		seen += strings.Count(string(data), "Open in SAM.gov")
+	}
+	if seen != len(opportunities) {
+		t.Errorf("Expected %d SAM.gov actions across all cards, got %d", len(opportunities), seen)
+	}
+
+	failing = true
+	if err := notifier.Send(context.Background(), notification); err == nil {
+		t.Error("Expected an error from a failing webhook")
+	}
+}
@@ -0,0 +1,112 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/config"
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestNotificationTemplatesIntegration(t *testing.T) {
+	configDir := t.TempDir()
+	templateDir := configDir + "/templates"
+	if err := os.Mkdir(templateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(templateDir+"/"+name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("brief.slack.tmpl", `*{{.QueryName}}*{{range .Opportunities}}
+• {{.Title}} due {{formatDeadline .ResponseDeadline}} ({{daysUntil .ResponseDeadline}} days){{with .Award}} {{money .Amount}}{{end}}{{end}}`)
+	write("unguarded.slack.tmpl", `{{range .Opportunities}}{{money .Award.Amount}}{{end}}`)
+	write("misspelled.email.tmpl", `<p>{{.QueryNmae}}</p>`)
+
+	if _, err := notify.LoadTemplates(templateDir, "brief"); err != nil {
+		t.Fatalf("Expected brief to load: %v", err)
+	}
+	for _, name := range []string{"unguarded", "misspelled", "missing", "../brief"} {
+		if _, err := notify.LoadTemplates(templateDir, name); err == nil {
+			t.Errorf("Expected template %s to fail validation", name)
+		}
+	}
+
+	// Templates are checked when the config is loaded, relative to it
+	writeConfig := func(template string) string {
+		path := configDir + "/queries.yaml"
+		content := fmt.Sprintf(`queries:
+  - name: "Template Query"
+    enabled: true
+    parameters:
+      ptype: "o"
+    notification:
+      priority: high
+      channels: [slack]
+      template: %s
+`, template)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	cfg, err := config.Load(writeConfig("brief"))
+	if err != nil {
+		t.Fatalf("Expected config with template brief to load: %v", err)
+	}
+	if cfg.Notifications.TemplateDir != templateDir {
+		t.Errorf("Expected template dir %s, got %s", templateDir, cfg.Notifications.TemplateDir)
+	}
+	if _, err := config.Load(writeConfig("unguarded")); err == nil {
+		t.Error("Expected config with a broken template to be rejected")
+	}
+
+	var messages []notify.SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message notify.SlackMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Failed to decode Slack message: %v", err)
+		}
+		messages = append(messages, message)
+	}))
+	defer server.Close()
+
+	deadline := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+	notification := notify.NewNotificationBuilder().
+		WithQuery("Template Query", notify.PriorityHigh).
+		WithTemplate("brief").
+		WithOpportunities([]samgov.Opportunity{{
+			NoticeID:         "TPL-001",
+			Title:            "Radar Maintenance",
+			ResponseDeadline: &deadline,
+			Award:            &samgov.Award{Amount: "1234567.5"},
+		}}).
+		WithSubject("1 New SAM.gov Opportunity").
+		Build()
+
+	notifier := notify.NewSlackNotifier(notify.SlackConfig{Enabled: true, WebhookURL: server.URL}, testing.Verbose())
+	notifier.SetTemplateLibrary(notify.NewTemplateLibrary(cfg.Notifications.TemplateDir))
+	if err := notifier.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Slack notification failed: %v", err)
+	}
+
+	if len(messages) != 1 {
+		t.Fatalf("Expected one Slack message, got %d", len(messages))
+	}
+	expected := "• Radar Maintenance due " + time.Now().AddDate(0, 0, 5).Format("Jan 2, 2006") + " (5 days) $1,234,567.50"
+	if !strings.Contains(messages[0].Text, expected) || len(messages[0].Blocks) != 0 {
+		t.Errorf("Expected the template's text without blocks, got %q", messages[0].Text)
+	}
+}
@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourusername/sam-gov-monitor/internal/notify"
+	"github.com/yourusername/sam-gov-monitor/internal/samgov"
+)
+
+func TestWebhookNotifierIntegration(t *testing.T) {
+	const secret = "integration-secret"
+
+	var payloads []notify.WebhookPayload
+	var keys []string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read webhook body: %v", err)
+		}
+
+		// Fail the first attempt to exercise the retry
+		if attempts == 1 {
+			http.Error(w, "temporarily unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		expected := notify.SignWebhook(secret, r.Header.Get(notify.WebhookTimestampHeader), body)
+		if !hmac.Equal([]byte(expected), []byte(r.Header.Get(notify.WebhookSignatureHeader))) {
+			t.Error("Webhook signature does not match the body")
+		}
+		if r.Header.Get("X-Api-Key") != "crm-key" {
+			t.Errorf("Expected custom header X-Api-Key, got '%s'", r.Header.Get("X-Api-Key"))
+		}
+
+		var payload notify.WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+		payloads = append(payloads, payload)
+		keys = append(keys, r.Header.Get(notify.WebhookIdempotencyHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(notify.WebhookConfig{
+		Enabled:    true,
+		URL:        server.URL,
+		Secret:     secret,
+		Headers:    map[string]string{"X-Api-Key": "crm-key"},
+		MaxRetries: 2,
+		RetryDelay: 10 * time.Millisecond,
+	}, testing.Verbose())
+
+	opportunities := []samgov.Opportunity{
+		{NoticeID: "WEBHOOK-2", Title: "Second Opportunity"},
+		{NoticeID: "WEBHOOK-1", Title: "First Opportunity"},
+	}
+	notification := notify.NewNotificationBuilder().
+		WithQuery("Webhook Integration Query", notify.PriorityMedium).
+		WithOpportunities(opportunities).
+		WithSubject("2 New SAM.gov Opportunities").
+		WithMetadata("query_type", "new").
+		Build()
+
+	if err := notifier.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Webhook notification failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+
+	// The same notices in another order are the same delivery
+	notification.Opportunities = []samgov.Opportunity{opportunities[1], opportunities[0]}
+	if err := notifier.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Second webhook notification failed: %v", err)
+	}
+
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 payloads, got %d", len(payloads))
+	}
+	payload := payloads[0]
+	if payload.Version != notify.WebhookPayloadVersion || payload.Event != "new" {
+		t.Errorf("Unexpected payload version %d, event '%s'", payload.Version, payload.Event)
+	}
+	if len(payload.Opportunities) != 2 || payload.Summary.NewOpportunities != 2 {
+		t.Errorf("Expected 2 opportunities in the payload, got %d", len(payload.Opportunities))
+	}
+	if keys[0] == "" || keys[0] != payload.ID || keys[0] != keys[1] {
+		t.Errorf("Expected a stable idempotency key, got %v (payload id %s)", keys, payload.ID)
+	}
+}